@@ -3,13 +3,18 @@ package main
 import (
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/duanyu/go-blog-system/internal/handler"
 	"github.com/duanyu/go-blog-system/internal/repository"
 	"github.com/duanyu/go-blog-system/internal/service"
 	"github.com/duanyu/go-blog-system/pkg/database"
+	"github.com/duanyu/go-blog-system/pkg/jwtkeys"
 	"github.com/duanyu/go-blog-system/pkg/logger"
+	"github.com/duanyu/go-blog-system/pkg/mailer"
 	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
 
@@ -24,6 +29,17 @@ func main() {
 		log.Fatalf("Failed to initialize logger: %v", err)
 	}
 
+	// 加载JWT签名密钥
+	keys, err := jwtkeys.InitFromViper()
+	if err != nil {
+		log.Fatalf("Failed to load jwt keys: %v", err)
+	}
+
+	// 生产环境拒绝使用默认密钥启动
+	if err := checkProductionSecrets(keys); err != nil {
+		log.Fatalf("Refusing to start: %v", err)
+	}
+
 	// 连接数据库
 	db, err := database.InitFromViper()
 	if err != nil {
@@ -31,6 +47,12 @@ func main() {
 	}
 	defer db.Close()
 
+	// 初始化邮件发送器
+	mail, err := mailer.InitFromViper()
+	if err != nil {
+		log.Fatalf("Failed to initialize mailer: %v", err)
+	}
+
 	// 设置Gin模式
 	mode := viper.GetString("app.mode")
 	if mode == "production" {
@@ -45,28 +67,140 @@ func main() {
 	postRepo := repository.NewPostRepository(db)
 	commentRepo := repository.NewCommentRepository(db)
 	tagRepo := repository.NewTagRepository(db)
+	tokenRepo := repository.NewTokenRepository(db)
+	passwordResetRepo := repository.NewPasswordResetRepository(db)
+	walletRepo := repository.NewWalletRepository(db)
+	recoveryCodeRepo := repository.NewRecoveryCodeRepository(db)
+	personalTokenRepo := repository.NewPersonalAccessTokenRepository(db)
+	loginAttemptRepo := newLoginAttemptRepository(db)
+	dataExportRepo := repository.NewDataExportRepository(db)
+	followRepo := repository.NewFollowRepository(db)
+	postRevisionRepo := repository.NewPostRevisionRepository(db)
+	postTransitionRepo := repository.NewPostTransitionRepository(db)
+	seriesRepo := repository.NewSeriesRepository(db)
+	collectionRepo := repository.NewCollectionRepository(db)
+	reactionRepo := repository.NewReactionRepository(db)
+	bookmarkRepo := repository.NewBookmarkRepository(db)
 
 	// 创建服务
-	userService := service.NewUserService(userRepo)
-	postService := service.NewPostService(postRepo, userRepo, tagRepo)
+	tokenService := service.NewTokenService(tokenRepo, userRepo, keys)
+	loginThrottle := service.NewLoginThrottle(loginAttemptRepo)
+	userService := service.NewUserService(
+		userRepo,
+		passwordResetRepo,
+		walletRepo,
+		postRepo,
+		tokenService,
+		loginThrottle,
+		mail,
+	)
+	nonceLimiter := service.NewRateLimiter(loginAttemptRepo, "siwe-nonce", viper.GetInt("siwe.nonce_rate_limit"), time.Minute)
+	siweService := service.NewSIWEService(walletRepo, userRepo, tokenService, nonceLimiter)
+	mfaService := service.NewMFAService(userRepo, recoveryCodeRepo, tokenService, loginThrottle)
+	personalTokenService := service.NewPersonalAccessTokenService(personalTokenRepo, userRepo)
+	postService := service.NewPostService(postRepo, userRepo, tagRepo, postRevisionRepo, seriesRepo, reactionRepo, bookmarkRepo)
 	commentService := service.NewCommentService(commentRepo, postRepo, userRepo)
 	tagService := service.NewTagService(tagRepo)
+	exportService := service.NewExportService(
+		dataExportRepo,
+		userRepo,
+		postRepo,
+		commentRepo,
+		walletRepo,
+		tokenRepo,
+		personalTokenRepo,
+		followRepo,
+		postRevisionRepo,
+		seriesRepo,
+		collectionRepo,
+		bookmarkRepo,
+		reactionRepo,
+		mail,
+	)
+	followService := service.NewFollowService(followRepo, userRepo, tagRepo, postRepo)
+	postRevisionService := service.NewPostRevisionService(postRevisionRepo, postRepo, userRepo, postService)
+	reviewService := service.NewReviewService(postRepo, userRepo, postTransitionRepo, postService)
+	searchService := service.NewSearchService(postRepo, userRepo)
+	seriesService := service.NewSeriesService(seriesRepo, postRepo, userRepo)
+	collectionService := service.NewCollectionService(collectionRepo, postRepo, userRepo)
+	reactionService := service.NewReactionService(reactionRepo, postRepo, commentRepo)
+	bookmarkService := service.NewBookmarkService(bookmarkRepo, reactionRepo, postRepo, userRepo)
 
 	// 创建处理器
-	userHandler := handler.NewUserHandler(userService)
+	userHandler := handler.NewUserHandler(userService, tokenService)
 	postHandler := handler.NewPostHandler(postService)
 	commentHandler := handler.NewCommentHandler(commentService)
 	tagHandler := handler.NewTagHandler(tagService)
+	siweHandler := handler.NewSIWEHandler(siweService)
+	jwksHandler := handler.NewJWKSHandler(keys)
+	mfaHandler := handler.NewMFAHandler(mfaService)
+	personalTokenHandler := handler.NewPersonalAccessTokenHandler(personalTokenService)
+	sessionHandler := handler.NewSessionHandler(tokenService)
+	exportHandler := handler.NewExportHandler(exportService)
+	followHandler := handler.NewFollowHandler(followService)
+	postRevisionHandler := handler.NewPostRevisionHandler(postRevisionService)
+	reviewHandler := handler.NewReviewHandler(reviewService)
+	searchHandler := handler.NewSearchHandler(searchService)
+	seriesHandler := handler.NewSeriesHandler(seriesService)
+	collectionHandler := handler.NewCollectionHandler(collectionService)
+	reactionHandler := handler.NewReactionHandler(reactionService)
+	bookmarkHandler := handler.NewBookmarkHandler(bookmarkService)
+
+	// 创建认证中间件
+	authMiddleware := handler.AuthMiddleware(tokenService, personalTokenService)
 
 	// 注册路由
+	jwksHandler.RegisterRoutes(r)
+
 	api := r.Group("/api")
 	{
-		userHandler.RegisterRoutes(api)
-		postHandler.RegisterRoutes(api)
-		commentHandler.RegisterRoutes(api)
-		tagHandler.RegisterRoutes(api)
+		userHandler.RegisterRoutes(api, authMiddleware)
+		postHandler.RegisterRoutes(api, authMiddleware)
+		commentHandler.RegisterRoutes(api, authMiddleware)
+		tagHandler.RegisterRoutes(api, authMiddleware)
+		siweHandler.RegisterRoutes(api, authMiddleware)
+		mfaHandler.RegisterRoutes(api, authMiddleware)
+		personalTokenHandler.RegisterRoutes(api, authMiddleware)
+		sessionHandler.RegisterRoutes(api, authMiddleware)
+		exportHandler.RegisterRoutes(api, authMiddleware)
+		followHandler.RegisterRoutes(api, authMiddleware)
+		postRevisionHandler.RegisterRoutes(api, authMiddleware)
+		reviewHandler.RegisterRoutes(api, authMiddleware)
+		searchHandler.RegisterRoutes(api, authMiddleware)
+		seriesHandler.RegisterRoutes(api, authMiddleware)
+		collectionHandler.RegisterRoutes(api, authMiddleware)
+		reactionHandler.RegisterRoutes(api, authMiddleware)
+		bookmarkHandler.RegisterRoutes(api, authMiddleware)
+	}
+
+	// 上次运行中断的导出任务无法继续，标记为失败
+	if err := exportService.FailInterrupted(); err != nil {
+		logrus.Errorf("Failed to clean up interrupted data exports: %v", err)
+	}
+
+	// 为迁移前创建的文章生成slug
+	if count, err := postService.BackfillSlugs(); err != nil {
+		logrus.Errorf("Failed to backfill post slugs: %v", err)
+	} else if count > 0 {
+		logrus.Infof("Generated slugs for %d posts", count)
 	}
 
+	// 渲染迁移前创建或按旧规则渲染的文章内容
+	if count, err := postService.RenderStale(); err != nil {
+		logrus.Errorf("Failed to render post content: %v", err)
+	} else if count > 0 {
+		logrus.Infof("Rendered content for %d posts", count)
+	}
+
+	// 定期清理过期的令牌、登录失败记录和导出文件
+	go purgeExpiredRecords(tokenService, loginThrottle, userService, siweService, exportService)
+
+	// 定期匿名化超过宽限期的注销账号
+	go anonymizeDeletedAccounts(userService)
+
+	// 定时发布到期的文章
+	go publishScheduledPosts(postService)
+
 	// 启动服务器
 	port := viper.GetInt("app.port")
 	if err := r.Run(fmt.Sprintf(":%d", port)); err != nil {
@@ -81,4 +215,113 @@ func loadConfig() error {
 	viper.AddConfigPath("./config")
 
 	return viper.ReadInConfig()
-}
\ No newline at end of file
+}
+
+// defaultSecrets 配置文件中自带的示例密钥
+var defaultSecrets = map[string]string{
+	"app.jwt_secret":     "your-secret-key",
+	"app.signing_secret": "your-signing-secret",
+}
+
+// checkProductionSecrets 生产环境下检查是否仍在使用默认密钥
+func checkProductionSecrets(keys *jwtkeys.KeySet) error {
+	if viper.GetString("app.mode") != "production" {
+		return nil
+	}
+
+	for key, defaultValue := range defaultSecrets {
+		// 使用非对称密钥签名时不再需要jwt_secret
+		if key == "app.jwt_secret" && !keys.IsSymmetric() {
+			continue
+		}
+
+		if value := viper.GetString(key); value == "" || value == defaultValue {
+			return fmt.Errorf("%s must be changed from its default value in production", key)
+		}
+	}
+
+	return nil
+}
+
+// anonymizeDeletedAccounts 定期匿名化超过宽限期的注销账号
+func anonymizeDeletedAccounts(userService service.UserService) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		count, err := userService.AnonymizeDeleted()
+		if err != nil {
+			logrus.Errorf("Failed to anonymize deleted accounts: %v", err)
+		}
+
+		if count > 0 {
+			logrus.Infof("Anonymized %d deleted accounts", count)
+		}
+	}
+}
+
+// publishScheduledPosts 定期发布到期的定时文章，多个实例同时运行时通过行锁避免重复发布
+func publishScheduledPosts(postService service.PostService) {
+	interval := time.Second * time.Duration(viper.GetInt("posts.scheduler_interval"))
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		count, err := postService.PublishDue()
+		if err != nil {
+			logrus.Errorf("Failed to publish scheduled posts: %v", err)
+			continue
+		}
+
+		if count > 0 {
+			logrus.Infof("Published %d scheduled posts", count)
+		}
+	}
+}
+
+// newLoginAttemptRepository 根据配置选择登录失败记录的存储方式，多实例部署时应使用database
+func newLoginAttemptRepository(db *sqlx.DB) repository.LoginAttemptRepository {
+	if viper.GetString("security.login_throttle.store") == "database" {
+		return repository.NewLoginAttemptRepository(db)
+	}
+
+	return repository.NewMemoryLoginAttemptRepository()
+}
+
+// purgeExpiredRecords 定期清理过期的刷新令牌、黑名单、登录失败记录、密码重置令牌、以太坊登录随机数和导出文件
+func purgeExpiredRecords(
+	tokenService service.TokenService,
+	loginThrottle service.LoginThrottle,
+	userService service.UserService,
+	siweService service.SIWEService,
+	exportService service.ExportService,
+) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := tokenService.PurgeExpired(); err != nil {
+			logrus.Errorf("Failed to purge expired tokens: %v", err)
+		}
+
+		if err := loginThrottle.PurgeExpired(); err != nil {
+			logrus.Errorf("Failed to purge expired login attempts: %v", err)
+		}
+
+		if err := userService.PurgeExpiredResets(); err != nil {
+			logrus.Errorf("Failed to purge expired password resets: %v", err)
+		}
+
+		if err := siweService.PurgeExpiredNonces(); err != nil {
+			logrus.Errorf("Failed to purge expired siwe nonces: %v", err)
+		}
+
+		if err := exportService.PurgeExpired(); err != nil {
+			logrus.Errorf("Failed to purge expired data exports: %v", err)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/duanyu/go-blog-system/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// BookmarkHandler 收藏处理器
+type BookmarkHandler struct {
+	bookmarkService service.BookmarkService
+}
+
+// NewBookmarkHandler 创建收藏处理器
+func NewBookmarkHandler(bookmarkService service.BookmarkService) *BookmarkHandler {
+	return &BookmarkHandler{bookmarkService: bookmarkService}
+}
+
+// List 获取收藏的文章
+func (h *BookmarkHandler) List(c *gin.Context) {
+	var query model.PageQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	bookmarks, err := h.bookmarkService.List(GetUserIDFromContext(c), &query)
+	if err != nil {
+		pageError(c, err)
+		return
+	}
+
+	writePage(c, bookmarks, &query)
+}
+
+// Add 收藏文章
+func (h *BookmarkHandler) Add(c *gin.Context) {
+	var req model.BookmarkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.bookmarkService.Add(GetActorFromContext(c), req.PostID); err != nil {
+		if errors.Is(err, service.ErrPostNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "post bookmarked successfully"})
+}
+
+// Remove 取消收藏
+func (h *BookmarkHandler) Remove(c *gin.Context) {
+	postID, err := strconv.Atoi(c.Param("post_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post id"})
+		return
+	}
+
+	if err := h.bookmarkService.Remove(GetUserIDFromContext(c), postID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "bookmark removed successfully"})
+}
+
+// RegisterRoutes 注册路由
+func (h *BookmarkHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	authRouter := router.Group("/profile/bookmarks")
+	authRouter.Use(authMiddleware)
+	{
+		authRouter.GET("", RequireScope(model.ScopeProfileRead), h.List)
+		authRouter.POST("", RequireScope(model.ScopeProfileWrite), h.Add)
+		authRouter.DELETE("/:post_id", RequireScope(model.ScopeProfileWrite), h.Remove)
+	}
+}
@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/duanyu/go-blog-system/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// CollectionHandler 合集处理器
+type CollectionHandler struct {
+	collectionService service.CollectionService
+}
+
+// NewCollectionHandler 创建合集处理器
+func NewCollectionHandler(collectionService service.CollectionService) *CollectionHandler {
+	return &CollectionHandler{collectionService: collectionService}
+}
+
+// Create 创建合集
+func (h *CollectionHandler) Create(c *gin.Context) {
+	var req model.CollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	collection, err := h.collectionService.Create(GetActorFromContext(c), &req)
+	if err != nil {
+		collectionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, collection)
+}
+
+// Get 获取合集及其中的文章
+func (h *CollectionHandler) Get(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid collection id"})
+		return
+	}
+
+	collection, err := h.collectionService.Get(id)
+	if err != nil {
+		collectionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, collection)
+}
+
+// ListByUser 获取用户的合集
+func (h *CollectionHandler) ListByUser(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	collections, err := h.collectionService.ListByUser(userID)
+	if err != nil {
+		collectionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"collections": collections})
+}
+
+// Update 更新合集
+func (h *CollectionHandler) Update(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid collection id"})
+		return
+	}
+
+	var req model.CollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	collection, err := h.collectionService.Update(id, GetActorFromContext(c), &req)
+	if err != nil {
+		collectionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, collection)
+}
+
+// Delete 删除合集
+func (h *CollectionHandler) Delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid collection id"})
+		return
+	}
+
+	if err := h.collectionService.Delete(id, GetActorFromContext(c)); err != nil {
+		collectionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "collection deleted successfully"})
+}
+
+// AddPost 向合集末尾添加文章
+func (h *CollectionHandler) AddPost(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid collection id"})
+		return
+	}
+
+	var req model.AddCollectionPostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	collection, err := h.collectionService.AddPost(id, GetActorFromContext(c), &req)
+	if err != nil {
+		collectionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, collection)
+}
+
+// RemovePost 从合集中移除文章
+func (h *CollectionHandler) RemovePost(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid collection id"})
+		return
+	}
+
+	postID, err := strconv.Atoi(c.Param("post_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post id"})
+		return
+	}
+
+	collection, err := h.collectionService.RemovePost(id, GetActorFromContext(c), postID)
+	if err != nil {
+		collectionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, collection)
+}
+
+// Reorder 调整合集中文章的顺序
+func (h *CollectionHandler) Reorder(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid collection id"})
+		return
+	}
+
+	var req model.ReorderPostsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	collection, err := h.collectionService.Reorder(id, GetActorFromContext(c), req.PostIDs)
+	if err != nil {
+		collectionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, collection)
+}
+
+// collectionError 将合集服务的错误映射为HTTP状态码
+func collectionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrCollectionNotFound), errors.Is(err, service.ErrPostNotFound),
+		errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrCollectionAccessDenied):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrPostNotPublished), errors.Is(err, service.ErrCollectionFull),
+		errors.Is(err, model.ErrInvalidPostOrder):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrPostAlreadyInCollection):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// RegisterRoutes 注册路由
+func (h *CollectionHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	router.GET("/collections/:id", h.Get)
+	router.GET("/users/:id/collections", h.ListByUser)
+
+	collectionRouter := router.Group("/collections")
+	collectionRouter.Use(authMiddleware, RequireScope(model.ScopeProfileWrite))
+	{
+		collectionRouter.POST("", h.Create)
+		collectionRouter.PUT("/:id", h.Update)
+		collectionRouter.DELETE("/:id", h.Delete)
+		collectionRouter.POST("/:id/posts", h.AddPost)
+		collectionRouter.PUT("/:id/posts", h.Reorder)
+		collectionRouter.DELETE("/:id/posts/:post_id", h.RemovePost)
+	}
+}
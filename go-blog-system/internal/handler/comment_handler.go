@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -31,6 +32,10 @@ func (h *CommentHandler) Create(c *gin.Context) {
 
 	comment, err := h.commentService.Create(userID, &req)
 	if err != nil {
+		if errors.Is(err, service.ErrEmailNotVerified) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -81,14 +86,14 @@ func (h *CommentHandler) Update(c *gin.Context) {
 
 // Delete 删除评论
 func (h *CommentHandler) Delete(c *gin.Context) {
-	userID := GetUserIDFromContext(c)
+	actor := GetActorFromContext(c)
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid comment id"})
 		return
 	}
 
-	if err := h.commentService.Delete(id, userID); err != nil {
+	if err := h.commentService.Delete(id, actor); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -96,7 +101,7 @@ func (h *CommentHandler) Delete(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "comment deleted successfully"})
 }
 
-// GetByPost 获取文章的所有评论
+// GetByPost 分页获取文章的评论
 func (h *CommentHandler) GetByPost(c *gin.Context) {
 	postID, err := strconv.Atoi(c.Param("post_id"))
 	if err != nil {
@@ -104,25 +109,31 @@ func (h *CommentHandler) GetByPost(c *gin.Context) {
 		return
 	}
 
-	comments, err := h.commentService.GetByPostID(postID)
+	var query model.PageQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	comments, err := h.commentService.GetByPostID(postID, &query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		pageError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, comments)
+	writePage(c, comments, &query)
 }
 
 // RegisterRoutes 注册路由
-func (h *CommentHandler) RegisterRoutes(router *gin.RouterGroup) {
+func (h *CommentHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
 	router.GET("/posts/comments/:post_id", h.GetByPost)
 	router.GET("/comments/:id", h.Get)
 
 	authRouter := router.Group("/")
-	authRouter.Use(AuthMiddleware())
+	authRouter.Use(authMiddleware)
 	{
-		authRouter.POST("/comments", h.Create)
-		authRouter.PUT("/comments/:id", h.Update)
-		authRouter.DELETE("/comments/:id", h.Delete)
+		authRouter.POST("/comments", RequireScope(model.ScopeCommentsWrite), RequirePermission(model.PermissionCreateComment), h.Create)
+		authRouter.PUT("/comments/:id", RequireScope(model.ScopeCommentsWrite), h.Update)
+		authRouter.DELETE("/comments/:id", RequireScope(model.ScopeCommentsWrite), h.Delete)
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/duanyu/go-blog-system/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// ExportHandler 个人数据导出处理器
+type ExportHandler struct {
+	exportService service.ExportService
+}
+
+// NewExportHandler 创建个人数据导出处理器
+func NewExportHandler(exportService service.ExportService) *ExportHandler {
+	return &ExportHandler{exportService: exportService}
+}
+
+// Request 申请导出个人数据
+func (h *ExportHandler) Request(c *gin.Context) {
+	userID := GetUserIDFromContext(c)
+
+	export, err := h.exportService.Request(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, export)
+}
+
+// Get 查询导出任务状态
+func (h *ExportHandler) Get(c *gin.Context) {
+	userID := GetUserIDFromContext(c)
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid export id"})
+		return
+	}
+
+	export, err := h.exportService.Get(userID, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, export)
+}
+
+// Download 通过限时链接下载导出文件
+func (h *ExportHandler) Download(c *gin.Context) {
+	file, err := h.exportService.Download(c.Query("token"))
+	if err != nil {
+		if errors.Is(err, service.ErrExportNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.FileAttachment(file.Path, file.Filename)
+}
+
+// RegisterRoutes 注册路由
+func (h *ExportHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	router.GET("/exports/download", h.Download)
+
+	authRouter := router.Group("/")
+	authRouter.Use(authMiddleware, RequireSession())
+	{
+		authRouter.POST("/profile/export", h.Request)
+		authRouter.GET("/profile/export/:id", h.Get)
+	}
+}
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/duanyu/go-blog-system/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// FollowHandler 关注处理器
+type FollowHandler struct {
+	followService service.FollowService
+}
+
+// NewFollowHandler 创建关注处理器
+func NewFollowHandler(followService service.FollowService) *FollowHandler {
+	return &FollowHandler{followService: followService}
+}
+
+// FollowUser 关注用户
+func (h *FollowHandler) FollowUser(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	if err := h.followService.FollowUser(GetUserIDFromContext(c), id); err != nil {
+		switch {
+		case errors.Is(err, service.ErrCannotFollowSelf):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "user followed successfully"})
+}
+
+// UnfollowUser 取消关注用户
+func (h *FollowHandler) UnfollowUser(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	if err := h.followService.UnfollowUser(GetUserIDFromContext(c), id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "user unfollowed successfully"})
+}
+
+// FollowTag 关注标签
+func (h *FollowHandler) FollowTag(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tag id"})
+		return
+	}
+
+	if err := h.followService.FollowTag(GetUserIDFromContext(c), id); err != nil {
+		if errors.Is(err, service.ErrTagNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "tag followed successfully"})
+}
+
+// UnfollowTag 取消关注标签
+func (h *FollowHandler) UnfollowTag(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tag id"})
+		return
+	}
+
+	if err := h.followService.UnfollowTag(GetUserIDFromContext(c), id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "tag unfollowed successfully"})
+}
+
+// Feed 获取个人动态流
+func (h *FollowHandler) Feed(c *gin.Context) {
+	var query model.PageQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	feed, err := h.followService.Feed(GetUserIDFromContext(c), &query)
+	if err != nil {
+		pageError(c, err)
+		return
+	}
+
+	writePage(c, feed, &query)
+}
+
+// RegisterRoutes 注册路由
+func (h *FollowHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	router.GET("/feed", authMiddleware, RequireScope(model.ScopePostsRead), h.Feed)
+
+	followRouter := router.Group("/")
+	followRouter.Use(authMiddleware, RequireScope(model.ScopeProfileWrite))
+	{
+		followRouter.POST("/users/:id/follow", h.FollowUser)
+		followRouter.DELETE("/users/:id/follow", h.UnfollowUser)
+		followRouter.POST("/tags/:id/follow", h.FollowTag)
+		followRouter.DELETE("/tags/:id/follow", h.UnfollowTag)
+	}
+}
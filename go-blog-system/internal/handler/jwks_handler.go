@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/duanyu/go-blog-system/pkg/jwtkeys"
+	"github.com/gin-gonic/gin"
+)
+
+// JWKSHandler 公钥发布处理器
+type JWKSHandler struct {
+	keys *jwtkeys.KeySet
+}
+
+// NewJWKSHandler 创建公钥发布处理器
+func NewJWKSHandler(keys *jwtkeys.KeySet) *JWKSHandler {
+	return &JWKSHandler{keys: keys}
+}
+
+// JWKS 返回用于验证访问令牌的公钥集合
+func (h *JWKSHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.keys.JWKS())
+}
+
+// RegisterRoutes 注册路由
+func (h *JWKSHandler) RegisterRoutes(router gin.IRoutes) {
+	router.GET("/.well-known/jwks.json", h.JWKS)
+}
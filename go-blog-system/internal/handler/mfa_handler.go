@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/duanyu/go-blog-system/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// MFAHandler 两步验证处理器
+type MFAHandler struct {
+	mfaService service.MFAService
+}
+
+// NewMFAHandler 创建两步验证处理器
+func NewMFAHandler(mfaService service.MFAService) *MFAHandler {
+	return &MFAHandler{mfaService: mfaService}
+}
+
+// Login 使用质询令牌和验证码完成登录
+func (h *MFAHandler) Login(c *gin.Context) {
+	var req model.MFALoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.Client = GetClientInfo(c)
+
+	resp, err := h.mfaService.CompleteLogin(&req)
+	if err != nil {
+		var throttled *service.LoginThrottledError
+		if errors.As(err, &throttled) {
+			c.Header("Retry-After", strconv.Itoa(throttled.RetryAfterSeconds()))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Status 获取两步验证状态
+func (h *MFAHandler) Status(c *gin.Context) {
+	userID := GetUserIDFromContext(c)
+
+	status, err := h.mfaService.Status(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// Setup 开始绑定认证器
+func (h *MFAHandler) Setup(c *gin.Context) {
+	userID := GetUserIDFromContext(c)
+
+	setup, err := h.mfaService.Setup(userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, setup)
+}
+
+// Confirm 确认绑定并开启两步验证
+func (h *MFAHandler) Confirm(c *gin.Context) {
+	userID := GetUserIDFromContext(c)
+
+	var req model.TOTPCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	codes, err := h.mfaService.Confirm(userID, req.Code)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// Disable 关闭两步验证
+func (h *MFAHandler) Disable(c *gin.Context) {
+	userID := GetUserIDFromContext(c)
+
+	var req model.DisableTOTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.mfaService.Disable(userID, &req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "two-factor authentication disabled"})
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码
+func (h *MFAHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	userID := GetUserIDFromContext(c)
+
+	var req model.TOTPCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	codes, err := h.mfaService.RegenerateRecoveryCodes(userID, req.Code)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// RegisterRoutes 注册路由
+func (h *MFAHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	router.POST("/login/mfa", h.Login)
+
+	authRouter := router.Group("/")
+	authRouter.Use(authMiddleware, RequireSession())
+	{
+		authRouter.GET("/profile/2fa", h.Status)
+		authRouter.POST("/profile/2fa/setup", h.Setup)
+		authRouter.POST("/profile/2fa/confirm", h.Confirm)
+		authRouter.POST("/profile/2fa/disable", h.Disable)
+		authRouter.POST("/profile/2fa/recovery-codes", h.RegenerateRecoveryCodes)
+	}
+}
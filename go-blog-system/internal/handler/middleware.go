@@ -1,21 +1,26 @@
 package handler
 
 import (
-	"errors"
-	"fmt"
 	"net/http"
 	"strings"
 
-	"github.com/dgrijalva/jwt-go"
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/duanyu/go-blog-system/internal/service"
 	"github.com/gin-gonic/gin"
 	"github.com/spf13/viper"
 )
 
-// userIDKey 用户ID上下文键
-const userIDKey = "user_id"
+const (
+	// userIDKey 用户ID上下文键
+	userIDKey = "user_id"
+	// roleKey 用户角色上下文键
+	roleKey = "role"
+	// claimsKey 令牌声明上下文键
+	claimsKey = "claims"
+)
 
-// AuthMiddleware JWT认证中间件
-func AuthMiddleware() gin.HandlerFunc {
+// AuthMiddleware 认证中间件，同时接受JWT访问令牌和个人访问令牌
+func AuthMiddleware(tokenService service.TokenService, personalTokenService service.PersonalAccessTokenService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		tokenString := c.GetHeader("Authorization")
 		if tokenString == "" {
@@ -29,20 +34,118 @@ func AuthMiddleware() gin.HandlerFunc {
 			tokenString = strings.TrimPrefix(tokenString, "Bearer ")
 		}
 
-		// 解析JWT令牌
-		userID, err := parseJWT(tokenString)
+		var (
+			claims *service.AccessClaims
+			err    error
+		)
+		if strings.HasPrefix(tokenString, model.PersonalAccessTokenPrefix) {
+			claims, err = personalTokenService.Authenticate(tokenString, c.ClientIP())
+		} else {
+			// 解析JWT令牌（同时检查黑名单和会话吊销状态）
+			claims, err = tokenService.ParseAccessToken(tokenString)
+			if err == nil {
+				err = tokenService.TouchSession(claims.SessionID, c.ClientIP())
+			}
+		}
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 			c.Abort()
 			return
 		}
 
-		// 将用户ID存储在上下文中
-		c.Set(userIDKey, userID)
+		// 将用户ID和令牌声明存储在上下文中
+		c.Set(userIDKey, claims.UserID)
+		c.Set(roleKey, effectiveRole(claims))
+		c.Set(claimsKey, claims)
+		c.Next()
+	}
+}
+
+// OptionalAuth 可选认证：携带Authorization头时按authMiddleware认证，否则以匿名身份继续
+func OptionalAuth(authMiddleware gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+
+		authMiddleware(c)
+	}
+}
+
+// RequireRole 角色校验中间件，需在AuthMiddleware之后使用
+func RequireRole(roles ...model.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := GetRoleFromContext(c)
+		for _, r := range roles {
+			if role == r {
+				c.Next()
+				return
+			}
+		}
+
+		c.JSON(http.StatusForbidden, gin.H{"error": forbiddenMessage(c, "insufficient role")})
+		c.Abort()
+	}
+}
+
+// RequirePermission 权限校验中间件，需在AuthMiddleware之后使用
+func RequirePermission(permission model.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !GetRoleFromContext(c).Can(permission) {
+			c.JSON(http.StatusForbidden, gin.H{"error": forbiddenMessage(c, "permission denied: "+string(permission))})
+			c.Abort()
+			return
+		}
+
 		c.Next()
 	}
 }
 
+// RequireScope 授权范围校验中间件，个人访问令牌必须拥有指定授权范围，需在AuthMiddleware之后使用
+func RequireScope(scope model.Scope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if claims := GetClaimsFromContext(c); claims == nil || !claims.HasScope(scope) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "token is missing required scope: " + string(scope)})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireSession 要求通过登录会话认证，用于拒绝个人访问令牌访问账号安全相关接口
+func RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if claims := GetClaimsFromContext(c); claims == nil || claims.IsPersonalToken() {
+			c.JSON(http.StatusForbidden, gin.H{"error": "this endpoint cannot be used with a personal access token"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// effectiveRole 计算令牌的有效角色：强制管理员两步验证时，未通过两步验证的管理员按作者处理
+func effectiveRole(claims *service.AccessClaims) model.Role {
+	if claims.Role == model.RoleAdmin && !claims.MFA && viper.GetBool("security.require_admin_mfa") {
+		return model.RoleAuthor
+	}
+
+	return claims.Role
+}
+
+// forbiddenMessage 管理员因未通过两步验证被降权时给出明确提示
+func forbiddenMessage(c *gin.Context, message string) string {
+	if claims := GetClaimsFromContext(c); claims != nil && claims.Role != GetRoleFromContext(c) {
+		return "admin privileges require two-factor authentication, please enable it and log in again"
+	}
+
+	return message
+}
+
 // GetUserIDFromContext 从上下文中获取用户ID
 func GetUserIDFromContext(c *gin.Context) int {
 	userID, exists := c.Get(userIDKey)
@@ -53,32 +156,38 @@ func GetUserIDFromContext(c *gin.Context) int {
 	return userID.(int)
 }
 
-// parseJWT 解析JWT令牌
-func parseJWT(tokenString string) (int, error) {
-	jwtSecret := viper.GetString("app.jwt_secret")
-
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		// 验证签名方法
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
+// GetRoleFromContext 从上下文中获取用户角色
+func GetRoleFromContext(c *gin.Context) model.Role {
+	role, exists := c.Get(roleKey)
+	if !exists {
+		return ""
+	}
 
-		return []byte(jwtSecret), nil
-	})
+	return role.(model.Role)
+}
 
-	if err != nil {
-		return 0, fmt.Errorf("invalid token: %w", err)
+// GetActorFromContext 从上下文中获取当前操作者
+func GetActorFromContext(c *gin.Context) model.Actor {
+	return model.Actor{
+		UserID: GetUserIDFromContext(c),
+		Role:   GetRoleFromContext(c),
 	}
+}
 
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok || !token.Valid {
-		return 0, errors.New("invalid token claims")
+// GetClientInfo 获取发起请求的客户端信息
+func GetClientInfo(c *gin.Context) model.ClientInfo {
+	return model.ClientInfo{
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
 	}
+}
 
-	userID, ok := claims["user_id"].(float64)
-	if !ok {
-		return 0, errors.New("invalid user id in token")
+// GetClaimsFromContext 从上下文中获取令牌声明
+func GetClaimsFromContext(c *gin.Context) *service.AccessClaims {
+	claims, exists := c.Get(claimsKey)
+	if !exists {
+		return nil
 	}
 
-	return int(userID), nil
-}
\ No newline at end of file
+	return claims.(*service.AccessClaims)
+}
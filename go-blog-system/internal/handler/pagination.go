@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+// writePage 返回分页结果，并按RFC 8288在Link头中给出相邻页的链接
+func writePage[T any](c *gin.Context, page *model.Page[T], query *model.PageQuery) {
+	if links := pageLinks(c.Request.URL, query, page.NextCursor, page.PrevCursor, page.Total); len(links) > 0 {
+		c.Header("Link", strings.Join(links, ", "))
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// pageError 将分页查询的错误转换为HTTP响应
+func pageError(c *gin.Context, err error) {
+	if errors.Is(err, model.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": model.ErrInvalidCursor.Error()})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+// pageLinks 生成first、prev、next和last链接。按页码分页时链接使用page参数，
+// 游标分页时使用cursor参数（游标分页不提供last链接）
+func pageLinks(u *url.URL, query *model.PageQuery, next, prev string, total *int) []string {
+	link := func(rel string, set func(v url.Values)) string {
+		v := u.Query()
+		v.Del("cursor")
+		v.Del("page")
+		v.Set("per_page", strconv.Itoa(query.PerPage))
+		set(v)
+		return fmt.Sprintf(`<%s?%s>; rel="%s"`, u.Path, v.Encode(), rel)
+	}
+
+	var links []string
+	if query.UseOffset() {
+		setPage := func(page int) func(v url.Values) {
+			return func(v url.Values) { v.Set("page", strconv.Itoa(page)) }
+		}
+
+		links = append(links, link("first", setPage(1)))
+		if query.Page > 1 {
+			links = append(links, link("prev", setPage(query.Page-1)))
+		}
+		if next != "" {
+			links = append(links, link("next", setPage(query.Page+1)))
+		}
+		if total != nil {
+			last := (*total + query.PerPage - 1) / query.PerPage
+			if last < 1 {
+				last = 1
+			}
+			links = append(links, link("last", setPage(last)))
+		}
+		return links
+	}
+
+	setCursor := func(cursor string) func(v url.Values) {
+		return func(v url.Values) { v.Set("cursor", cursor) }
+	}
+
+	links = append(links, link("first", func(v url.Values) {}))
+	if prev != "" {
+		links = append(links, link("prev", setCursor(prev)))
+	}
+	if next != "" {
+		links = append(links, link("next", setCursor(next)))
+	}
+
+	return links
+}
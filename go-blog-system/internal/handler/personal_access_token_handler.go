@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/duanyu/go-blog-system/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// PersonalAccessTokenHandler 个人访问令牌处理器
+type PersonalAccessTokenHandler struct {
+	personalTokenService service.PersonalAccessTokenService
+}
+
+// NewPersonalAccessTokenHandler 创建个人访问令牌处理器
+func NewPersonalAccessTokenHandler(personalTokenService service.PersonalAccessTokenService) *PersonalAccessTokenHandler {
+	return &PersonalAccessTokenHandler{personalTokenService: personalTokenService}
+}
+
+// Create 创建个人访问令牌
+func (h *PersonalAccessTokenHandler) Create(c *gin.Context) {
+	userID := GetUserIDFromContext(c)
+
+	var req model.CreatePersonalAccessTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	token, err := h.personalTokenService.Create(userID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, token)
+}
+
+// List 获取个人访问令牌列表
+func (h *PersonalAccessTokenHandler) List(c *gin.Context) {
+	userID := GetUserIDFromContext(c)
+
+	tokens, err := h.personalTokenService.List(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// Revoke 吊销个人访问令牌
+func (h *PersonalAccessTokenHandler) Revoke(c *gin.Context) {
+	userID := GetUserIDFromContext(c)
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid token id"})
+		return
+	}
+
+	if err := h.personalTokenService.Revoke(userID, id); err != nil {
+		if errors.Is(err, service.ErrPersonalTokenNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "personal access token revoked"})
+}
+
+// RegisterRoutes 注册路由
+func (h *PersonalAccessTokenHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	authRouter := router.Group("/")
+	authRouter.Use(authMiddleware, RequireSession())
+	{
+		authRouter.GET("/profile/tokens", h.List)
+		authRouter.POST("/profile/tokens", h.Create)
+		authRouter.DELETE("/profile/tokens/:id", h.Revoke)
+	}
+}
@@ -1,7 +1,9 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
+	"net/url"
 	"strconv"
 
 	"github.com/duanyu/go-blog-system/internal/model"
@@ -21,7 +23,7 @@ func NewPostHandler(postService service.PostService) *PostHandler {
 
 // Create 创建文章
 func (h *PostHandler) Create(c *gin.Context) {
-	userID := GetUserIDFromContext(c)
+	actor := GetActorFromContext(c)
 
 	var req model.CreatePostRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -29,9 +31,18 @@ func (h *PostHandler) Create(c *gin.Context) {
 		return
 	}
 
-	post, err := h.postService.Create(userID, &req)
+	post, err := h.postService.Create(actor, &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		switch {
+		case errors.Is(err, service.ErrEmailNotVerified), errors.Is(err, service.ErrTransitionNotAllowed):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrInvalidSlug), errors.Is(err, service.ErrInvalidPublishTime):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrSlugTaken):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
 		return
 	}
 
@@ -54,7 +65,7 @@ func (h *PostHandler) Get(c *gin.Context) {
 		}
 	}
 
-	post, err := h.postService.GetByID(id)
+	post, err := h.postService.GetByID(id, GetActorFromContext(c))
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "post not found"})
 		return
@@ -63,9 +74,40 @@ func (h *PostHandler) Get(c *gin.Context) {
 	c.JSON(http.StatusOK, post)
 }
 
+// GetBySlug 根据slug获取文章，旧slug永久重定向到当前slug
+func (h *PostHandler) GetBySlug(c *gin.Context) {
+	slug := c.Param("slug")
+
+	post, err := h.postService.GetBySlug(slug, GetActorFromContext(c))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "post not found"})
+		return
+	}
+
+	if post.Slug != nil && *post.Slug != slug {
+		location := "/api/posts/by-slug/" + url.PathEscape(*post.Slug)
+		if c.Request.URL.RawQuery != "" {
+			location += "?" + c.Request.URL.RawQuery
+		}
+		c.Redirect(http.StatusMovedPermanently, location)
+		return
+	}
+
+	// 增加浏览量
+	if c.Query("view") == "true" {
+		if err := h.postService.IncrementViewCount(post.ID); err != nil {
+			// 记录错误但不中断请求
+			c.Error(err)
+		}
+		post.ViewCount++
+	}
+
+	c.JSON(http.StatusOK, post)
+}
+
 // Update 更新文章
 func (h *PostHandler) Update(c *gin.Context) {
-	userID := GetUserIDFromContext(c)
+	actor := GetActorFromContext(c)
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post id"})
@@ -78,9 +120,18 @@ func (h *PostHandler) Update(c *gin.Context) {
 		return
 	}
 
-	post, err := h.postService.Update(id, userID, &req)
+	post, err := h.postService.Update(id, actor, &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		switch {
+		case errors.Is(err, service.ErrInvalidSlug), errors.Is(err, service.ErrInvalidPublishTime):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrSlugTaken):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrTransitionNotAllowed):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
 		return
 	}
 
@@ -89,14 +140,14 @@ func (h *PostHandler) Update(c *gin.Context) {
 
 // Delete 删除文章
 func (h *PostHandler) Delete(c *gin.Context) {
-	userID := GetUserIDFromContext(c)
+	actor := GetActorFromContext(c)
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post id"})
 		return
 	}
 
-	if err := h.postService.Delete(id, userID); err != nil {
+	if err := h.postService.Delete(id, actor); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -112,40 +163,34 @@ func (h *PostHandler) List(c *gin.Context) {
 		return
 	}
 
-	// 设置默认值
-	if query.Page <= 0 {
-		query.Page = 1
-	}
-	if query.PerPage <= 0 {
-		query.PerPage = 10
-	}
-
-	posts, total, err := h.postService.List(&query)
+	posts, err := h.postService.List(&query, GetActorFromContext(c))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		if errors.Is(err, model.ErrInvalidFilter) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrPostStatusNotAllowed) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		pageError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"posts": posts,
-		"meta": gin.H{
-			"total":    total,
-			"page":     query.Page,
-			"per_page": query.PerPage,
-		},
-	})
+	writePage(c, posts, &query.PageQuery)
 }
 
 // RegisterRoutes 注册路由
-func (h *PostHandler) RegisterRoutes(router *gin.RouterGroup) {
-	router.GET("/posts", h.List)
-	router.GET("/posts/:id", h.Get)
+func (h *PostHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	router.GET("/posts", OptionalAuth(authMiddleware), h.List)
+	router.GET("/posts/:id", OptionalAuth(authMiddleware), h.Get)
+	router.GET("/posts/by-slug/:slug", OptionalAuth(authMiddleware), h.GetBySlug)
 
 	authRouter := router.Group("/")
-	authRouter.Use(AuthMiddleware())
+	authRouter.Use(authMiddleware)
 	{
-		authRouter.POST("/posts", h.Create)
-		authRouter.PUT("/posts/:id", h.Update)
-		authRouter.DELETE("/posts/:id", h.Delete)
+		authRouter.POST("/posts", RequireScope(model.ScopePostsWrite), RequirePermission(model.PermissionCreatePost), h.Create)
+		authRouter.PUT("/posts/:id", RequireScope(model.ScopePostsWrite), h.Update)
+		authRouter.DELETE("/posts/:id", RequireScope(model.ScopePostsWrite), h.Delete)
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/duanyu/go-blog-system/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// PostRevisionHandler 文章修订版本处理器
+type PostRevisionHandler struct {
+	revisionService service.PostRevisionService
+}
+
+// NewPostRevisionHandler 创建文章修订版本处理器
+func NewPostRevisionHandler(revisionService service.PostRevisionService) *PostRevisionHandler {
+	return &PostRevisionHandler{revisionService: revisionService}
+}
+
+// List 获取文章的修订历史
+func (h *PostRevisionHandler) List(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post id"})
+		return
+	}
+
+	revisions, err := h.revisionService.List(id, GetActorFromContext(c))
+	if err != nil {
+		revisionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"revisions": revisions})
+}
+
+// Get 获取指定修订版本
+func (h *PostRevisionHandler) Get(c *gin.Context) {
+	id, rev, ok := revisionParams(c)
+	if !ok {
+		return
+	}
+
+	revision, err := h.revisionService.Get(id, rev, GetActorFromContext(c))
+	if err != nil {
+		revisionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, revision)
+}
+
+// Diff 比较两个修订版本
+func (h *PostRevisionHandler) Diff(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post id"})
+		return
+	}
+
+	var query model.RevisionDiffQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.revisionService.Diff(id, GetActorFromContext(c), &query)
+	if err != nil {
+		revisionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Restore 恢复到指定修订版本
+func (h *PostRevisionHandler) Restore(c *gin.Context) {
+	id, rev, ok := revisionParams(c)
+	if !ok {
+		return
+	}
+
+	post, err := h.revisionService.Restore(id, rev, GetActorFromContext(c))
+	if err != nil {
+		revisionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, post)
+}
+
+// revisionParams 解析文章ID和修订版本号
+func revisionParams(c *gin.Context) (int, int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post id"})
+		return 0, 0, false
+	}
+
+	rev, err := strconv.Atoi(c.Param("rev"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid revision"})
+		return 0, 0, false
+	}
+
+	return id, rev, true
+}
+
+// revisionError 将修订版本服务的错误转换为HTTP响应
+func revisionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrPostNotFound), errors.Is(err, service.ErrRevisionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrPostHistoryAccessDenied), errors.Is(err, service.ErrTransitionNotAllowed):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// RegisterRoutes 注册路由
+func (h *PostRevisionHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	revisionRouter := router.Group("/posts/:id/revisions")
+	revisionRouter.Use(authMiddleware)
+	{
+		revisionRouter.GET("", RequireScope(model.ScopePostsRead), h.List)
+		revisionRouter.GET("/diff", RequireScope(model.ScopePostsRead), h.Diff)
+		revisionRouter.GET("/:rev", RequireScope(model.ScopePostsRead), h.Get)
+		revisionRouter.POST("/:rev/restore", RequireScope(model.ScopePostsWrite), h.Restore)
+	}
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/duanyu/go-blog-system/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// ReactionHandler 表情回应处理器
+type ReactionHandler struct {
+	reactionService service.ReactionService
+}
+
+// NewReactionHandler 创建表情回应处理器
+func NewReactionHandler(reactionService service.ReactionService) *ReactionHandler {
+	return &ReactionHandler{reactionService: reactionService}
+}
+
+// reactionFunc 添加或取消回应的服务方法
+type reactionFunc func(id int, actor model.Actor, reaction model.Reaction) (*model.ReactionsResponse, error)
+
+// Options 获取可用的表情回应
+func (h *ReactionHandler) Options(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"reactions": model.ReactionOptions()})
+}
+
+// ReactToPost 对文章添加回应
+func (h *ReactionHandler) ReactToPost(c *gin.Context) {
+	h.react(c, "invalid post id", h.reactionService.ReactToPost)
+}
+
+// UnreactToPost 取消对文章的回应
+func (h *ReactionHandler) UnreactToPost(c *gin.Context) {
+	h.unreact(c, "invalid post id", h.reactionService.UnreactToPost)
+}
+
+// ReactToComment 对评论添加回应
+func (h *ReactionHandler) ReactToComment(c *gin.Context) {
+	h.react(c, "invalid comment id", h.reactionService.ReactToComment)
+}
+
+// UnreactToComment 取消对评论的回应
+func (h *ReactionHandler) UnreactToComment(c *gin.Context) {
+	h.unreact(c, "invalid comment id", h.reactionService.UnreactToComment)
+}
+
+// react 从请求体读取回应并添加
+func (h *ReactionHandler) react(c *gin.Context, invalidID string, fn reactionFunc) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": invalidID})
+		return
+	}
+
+	var req model.ReactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	reactions, err := fn(id, GetActorFromContext(c), req.Reaction)
+	if err != nil {
+		reactionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reactions)
+}
+
+// unreact 取消路径中指定的回应
+func (h *ReactionHandler) unreact(c *gin.Context, invalidID string, fn reactionFunc) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": invalidID})
+		return
+	}
+
+	reactions, err := fn(id, GetActorFromContext(c), model.Reaction(c.Param("reaction")))
+	if err != nil {
+		reactionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reactions)
+}
+
+// reactionError 将表情回应服务的错误映射为HTTP状态码
+func reactionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidReaction):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrPostNotFound), errors.Is(err, service.ErrCommentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// RegisterRoutes 注册路由
+func (h *ReactionHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	router.GET("/reactions", h.Options)
+
+	authRouter := router.Group("/")
+	authRouter.Use(authMiddleware, RequireScope(model.ScopeProfileWrite))
+	{
+		authRouter.POST("/posts/:id/reactions", h.ReactToPost)
+		authRouter.DELETE("/posts/:id/reactions/:reaction", h.UnreactToPost)
+		authRouter.POST("/comments/:id/reactions", h.ReactToComment)
+		authRouter.DELETE("/comments/:id/reactions/:reaction", h.UnreactToComment)
+	}
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/duanyu/go-blog-system/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// ReviewHandler 文章审核处理器
+type ReviewHandler struct {
+	reviewService service.ReviewService
+}
+
+// NewReviewHandler 创建文章审核处理器
+func NewReviewHandler(reviewService service.ReviewService) *ReviewHandler {
+	return &ReviewHandler{reviewService: reviewService}
+}
+
+// Approve 审核通过
+func (h *ReviewHandler) Approve(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post id"})
+		return
+	}
+
+	var req model.ReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	post, err := h.reviewService.Approve(id, GetActorFromContext(c), req.Comment)
+	if err != nil {
+		reviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, post)
+}
+
+// RequestChanges 要求修改
+func (h *ReviewHandler) RequestChanges(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post id"})
+		return
+	}
+
+	var req model.RequestChangesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	post, err := h.reviewService.RequestChanges(id, GetActorFromContext(c), req.Comment)
+	if err != nil {
+		reviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, post)
+}
+
+// Queue 获取审核队列
+func (h *ReviewHandler) Queue(c *gin.Context) {
+	var query model.ReviewQueueQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	posts, total, err := h.reviewService.Queue(&query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"posts": posts,
+		"meta": gin.H{
+			"total":    total,
+			"page":     query.Page,
+			"per_page": query.PerPage,
+		},
+	})
+}
+
+// History 获取文章的状态变更历史
+func (h *ReviewHandler) History(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post id"})
+		return
+	}
+
+	transitions, err := h.reviewService.History(id, GetActorFromContext(c))
+	if err != nil {
+		reviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"transitions": transitions})
+}
+
+// reviewError 将审核服务的错误转换为HTTP响应
+func reviewError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrPostNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrPostNotInReview):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrPostHistoryAccessDenied):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// RegisterRoutes 注册路由
+func (h *ReviewHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	authRouter := router.Group("/")
+	authRouter.Use(authMiddleware)
+	{
+		authRouter.GET("/posts/:id/transitions", RequireScope(model.ScopePostsRead), h.History)
+	}
+
+	reviewRouter := router.Group("/")
+	reviewRouter.Use(authMiddleware, RequirePermission(model.PermissionReviewPosts))
+	{
+		reviewRouter.GET("/review/queue", RequireScope(model.ScopePostsRead), h.Queue)
+		reviewRouter.POST("/posts/:id/review/approve", RequireScope(model.ScopePostsWrite), h.Approve)
+		reviewRouter.POST("/posts/:id/review/request-changes", RequireScope(model.ScopePostsWrite), h.RequestChanges)
+	}
+}
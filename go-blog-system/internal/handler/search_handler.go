@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/duanyu/go-blog-system/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// SearchHandler 搜索处理器
+type SearchHandler struct {
+	searchService service.SearchService
+}
+
+// NewSearchHandler 创建搜索处理器
+func NewSearchHandler(searchService service.SearchService) *SearchHandler {
+	return &SearchHandler{searchService: searchService}
+}
+
+// Search 全文搜索文章
+func (h *SearchHandler) Search(c *gin.Context) {
+	var query model.SearchQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hits, total, err := h.searchService.Search(&query, GetActorFromContext(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidSearchQuery):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrPostStatusNotAllowed):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"hits": hits,
+		"meta": gin.H{
+			"total":    total,
+			"page":     query.Page,
+			"per_page": query.PerPage,
+		},
+	})
+}
+
+// RegisterRoutes 注册路由
+func (h *SearchHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	router.GET("/search", OptionalAuth(authMiddleware), h.Search)
+}
@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/duanyu/go-blog-system/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// SeriesHandler 系列处理器
+type SeriesHandler struct {
+	seriesService service.SeriesService
+}
+
+// NewSeriesHandler 创建系列处理器
+func NewSeriesHandler(seriesService service.SeriesService) *SeriesHandler {
+	return &SeriesHandler{seriesService: seriesService}
+}
+
+// Create 创建系列
+func (h *SeriesHandler) Create(c *gin.Context) {
+	var req model.SeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	series, err := h.seriesService.Create(GetActorFromContext(c), &req)
+	if err != nil {
+		seriesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, series)
+}
+
+// Get 获取系列及其中的文章
+func (h *SeriesHandler) Get(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid series id"})
+		return
+	}
+
+	series, err := h.seriesService.Get(id, GetActorFromContext(c))
+	if err != nil {
+		seriesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+// ListByUser 获取用户的系列
+func (h *SeriesHandler) ListByUser(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	series, err := h.seriesService.ListByUser(userID)
+	if err != nil {
+		seriesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"series": series})
+}
+
+// Update 更新系列
+func (h *SeriesHandler) Update(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid series id"})
+		return
+	}
+
+	var req model.SeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	series, err := h.seriesService.Update(id, GetActorFromContext(c), &req)
+	if err != nil {
+		seriesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+// Delete 删除系列
+func (h *SeriesHandler) Delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid series id"})
+		return
+	}
+
+	if err := h.seriesService.Delete(id, GetActorFromContext(c)); err != nil {
+		seriesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "series deleted successfully"})
+}
+
+// AddPost 向系列末尾添加文章
+func (h *SeriesHandler) AddPost(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid series id"})
+		return
+	}
+
+	var req model.AddSeriesPostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	series, err := h.seriesService.AddPost(id, GetActorFromContext(c), req.PostID)
+	if err != nil {
+		seriesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+// RemovePost 从系列中移除文章
+func (h *SeriesHandler) RemovePost(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid series id"})
+		return
+	}
+
+	postID, err := strconv.Atoi(c.Param("post_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post id"})
+		return
+	}
+
+	series, err := h.seriesService.RemovePost(id, GetActorFromContext(c), postID)
+	if err != nil {
+		seriesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+// Reorder 调整系列中文章的顺序
+func (h *SeriesHandler) Reorder(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid series id"})
+		return
+	}
+
+	var req model.ReorderPostsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	series, err := h.seriesService.Reorder(id, GetActorFromContext(c), req.PostIDs)
+	if err != nil {
+		seriesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+// seriesError 将系列服务的错误映射为HTTP状态码
+func seriesError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrSeriesNotFound), errors.Is(err, service.ErrPostNotFound),
+		errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrSeriesAccessDenied):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrSeriesPostNotOwned), errors.Is(err, service.ErrSeriesFull),
+		errors.Is(err, model.ErrInvalidPostOrder):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrPostAlreadyInSeries):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// RegisterRoutes 注册路由
+func (h *SeriesHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	router.GET("/series/:id", OptionalAuth(authMiddleware), h.Get)
+	router.GET("/users/:id/series", h.ListByUser)
+
+	seriesRouter := router.Group("/series")
+	seriesRouter.Use(authMiddleware, RequireScope(model.ScopePostsWrite))
+	{
+		seriesRouter.POST("", RequirePermission(model.PermissionCreatePost), h.Create)
+		seriesRouter.PUT("/:id", h.Update)
+		seriesRouter.DELETE("/:id", h.Delete)
+		seriesRouter.POST("/:id/posts", h.AddPost)
+		seriesRouter.PUT("/:id/posts", h.Reorder)
+		seriesRouter.DELETE("/:id/posts/:post_id", h.RemovePost)
+	}
+}
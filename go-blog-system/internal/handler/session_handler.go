@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/duanyu/go-blog-system/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// SessionHandler 登录会话处理器
+type SessionHandler struct {
+	tokenService service.TokenService
+}
+
+// NewSessionHandler 创建登录会话处理器
+func NewSessionHandler(tokenService service.TokenService) *SessionHandler {
+	return &SessionHandler{tokenService: tokenService}
+}
+
+// List 获取当前用户的活跃会话（登录设备）
+func (h *SessionHandler) List(c *gin.Context) {
+	userID := GetUserIDFromContext(c)
+	claims := GetClaimsFromContext(c)
+
+	sessions, err := h.tokenService.ListSessions(userID, claims.SessionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sessions)
+}
+
+// Revoke 吊销指定会话
+func (h *SessionHandler) Revoke(c *gin.Context) {
+	userID := GetUserIDFromContext(c)
+
+	if err := h.tokenService.RevokeSession(userID, c.Param("id")); err != nil {
+		if errors.Is(err, service.ErrSessionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "session revoked successfully"})
+}
+
+// RevokeAll 退出所有设备，keep_current=true时保留当前会话
+func (h *SessionHandler) RevokeAll(c *gin.Context) {
+	userID := GetUserIDFromContext(c)
+	claims := GetClaimsFromContext(c)
+
+	var err error
+	if c.Query("keep_current") == "true" {
+		err = h.tokenService.RevokeOtherSessions(userID, claims.SessionID)
+	} else {
+		err = h.tokenService.RevokeAllForUser(userID)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "sessions revoked successfully"})
+}
+
+// RegisterRoutes 注册路由
+func (h *SessionHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	authRouter := router.Group("/")
+	authRouter.Use(authMiddleware, RequireSession())
+	{
+		authRouter.GET("/profile/sessions", h.List)
+		authRouter.DELETE("/profile/sessions", h.RevokeAll)
+		authRouter.DELETE("/profile/sessions/:id", h.Revoke)
+	}
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/duanyu/go-blog-system/internal/service"
+	"github.com/duanyu/go-blog-system/pkg/siwe"
+	"github.com/gin-gonic/gin"
+)
+
+// SIWEHandler 以太坊登录处理器
+type SIWEHandler struct {
+	siweService service.SIWEService
+}
+
+// NewSIWEHandler 创建以太坊登录处理器
+func NewSIWEHandler(siweService service.SIWEService) *SIWEHandler {
+	return &SIWEHandler{siweService: siweService}
+}
+
+// Nonce 获取一次性随机数
+func (h *SIWEHandler) Nonce(c *gin.Context) {
+	nonce, err := h.siweService.GenerateNonce(c.ClientIP())
+	if err != nil {
+		var limited *service.RateLimitedError
+		if errors.As(err, &limited) {
+			c.Header("Retry-After", strconv.Itoa(limited.RetryAfterSeconds()))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, nonce)
+}
+
+// Verify 校验签名并登录
+func (h *SIWEHandler) Verify(c *gin.Context) {
+	var req model.SIWERequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.Client = GetClientInfo(c)
+
+	resp, err := h.siweService.Login(&req)
+	if err != nil {
+		if errors.Is(err, service.ErrWalletNotLinked) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// LinkWallet 绑定钱包
+func (h *SIWEHandler) LinkWallet(c *gin.Context) {
+	userID := GetUserIDFromContext(c)
+
+	var req model.SIWERequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	wallet, err := h.siweService.LinkWallet(userID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	wallet.Address = siwe.ChecksumAddress(wallet.Address)
+	c.JSON(http.StatusCreated, wallet)
+}
+
+// UnlinkWallet 解绑钱包
+func (h *SIWEHandler) UnlinkWallet(c *gin.Context) {
+	userID := GetUserIDFromContext(c)
+
+	if err := h.siweService.UnlinkWallet(userID, c.Param("address")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "wallet unlinked successfully"})
+}
+
+// RegisterRoutes 注册路由
+func (h *SIWEHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	router.GET("/siwe/nonce", h.Nonce)
+	router.POST("/siwe/verify", h.Verify)
+
+	authRouter := router.Group("/")
+	authRouter.Use(authMiddleware, RequireSession())
+	{
+		authRouter.POST("/profile/wallets", h.LinkWallet)
+		authRouter.DELETE("/profile/wallets/:address", h.UnlinkWallet)
+	}
+}
@@ -55,13 +55,19 @@ func (h *TagHandler) Get(c *gin.Context) {
 
 // List 获取所有标签
 func (h *TagHandler) List(c *gin.Context) {
-	tags, err := h.tagService.List()
+	var query model.PageQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tags, err := h.tagService.List(&query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		pageError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, tags)
+	writePage(c, tags, &query)
 }
 
 // Delete 删除标签
@@ -81,14 +87,14 @@ func (h *TagHandler) Delete(c *gin.Context) {
 }
 
 // RegisterRoutes 注册路由
-func (h *TagHandler) RegisterRoutes(router *gin.RouterGroup) {
+func (h *TagHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
 	router.GET("/tags", h.List)
 	router.GET("/tags/:id", h.Get)
 
 	authRouter := router.Group("/")
-	authRouter.Use(AuthMiddleware())
+	authRouter.Use(authMiddleware, RequireScope(model.ScopeTagsWrite), RequirePermission(model.PermissionManageTags))
 	{
 		authRouter.POST("/tags", h.Create)
 		authRouter.DELETE("/tags/:id", h.Delete)
 	}
-}
\ No newline at end of file
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -11,12 +12,16 @@ import (
 
 // UserHandler 用户处理器
 type UserHandler struct {
-	userService service.UserService
+	userService  service.UserService
+	tokenService service.TokenService
 }
 
 // NewUserHandler 创建用户处理器
-func NewUserHandler(userService service.UserService) *UserHandler {
-	return &UserHandler{userService: userService}
+func NewUserHandler(userService service.UserService, tokenService service.TokenService) *UserHandler {
+	return &UserHandler{
+		userService:  userService,
+		tokenService: tokenService,
+	}
 }
 
 // Register 注册用户
@@ -43,17 +48,52 @@ func (h *UserHandler) Login(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	req.Client = GetClientInfo(c)
 
-	token, user, err := h.userService.Login(&req)
+	resp, err := h.userService.Login(&req)
 	if err != nil {
+		var throttled *service.LoginThrottledError
+		if errors.As(err, &throttled) {
+			c.Header("Retry-After", strconv.Itoa(throttled.RetryAfterSeconds()))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"token": token,
-		"user":  user,
-	})
+	c.JSON(http.StatusOK, resp)
+}
+
+// RefreshToken 使用刷新令牌换取新的令牌对
+func (h *UserHandler) RefreshToken(c *gin.Context) {
+	var req model.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	req.Client = GetClientInfo(c)
+
+	tokens, err := h.tokenService.Refresh(req.RefreshToken, req.Client)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// Logout 注销当前会话
+func (h *UserHandler) Logout(c *gin.Context) {
+	claims := GetClaimsFromContext(c)
+
+	if err := h.tokenService.Revoke(claims); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "logged out successfully"})
 }
 
 // GetProfile 获取用户个人资料
@@ -69,7 +109,7 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 	c.JSON(http.StatusOK, user)
 }
 
-// GetUser 获取用户信息
+// GetUser 获取用户公开主页
 func (h *UserHandler) GetUser(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -77,13 +117,17 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 		return
 	}
 
-	user, err := h.userService.GetByID(id)
+	profile, err := h.userService.GetProfile(id, GetActorFromContext(c))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		if errors.Is(err, service.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, user)
+	c.JSON(http.StatusOK, profile)
 }
 
 // UpdateProfile 更新用户个人资料
@@ -105,52 +149,248 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	c.JSON(http.StatusOK, user)
 }
 
+// VerifyEmail 验证邮箱
+func (h *UserHandler) VerifyEmail(c *gin.Context) {
+	var req model.VerifyEmailRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.userService.VerifyEmail(req.Token)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// ResendVerification 重新发送验证邮件
+func (h *UserHandler) ResendVerification(c *gin.Context) {
+	userID := GetUserIDFromContext(c)
+
+	if err := h.userService.ResendVerification(userID); err != nil {
+		if errors.Is(err, service.ErrVerificationThrottled) {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "verification email sent"})
+}
+
+// ChangePassword 修改密码
+func (h *UserHandler) ChangePassword(c *gin.Context) {
+	userID := GetUserIDFromContext(c)
+
+	var req model.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.Client = GetClientInfo(c)
+	req.MFA = GetClaimsFromContext(c).MFA
+
+	tokens, err := h.userService.ChangePassword(userID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// ForgotPassword 申请重置密码
+func (h *UserHandler) ForgotPassword(c *gin.Context) {
+	var req model.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.userService.RequestPasswordReset(&req); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "if the email is registered, a reset link has been sent"})
+}
+
+// ResetPassword 重置密码
+func (h *UserHandler) ResetPassword(c *gin.Context) {
+	var req model.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.userService.ResetPassword(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "password reset successfully"})
+}
+
 // DeleteProfile 删除用户账号
 func (h *UserHandler) DeleteProfile(c *gin.Context) {
 	userID := GetUserIDFromContext(c)
 
-	if err := h.userService.Delete(userID); err != nil {
+	purgeAfter, err := h.userService.Delete(userID, userID)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "user deleted successfully"})
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "account scheduled for deletion, log in again before purge_after to restore it",
+		"purge_after": purgeAfter,
+	})
+}
+
+// Directory 获取公开的用户目录
+func (h *UserHandler) Directory(c *gin.Context) {
+	var query model.UserDirectoryQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	users, err := h.userService.Directory(&query)
+	if err != nil {
+		pageError(c, err)
+		return
+	}
+
+	writePage(c, users, &query.PageQuery)
 }
 
 // ListUsers 获取用户列表
 func (h *UserHandler) ListUsers(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "10"))
+	var query model.PageQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	users, err := h.userService.List(&query)
+	if err != nil {
+		pageError(c, err)
+		return
+	}
+
+	writePage(c, users, &query)
+}
+
+// UpdateUserRole 修改用户角色（管理员）
+func (h *UserHandler) UpdateUserRole(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	var req model.UpdateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	users, total, err := h.userService.List(page, perPage)
+	user, err := h.userService.UpdateRole(id, req.Role)
 	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 吊销该用户现有会话，使新角色立即生效
+	if err := h.tokenService.RevokeAllForUser(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// UnlockUser 解除用户的登录锁定（管理员）
+func (h *UserHandler) UnlockUser(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	if err := h.userService.Unlock(id); err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "user unlocked successfully"})
+}
+
+// DeleteUser 删除用户（管理员）
+func (h *UserHandler) DeleteUser(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	purgeAfter, err := h.userService.Delete(id, GetUserIDFromContext(c))
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"users": users,
-		"meta": gin.H{
-			"total":    total,
-			"page":     page,
-			"per_page": perPage,
-		},
+		"message":     "user scheduled for deletion",
+		"purge_after": purgeAfter,
 	})
 }
 
 // RegisterRoutes 注册路由
-func (h *UserHandler) RegisterRoutes(router *gin.RouterGroup) {
+func (h *UserHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
 	router.POST("/register", h.Register)
 	router.POST("/login", h.Login)
+	router.POST("/token/refresh", h.RefreshToken)
+	router.POST("/password/forgot", h.ForgotPassword)
+	router.POST("/password/reset", h.ResetPassword)
+	router.GET("/verify-email", h.VerifyEmail)
 
 	authRouter := router.Group("/")
-	authRouter.Use(AuthMiddleware())
+	authRouter.Use(authMiddleware)
 	{
-		authRouter.GET("/profile", h.GetProfile)
-		authRouter.PUT("/profile", h.UpdateProfile)
-		authRouter.DELETE("/profile", h.DeleteProfile)
+		authRouter.POST("/logout", RequireSession(), h.Logout)
+		authRouter.GET("/profile", RequireScope(model.ScopeProfileRead), h.GetProfile)
+		authRouter.PUT("/profile", RequireScope(model.ScopeProfileWrite), h.UpdateProfile)
+		authRouter.PUT("/profile/password", RequireSession(), h.ChangePassword)
+		authRouter.POST("/profile/verify-email/resend", RequireScope(model.ScopeProfileWrite), h.ResendVerification)
+		authRouter.DELETE("/profile", RequireSession(), h.DeleteProfile)
 	}
 
-	router.GET("/users/:id", h.GetUser)
-	router.GET("/users", h.ListUsers)
-}
\ No newline at end of file
+	router.GET("/users", h.Directory)
+	router.GET("/users/:id", OptionalAuth(authMiddleware), h.GetUser)
+
+	adminRouter := router.Group("/")
+	adminRouter.Use(authMiddleware, RequirePermission(model.PermissionManageUsers))
+	{
+		adminRouter.GET("/admin/users", RequireScope(model.ScopeUsersRead), h.ListUsers)
+		adminRouter.PUT("/users/:id/role", RequireScope(model.ScopeUsersWrite), h.UpdateUserRole)
+		adminRouter.POST("/users/:id/unlock", RequireScope(model.ScopeUsersWrite), h.UnlockUser)
+		adminRouter.DELETE("/users/:id", RequireScope(model.ScopeUsersWrite), h.DeleteUser)
+	}
+}
@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// Bookmark 收藏的文章
+type Bookmark struct {
+	Post
+	BookmarkedAt time.Time `db:"bookmarked_at"`
+}
+
+// BookmarkResponse 收藏列表中的文章
+type BookmarkResponse struct {
+	PostResponse
+	BookmarkedAt time.Time `json:"bookmarked_at"`
+}
+
+// BookmarkRequest 收藏文章请求
+type BookmarkRequest struct {
+	PostID int `json:"post_id" binding:"required"`
+}
+
+// BookmarkedPost 收藏记录（数据导出）
+type BookmarkedPost struct {
+	PostID       int       `db:"post_id" json:"post_id"`
+	Title        string    `db:"title" json:"title"`
+	BookmarkedAt time.Time `db:"bookmarked_at" json:"bookmarked_at"`
+}
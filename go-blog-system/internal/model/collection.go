@@ -0,0 +1,67 @@
+package model
+
+import "time"
+
+// MaxCollectionPosts 合集最多包含的文章数
+const MaxCollectionPosts = 200
+
+// Collection 合集：用户公开整理的文章列表，可以包含任何作者已发布的文章
+type Collection struct {
+	ID          int       `db:"id" json:"id"`
+	UserID      int       `db:"user_id" json:"user_id"`
+	Title       string    `db:"title" json:"title"`
+	Description *string   `db:"description" json:"description"`
+	PostCount   int       `db:"post_count" json:"-"` // 已发布的文章数
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// ToResponse 转换为响应模型
+func (c *Collection) ToResponse() CollectionResponse {
+	return CollectionResponse{
+		ID:          c.ID,
+		Title:       c.Title,
+		Description: c.Description,
+		PostCount:   c.PostCount,
+		CreatedAt:   c.CreatedAt,
+		UpdatedAt:   c.UpdatedAt,
+	}
+}
+
+// CollectionEntry 合集中的文章及整理者的推荐语
+type CollectionEntry struct {
+	Post
+	Note    *string   `db:"note"`
+	AddedAt time.Time `db:"added_at"`
+}
+
+// CollectionResponse 合集响应，详情中包含按顺序排列的文章
+type CollectionResponse struct {
+	ID          int                      `json:"id"`
+	Title       string                   `json:"title"`
+	Description *string                  `json:"description"`
+	PostCount   int                      `json:"post_count"`
+	User        *PublicUserResponse      `json:"user"`
+	Items       []CollectionItemResponse `json:"items,omitempty"`
+	CreatedAt   time.Time                `json:"created_at"`
+	UpdatedAt   time.Time                `json:"updated_at"`
+}
+
+// CollectionItemResponse 合集中的一篇文章
+type CollectionItemResponse struct {
+	Post    PostResponse `json:"post"`
+	Note    *string      `json:"note"`
+	AddedAt time.Time    `json:"added_at"`
+}
+
+// CollectionRequest 创建或更新合集请求
+type CollectionRequest struct {
+	Title       string  `json:"title" binding:"required,max=255"`
+	Description *string `json:"description" binding:"omitempty,max=1000"`
+}
+
+// AddCollectionPostRequest 向合集末尾添加文章请求
+type AddCollectionPostRequest struct {
+	PostID int     `json:"post_id" binding:"required"`
+	Note   *string `json:"note" binding:"omitempty,max=500"`
+}
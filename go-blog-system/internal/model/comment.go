@@ -9,10 +9,11 @@ type Comment struct {
 	UserID    int        `db:"user_id" json:"user_id"`
 	PostID    int        `db:"post_id" json:"post_id"`
 	ParentID  *int       `db:"parent_id" json:"parent_id"`
+	Reactions ReactionCounts `db:"reactions" json:"reactions"`
 	CreatedAt time.Time  `db:"created_at" json:"created_at"`
 	UpdatedAt time.Time  `db:"updated_at" json:"updated_at"`
 	// 关联字段（不在数据库中）
-	User      *UserResponse `db:"-" json:"user,omitempty"`
+	User      *PublicUserResponse `db:"-" json:"user,omitempty"`
 	Replies   []Comment     `db:"-" json:"replies,omitempty"`
 }
 
@@ -22,9 +23,10 @@ type CommentResponse struct {
 	Content   string        `json:"content"`
 	PostID    int           `json:"post_id"`
 	ParentID  *int          `json:"parent_id"`
+	Reactions ReactionCounts `json:"reactions"`
 	CreatedAt time.Time     `json:"created_at"`
 	UpdatedAt time.Time     `json:"updated_at"`
-	User      *UserResponse `json:"user,omitempty"`
+	User      *PublicUserResponse `json:"user,omitempty"`
 	Replies   []Comment     `json:"replies,omitempty"`
 }
 
@@ -35,6 +37,7 @@ func (c *Comment) ToResponse() CommentResponse {
 		Content:   c.Content,
 		PostID:    c.PostID,
 		ParentID:  c.ParentID,
+		Reactions: c.Reactions,
 		CreatedAt: c.CreatedAt,
 		UpdatedAt: c.UpdatedAt,
 		User:      c.User,
@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+// DataExportStatus 数据导出状态
+type DataExportStatus string
+
+const (
+	DataExportPending    DataExportStatus = "pending"
+	DataExportProcessing DataExportStatus = "processing"
+	DataExportCompleted  DataExportStatus = "completed"
+	DataExportFailed     DataExportStatus = "failed"
+)
+
+// DataExport 个人数据导出任务
+type DataExport struct {
+	ID          int              `db:"id" json:"id"`
+	UserID      int              `db:"user_id" json:"user_id"`
+	Status      DataExportStatus `db:"status" json:"status"`
+	InstanceID  string           `db:"instance_id" json:"-"`
+	FilePath    *string          `db:"file_path" json:"-"`
+	Error       *string          `db:"error" json:"error"`
+	ExpiresAt   *time.Time       `db:"expires_at" json:"expires_at"`
+	CompletedAt *time.Time       `db:"completed_at" json:"completed_at"`
+	CreatedAt   time.Time        `db:"created_at" json:"created_at"`
+}
+
+// IsInProgress 导出是否仍在进行中
+func (e *DataExport) IsInProgress() bool {
+	return e.Status == DataExportPending || e.Status == DataExportProcessing
+}
+
+// DataExportResponse 数据导出响应，完成后附带限时下载链接
+type DataExportResponse struct {
+	ID          int              `json:"id"`
+	Status      DataExportStatus `json:"status"`
+	Error       *string          `json:"error,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+	CompletedAt *time.Time       `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time       `json:"expires_at,omitempty"`
+	DownloadURL string           `json:"download_url,omitempty"`
+}
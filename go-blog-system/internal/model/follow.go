@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// FollowedUser 关注的用户
+type FollowedUser struct {
+	UserID     int       `db:"user_id" json:"user_id"`
+	Username   string    `db:"username" json:"username"`
+	FollowedAt time.Time `db:"followed_at" json:"followed_at"`
+}
+
+// FollowedTag 关注的标签
+type FollowedTag struct {
+	TagID      int       `db:"tag_id" json:"tag_id"`
+	Name       string    `db:"name" json:"name"`
+	FollowedAt time.Time `db:"followed_at" json:"followed_at"`
+}
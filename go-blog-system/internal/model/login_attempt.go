@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+// LoginAttempt 登录失败记录，key为账号或IP
+type LoginAttempt struct {
+	Key           string     `db:"attempt_key" json:"key"`
+	Failures      int        `db:"failures" json:"failures"`
+	LastFailureAt time.Time  `db:"last_failure_at" json:"last_failure_at"`
+	BlockedUntil  *time.Time `db:"blocked_until" json:"blocked_until"`
+}
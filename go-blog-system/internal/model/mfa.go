@@ -0,0 +1,48 @@
+package model
+
+import "time"
+
+// RecoveryCode 两步验证恢复码（仅保存哈希）
+type RecoveryCode struct {
+	ID        int        `db:"id" json:"id"`
+	UserID    int        `db:"user_id" json:"user_id"`
+	CodeHash  string     `db:"code_hash" json:"-"`
+	UsedAt    *time.Time `db:"used_at" json:"used_at"`
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
+}
+
+// MFAStatusResponse 两步验证状态
+type MFAStatusResponse struct {
+	Enabled                bool       `json:"enabled"`
+	EnabledAt              *time.Time `json:"enabled_at"`
+	RecoveryCodesRemaining int        `json:"recovery_codes_remaining"`
+}
+
+// TOTPSetupResponse 两步验证绑定信息
+type TOTPSetupResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+// RecoveryCodesResponse 恢复码（只在生成时返回一次）
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// TOTPCodeRequest 验证码请求
+type TOTPCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// DisableTOTPRequest 关闭两步验证请求
+type DisableTOTPRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+// MFALoginRequest 两步验证登录请求，code可以是验证码或恢复码
+type MFALoginRequest struct {
+	ChallengeToken string     `json:"challenge_token" binding:"required"`
+	Code           string     `json:"code" binding:"required"`
+	Client         ClientInfo `json:"-"`
+}
@@ -0,0 +1,89 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// ErrInvalidCursor 分页游标无效
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor 键集分页游标，记录边界记录的排序值和ID
+type Cursor struct {
+	Sort     string  `json:"s"`           // 生成游标时的排序方式，游标不能用于其他排序
+	Value    *string `json:"v,omitempty"` // 排序列的值，nil表示NULL
+	ID       int     `json:"id"`
+	Backward bool    `json:"b,omitempty"` // 为true时获取边界记录之前的一页
+}
+
+// Encode 编码为不透明的游标字符串
+func (c *Cursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeCursor 解析游标字符串，空字符串表示第一页
+func DecodeCursor(s string) (*Cursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.ID <= 0 {
+		return nil, ErrInvalidCursor
+	}
+
+	return &cursor, nil
+}
+
+// PageQuery 分页参数：默认使用游标分页，只指定page时按页码分页（兼容旧的客户端）
+type PageQuery struct {
+	Cursor  string `form:"cursor"`
+	Page    int    `form:"page" binding:"omitempty,min=1"`
+	PerPage int    `form:"per_page" binding:"omitempty,min=1"`
+	Total   bool   `form:"total"`
+}
+
+// Normalize 设置每页数量的默认值并限制最大值
+func (q *PageQuery) Normalize(defaultPerPage, maxPerPage int) {
+	if q.PerPage <= 0 {
+		q.PerPage = defaultPerPage
+	}
+	if q.PerPage > maxPerPage {
+		q.PerPage = maxPerPage
+	}
+}
+
+// UseOffset 是否按页码分页
+func (q *PageQuery) UseOffset() bool {
+	return q.Cursor == "" && q.Page > 0
+}
+
+// WantTotal 是否需要返回总数：按页码分页时总是返回，游标分页时按需返回
+func (q *PageQuery) WantTotal() bool {
+	return q.UseOffset() || q.Total
+}
+
+// Page 分页响应
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+	Total      *int   `json:"total,omitempty"`
+}
+
+// MapPage 用新的条目替换分页结果中的条目，保留游标和总数
+func MapPage[T, U any](page *Page[T], items []U) *Page[U] {
+	return &Page[U]{
+		Items:      items,
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+		Total:      page.Total,
+	}
+}
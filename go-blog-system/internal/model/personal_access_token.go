@@ -0,0 +1,96 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// PersonalAccessTokenPrefix 个人访问令牌前缀，用于和JWT区分
+const PersonalAccessTokenPrefix = "blog_pat_"
+
+// Scope 个人访问令牌的授权范围
+type Scope string
+
+const (
+	ScopePostsRead     Scope = "posts:read"
+	ScopePostsWrite    Scope = "posts:write"
+	ScopeCommentsRead  Scope = "comments:read"
+	ScopeCommentsWrite Scope = "comments:write"
+	ScopeTagsWrite     Scope = "tags:write"
+	ScopeProfileRead   Scope = "profile:read"
+	ScopeProfileWrite  Scope = "profile:write"
+	ScopeUsersRead     Scope = "users:read"
+	ScopeUsersWrite    Scope = "users:write"
+)
+
+// validScopes 所有可授予的授权范围
+var validScopes = map[Scope]bool{
+	ScopePostsRead:     true,
+	ScopePostsWrite:    true,
+	ScopeCommentsRead:  true,
+	ScopeCommentsWrite: true,
+	ScopeTagsWrite:     true,
+	ScopeProfileRead:   true,
+	ScopeProfileWrite:  true,
+	ScopeUsersRead:     true,
+	ScopeUsersWrite:    true,
+}
+
+// IsValid 检查授权范围是否合法
+func (s Scope) IsValid() bool {
+	return validScopes[s]
+}
+
+// PersonalAccessToken 个人访问令牌模型（仅保存哈希）
+type PersonalAccessToken struct {
+	ID          int        `db:"id" json:"id"`
+	UserID      int        `db:"user_id" json:"user_id"`
+	Name        string     `db:"name" json:"name"`
+	TokenPrefix string     `db:"token_prefix" json:"token_prefix"`
+	TokenHash   string     `db:"token_hash" json:"-"`
+	Scopes      string     `db:"scopes" json:"-"` // 以逗号分隔
+	ExpiresAt   time.Time  `db:"expires_at" json:"expires_at"`
+	LastUsedAt  *time.Time `db:"last_used_at" json:"last_used_at"`
+	LastUsedIP  *string    `db:"last_used_ip" json:"last_used_ip"`
+	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
+}
+
+// ScopeList 返回令牌的授权范围列表
+func (t *PersonalAccessToken) ScopeList() []Scope {
+	if t.Scopes == "" {
+		return nil
+	}
+
+	parts := strings.Split(t.Scopes, ",")
+	scopes := make([]Scope, 0, len(parts))
+	for _, p := range parts {
+		scopes = append(scopes, Scope(p))
+	}
+
+	return scopes
+}
+
+// PersonalAccessTokenResponse 个人访问令牌响应
+type PersonalAccessTokenResponse struct {
+	ID          int        `json:"id"`
+	Name        string     `json:"name"`
+	TokenPrefix string     `json:"token_prefix"`
+	Scopes      []Scope    `json:"scopes"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	LastUsedIP  *string    `json:"last_used_ip"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// CreatedPersonalAccessTokenResponse 新建的个人访问令牌，明文令牌只在创建时返回一次
+type CreatedPersonalAccessTokenResponse struct {
+	PersonalAccessTokenResponse
+	Token string `json:"token"`
+}
+
+// CreatePersonalAccessTokenRequest 创建个人访问令牌请求
+type CreatePersonalAccessTokenRequest struct {
+	Name          string  `json:"name" binding:"required,max=100"`
+	Scopes        []Scope `json:"scopes" binding:"required,min=1"`
+	ExpiresInDays int     `json:"expires_in_days" binding:"omitempty,min=1"`
+}
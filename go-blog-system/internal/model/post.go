@@ -1,82 +1,241 @@
 package model
 
-import "time"
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
 
 // PostStatus 文章状态
 type PostStatus string
 
 const (
 	PostStatusDraft     PostStatus = "draft"
+	PostStatusInReview  PostStatus = "in_review" // 等待审核
+	PostStatusApproved  PostStatus = "approved"  // 审核通过，等待作者发布
+	PostStatusScheduled PostStatus = "scheduled" // 到达published_at后由定时任务发布
 	PostStatusPublished PostStatus = "published"
 	PostStatusArchived  PostStatus = "archived"
 )
 
+// ContentFormat 文章内容格式
+type ContentFormat string
+
+const (
+	ContentFormatMarkdown ContentFormat = "markdown"
+	ContentFormatHTML     ContentFormat = "html"
+	ContentFormatPlain    ContentFormat = "plain"
+)
+
+// TOCEntry 文章目录项
+type TOCEntry struct {
+	Level int    `json:"level"`
+	Text  string `json:"text"`
+	ID    string `json:"id"`
+}
+
 // Post 文章模型
 type Post struct {
-	ID        int        `db:"id" json:"id"`
-	Title     string     `db:"title" json:"title"`
-	Content   string     `db:"content" json:"content"`
-	UserID    int        `db:"user_id" json:"user_id"`
-	Status    PostStatus `db:"status" json:"status"`
-	ViewCount int        `db:"view_count" json:"view_count"`
-	CreatedAt time.Time  `db:"created_at" json:"created_at"`
-	UpdatedAt time.Time  `db:"updated_at" json:"updated_at"`
+	ID            int            `db:"id" json:"id"`
+	Title         string         `db:"title" json:"title"`
+	Slug          *string        `db:"slug" json:"slug"`
+	Content       string         `db:"content" json:"content"`
+	Summary       *string        `db:"summary" json:"summary"` // 作者填写的摘要，为空时使用自动生成的摘要
+	Excerpt       string         `db:"excerpt" json:"-"`       // 根据正文自动生成的摘要
+	WordCount     int            `db:"word_count" json:"word_count"`
+	ReadingTime   int            `db:"reading_time" json:"reading_time"` // 预计阅读时间（分钟）
+	ContentFormat ContentFormat  `db:"content_format" json:"content_format"`
+	ContentHTML   *string        `db:"content_html" json:"content_html"`
+	TOC           *string        `db:"toc" json:"-"`            // 目录（JSON）
+	RenderVersion int            `db:"render_version" json:"-"` // 渲染content_html时的规则版本
+	UserID        int            `db:"user_id" json:"user_id"`
+	Status        PostStatus     `db:"status" json:"status"`
+	PublishedAt   *time.Time     `db:"published_at" json:"published_at"`
+	ViewCount     int            `db:"view_count" json:"view_count"`
+	CommentCount  int            `db:"comment_count" json:"comment_count"`
+	Reactions     ReactionCounts `db:"reactions" json:"reactions"`
+	CreatedAt     time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time      `db:"updated_at" json:"updated_at"`
 	// 关联字段（不在数据库中）
-	User      *UserResponse `db:"-" json:"user,omitempty"`
-	Tags      []Tag         `db:"-" json:"tags,omitempty"`
-	Comments  []Comment     `db:"-" json:"comments,omitempty"`
+	User     *PublicUserResponse `db:"-" json:"user,omitempty"`
+	Tags     []Tag               `db:"-" json:"tags,omitempty"`
+	Comments []Comment           `db:"-" json:"comments,omitempty"`
 }
 
 // PostResponse 文章响应模型
 type PostResponse struct {
-	ID        int           `json:"id"`
-	Title     string        `json:"title"`
-	Content   string        `json:"content"`
-	Status    PostStatus    `json:"status"`
-	ViewCount int           `json:"view_count"`
-	CreatedAt time.Time     `json:"created_at"`
-	UpdatedAt time.Time     `json:"updated_at"`
-	User      *UserResponse `json:"user,omitempty"`
-	Tags      []Tag         `json:"tags,omitempty"`
+	ID            int                 `json:"id"`
+	Title         string              `json:"title"`
+	Slug          *string             `json:"slug"`
+	Summary       *string             `json:"summary,omitempty"`
+	Excerpt       string              `json:"excerpt"`
+	Content       string              `json:"content,omitempty"`
+	ContentFormat ContentFormat       `json:"content_format"`
+	ContentHTML   string              `json:"content_html,omitempty"`
+	TOC           []TOCEntry          `json:"toc,omitempty"`
+	WordCount     int                 `json:"word_count"`
+	ReadingTime   int                 `json:"reading_time"`
+	Status        PostStatus          `json:"status"`
+	PublishedAt   *time.Time          `json:"published_at"`
+	ViewCount     int                 `json:"view_count"`
+	CommentCount  int                 `json:"comment_count"`
+	Reactions     ReactionCounts      `json:"reactions"`
+	CreatedAt     time.Time           `json:"created_at"`
+	UpdatedAt     time.Time           `json:"updated_at"`
+	User          *PublicUserResponse `json:"user,omitempty"`
+	Tags          []Tag               `json:"tags,omitempty"`
+	Series        *PostSeriesInfo     `json:"series,omitempty"` // 仅文章详情包含
+	Viewer        *PostViewerState    `json:"viewer,omitempty"` // 仅携带令牌访问时包含
 }
 
 // ToResponse 转换为响应模型
 func (p *Post) ToResponse() PostResponse {
 	return PostResponse{
-		ID:        p.ID,
-		Title:     p.Title,
-		Content:   p.Content,
-		Status:    p.Status,
-		ViewCount: p.ViewCount,
-		CreatedAt: p.CreatedAt,
-		UpdatedAt: p.UpdatedAt,
-		User:      p.User,
-		Tags:      p.Tags,
+		ID:            p.ID,
+		Title:         p.Title,
+		Slug:          p.Slug,
+		Summary:       p.Summary,
+		Excerpt:       p.DisplayExcerpt(),
+		Content:       p.Content,
+		ContentFormat: p.ContentFormat,
+		ContentHTML:   p.HTML(),
+		TOC:           p.TOCEntries(),
+		WordCount:     p.WordCount,
+		ReadingTime:   p.ReadingTime,
+		Status:        p.Status,
+		PublishedAt:   p.PublishedAt,
+		ViewCount:     p.ViewCount,
+		CommentCount:  p.CommentCount,
+		Reactions:     p.Reactions,
+		CreatedAt:     p.CreatedAt,
+		UpdatedAt:     p.UpdatedAt,
+		User:          p.User,
+		Tags:          p.Tags,
+	}
+}
+
+// HTML 返回渲染后的HTML内容
+func (p *Post) HTML() string {
+	if p.ContentHTML == nil {
+		return ""
 	}
+
+	return *p.ContentHTML
+}
+
+// DisplayExcerpt 返回列表中展示的摘要：优先使用作者填写的摘要
+func (p *Post) DisplayExcerpt() string {
+	if p.Summary != nil && *p.Summary != "" {
+		return *p.Summary
+	}
+
+	return p.Excerpt
+}
+
+// ToListResponse 转换为列表中使用的精简响应，不包含正文、HTML和目录
+func (p *Post) ToListResponse() PostResponse {
+	resp := p.ToResponse()
+	resp.Content = ""
+	resp.ContentHTML = ""
+	resp.TOC = nil
+	return resp
+}
+
+// TOCEntries 返回文章目录
+func (p *Post) TOCEntries() []TOCEntry {
+	if p.TOC == nil || *p.TOC == "" {
+		return nil
+	}
+
+	var entries []TOCEntry
+	if err := json.Unmarshal([]byte(*p.TOC), &entries); err != nil {
+		return nil
+	}
+
+	return entries
 }
 
 // CreatePostRequest 创建文章请求
 type CreatePostRequest struct {
-	Title   string     `json:"title" binding:"required"`
-	Content string     `json:"content" binding:"required"`
-	Status  PostStatus `json:"status" binding:"omitempty"`
-	TagIDs  []int      `json:"tag_ids" binding:"omitempty"`
+	Title         string        `json:"title" binding:"required"`
+	Slug          string        `json:"slug" binding:"omitempty,max=80"` // 为空时根据标题生成
+	Content       string        `json:"content" binding:"required"`
+	Summary       string        `json:"summary" binding:"omitempty,max=500"`                          // 为空时根据正文自动生成摘要
+	ContentFormat ContentFormat `json:"content_format" binding:"omitempty,oneof=markdown html plain"` // 默认markdown
+	Status        PostStatus    `json:"status" binding:"omitempty,oneof=draft in_review approved scheduled published archived"`
+	PublishedAt   *time.Time    `json:"published_at" binding:"omitempty"` // RFC3339格式（需带时区），status为scheduled时必填
+	TagIDs        []int         `json:"tag_ids" binding:"omitempty"`
 }
 
 // UpdatePostRequest 更新文章请求
 type UpdatePostRequest struct {
-	Title   *string     `json:"title" binding:"omitempty"`
-	Content *string     `json:"content" binding:"omitempty"`
-	Status  *PostStatus `json:"status" binding:"omitempty"`
-	TagIDs  []int       `json:"tag_ids" binding:"omitempty"`
+	Title         *string        `json:"title" binding:"omitempty"`
+	Slug          *string        `json:"slug" binding:"omitempty,max=80"` // 为空时修改标题会重新生成slug
+	Content       *string        `json:"content" binding:"omitempty"`
+	Summary       *string        `json:"summary" binding:"omitempty,max=500"` // 设为空字符串时恢复自动生成的摘要
+	ContentFormat *ContentFormat `json:"content_format" binding:"omitempty,oneof=markdown html plain"`
+	Status        *PostStatus    `json:"status" binding:"omitempty,oneof=draft in_review approved scheduled published archived"`
+	PublishedAt   *time.Time     `json:"published_at" binding:"omitempty"` // RFC3339格式（需带时区）
+	TagIDs        []int          `json:"tag_ids" binding:"omitempty"`
+}
+
+// PostEdit 创建或更新文章时需要与文章在同一事务中写入的记录
+type PostEdit struct {
+	Baseline   *PostRevision   // 文章还没有修订记录时先保存的修改前版本
+	Revision   *PostRevision   // 修改后的版本
+	Transition *PostTransition // 状态发生变化时的变更记录
+	NewSlug    string          // 非空时修改slug，旧slug保留为重定向
+	TagIDs     []int           // 非nil时替换文章标签
 }
 
+// ErrInvalidFilter 文章过滤表达式无效
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// PostSort 文章列表排序方式
+type PostSort string
+
+const (
+	PostSortPublished PostSort = "published" // 按发布时间，默认倒序
+	PostSortViews     PostSort = "views"     // 按浏览量，默认倒序
+	PostSortComments  PostSort = "comments"  // 按评论数，默认倒序
+	PostSortUpdated   PostSort = "updated"   // 按最后修改时间，默认倒序
+	PostSortTitle     PostSort = "title"     // 按标题，默认正序
+)
+
+// TagMatchMode 多个标签的匹配方式
+type TagMatchMode string
+
+const (
+	TagMatchAny TagMatchMode = "any" // 包含任一标签
+	TagMatchAll TagMatchMode = "all" // 包含全部标签
+)
+
 // PostQuery 文章查询参数
 type PostQuery struct {
-	UserID  *int       `form:"user_id"`
-	Status  PostStatus `form:"status"`
-	TagID   *int       `form:"tag_id"`
-	Keyword string     `form:"keyword"`
-	Page    int        `form:"page,default=1"`
-	PerPage int        `form:"per_page,default=10"`
-}
\ No newline at end of file
+	UserID        *int         `form:"user_id"`
+	UserIDs       []int        `form:"user_ids" binding:"max=20"`
+	Status        PostStatus   `form:"status"`
+	TagID         *int         `form:"tag_id"`
+	TagIDs        []int        `form:"tag_ids" binding:"max=20"`
+	TagMode       TagMatchMode `form:"tag_mode" binding:"omitempty,oneof=any all"`
+	PublishedFrom *time.Time   `form:"published_from"`
+	PublishedTo   *time.Time   `form:"published_to"`
+	Keyword       string       `form:"keyword"`
+	Filter        string       `form:"filter"`
+	Fields        string       `form:"fields"` // fields=content时列表包含正文
+	Sort          PostSort     `form:"sort" binding:"omitempty,oneof=published views comments updated title"`
+	Order         string       `form:"order" binding:"omitempty,oneof=asc desc"`
+	PageQuery
+}
+
+// WantsContent 列表是否需要包含正文（fields参数以逗号分隔）
+func (q *PostQuery) WantsContent() bool {
+	for _, field := range strings.Split(q.Fields, ",") {
+		if strings.TrimSpace(field) == "content" {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,59 @@
+package model
+
+import "time"
+
+// PostRevision 文章修订版本，保存每次创建或更新后的文章快照
+type PostRevision struct {
+	ID            int           `db:"id" json:"id"`
+	PostID        int           `db:"post_id" json:"post_id"`
+	Revision      int           `db:"revision" json:"revision"`
+	UserID        *int          `db:"user_id" json:"user_id"` // 修改人
+	Title         string        `db:"title" json:"title"`
+	Content       string        `db:"content" json:"content"`
+	ContentFormat ContentFormat `db:"content_format" json:"content_format"`
+	Status        PostStatus    `db:"status" json:"status"`
+	CreatedAt     time.Time     `db:"created_at" json:"created_at"`
+}
+
+// PostRevisionResponse 文章修订版本响应，列表中不包含正文
+type PostRevisionResponse struct {
+	Revision      int                 `json:"revision"`
+	Title         string              `json:"title"`
+	Content       string              `json:"content,omitempty"`
+	ContentFormat ContentFormat       `json:"content_format"`
+	Status        PostStatus          `json:"status"`
+	Editor        *PublicUserResponse `json:"editor"`
+	CreatedAt     time.Time           `json:"created_at"`
+}
+
+// DiffMode 差异格式
+type DiffMode string
+
+const (
+	DiffModeUnified DiffMode = "unified" // 按行比较的统一格式
+	DiffModeWords   DiffMode = "words"   // 按词比较
+)
+
+// RevisionDiffQuery 修订版本比较参数
+type RevisionDiffQuery struct {
+	From int      `form:"from" binding:"required,min=1"`
+	To   int      `form:"to" binding:"required,min=1"`
+	Mode DiffMode `form:"mode,default=unified" binding:"oneof=unified words"`
+}
+
+// RevisionDiffResponse 修订版本差异
+type RevisionDiffResponse struct {
+	From      int           `json:"from"`
+	To        int           `json:"to"`
+	Mode      DiffMode      `json:"mode"`
+	TitleFrom string        `json:"title_from"`
+	TitleTo   string        `json:"title_to"`
+	Unified   string        `json:"unified,omitempty"`
+	Segments  []DiffSegment `json:"segments,omitempty"`
+}
+
+// DiffSegment 词级差异片段，op为equal、insert或delete
+type DiffSegment struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
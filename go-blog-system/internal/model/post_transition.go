@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// PostTransition 文章状态变更记录
+type PostTransition struct {
+	ID         int         `db:"id" json:"id"`
+	PostID     int         `db:"post_id" json:"post_id"`
+	FromStatus *PostStatus `db:"from_status" json:"from_status"` // 创建文章时为空
+	ToStatus   PostStatus  `db:"to_status" json:"to_status"`
+	UserID     *int        `db:"user_id" json:"user_id"` // 定时任务发布时为空
+	Comment    *string     `db:"comment" json:"comment"`
+	CreatedAt  time.Time   `db:"created_at" json:"created_at"`
+}
+
+// PostTransitionResponse 文章状态变更记录响应
+type PostTransitionResponse struct {
+	FromStatus *PostStatus         `json:"from_status"`
+	ToStatus   PostStatus          `json:"to_status"`
+	User       *PublicUserResponse `json:"user"`
+	Comment    *string             `json:"comment"`
+	CreatedAt  time.Time           `json:"created_at"`
+}
+
+// ReviewRequest 审核通过请求
+type ReviewRequest struct {
+	Comment string `json:"comment" binding:"max=2000"`
+}
+
+// RequestChangesRequest 要求修改请求，必须说明需要修改的内容
+type RequestChangesRequest struct {
+	Comment string `json:"comment" binding:"required,max=2000"`
+}
+
+// ReviewQueueQuery 审核队列查询参数
+type ReviewQueueQuery struct {
+	Page    int `form:"page,default=1" binding:"min=1"`
+	PerPage int `form:"per_page,default=20" binding:"min=1,max=100"`
+}
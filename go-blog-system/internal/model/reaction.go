@@ -0,0 +1,139 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Reaction 表情回应
+type Reaction string
+
+const (
+	ReactionLike     Reaction = "like"     // 👍
+	ReactionHeart    Reaction = "heart"    // ❤️
+	ReactionLaugh    Reaction = "laugh"    // 😄
+	ReactionHooray   Reaction = "hooray"   // 🎉
+	ReactionConfused Reaction = "confused" // 😕
+	ReactionRocket   Reaction = "rocket"   // 🚀
+	ReactionEyes     Reaction = "eyes"     // 👀
+)
+
+// reactionEmoji 可用的表情回应及对应的表情符号
+var reactionEmoji = map[Reaction]string{
+	ReactionLike:     "👍",
+	ReactionHeart:    "❤️",
+	ReactionLaugh:    "😄",
+	ReactionHooray:   "🎉",
+	ReactionConfused: "😕",
+	ReactionRocket:   "🚀",
+	ReactionEyes:     "👀",
+}
+
+// IsValid 检查表情回应是否在可用列表中
+func (r Reaction) IsValid() bool {
+	_, ok := reactionEmoji[r]
+	return ok
+}
+
+// Emoji 返回表情回应对应的表情符号
+func (r Reaction) Emoji() string {
+	return reactionEmoji[r]
+}
+
+// ReactionTarget 可以回应的对象
+type ReactionTarget string
+
+const (
+	ReactionTargetPost    ReactionTarget = "post"
+	ReactionTargetComment ReactionTarget = "comment"
+)
+
+// UserReaction 用户添加的表情回应（数据导出）
+type UserReaction struct {
+	Target    ReactionTarget `db:"target" json:"target"`
+	TargetID  int            `db:"target_id" json:"target_id"`
+	Reaction  Reaction       `db:"reaction" json:"reaction"`
+	CreatedAt time.Time      `db:"created_at" json:"created_at"`
+}
+
+// ReactionCounts 各表情回应的数量，以JSON保存在文章和评论上，随回应记录在同一事务中更新。
+// 数量为0的回应不输出
+type ReactionCounts map[Reaction]int
+
+// Scan 实现sql.Scanner，NULL表示没有回应
+func (c *ReactionCounts) Scan(src interface{}) error {
+	*c = ReactionCounts{}
+
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported reaction counts type %T", src)
+	}
+
+	// MySQL可能以浮点数形式保存JSON中的数字
+	var counts map[Reaction]float64
+	if err := json.Unmarshal(data, &counts); err != nil {
+		return fmt.Errorf("failed to parse reaction counts: %w", err)
+	}
+
+	for reaction, count := range counts {
+		if count > 0 {
+			(*c)[reaction] = int(count)
+		}
+	}
+
+	return nil
+}
+
+// MarshalJSON 没有回应时输出空对象
+func (c ReactionCounts) MarshalJSON() ([]byte, error) {
+	if c == nil {
+		return []byte("{}"), nil
+	}
+
+	return json.Marshal(map[Reaction]int(c))
+}
+
+// PostViewerState 当前用户对文章的回应和收藏状态，仅在携带令牌访问时返回
+type PostViewerState struct {
+	Reactions  []Reaction `json:"reactions"`
+	Bookmarked bool       `json:"bookmarked"`
+}
+
+// ReactionRequest 添加表情回应请求
+type ReactionRequest struct {
+	Reaction Reaction `json:"reaction" binding:"required,oneof=like heart laugh hooray confused rocket eyes"`
+}
+
+// ReactionOption 可用的表情回应
+type ReactionOption struct {
+	Reaction Reaction `json:"reaction"`
+	Emoji    string   `json:"emoji"`
+}
+
+// ReactionOptions 按固定顺序返回全部可用的表情回应
+func ReactionOptions() []ReactionOption {
+	reactions := []Reaction{
+		ReactionLike, ReactionHeart, ReactionLaugh, ReactionHooray, ReactionConfused, ReactionRocket, ReactionEyes,
+	}
+
+	options := make([]ReactionOption, len(reactions))
+	for i, r := range reactions {
+		options[i] = ReactionOption{Reaction: r, Emoji: r.Emoji()}
+	}
+
+	return options
+}
+
+// ReactionsResponse 添加或取消回应后的回应数量和当前用户的回应
+type ReactionsResponse struct {
+	Reactions       ReactionCounts `json:"reactions"`
+	ViewerReactions []Reaction     `json:"viewer_reactions"`
+}
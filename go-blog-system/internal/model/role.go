@@ -0,0 +1,86 @@
+package model
+
+// Role 用户角色
+type Role string
+
+const (
+	RoleAdmin  Role = "admin"
+	RoleEditor Role = "editor"
+	RoleAuthor Role = "author"
+	RoleReader Role = "reader"
+)
+
+// Permission 权限
+type Permission string
+
+const (
+	PermissionCreatePost       Permission = "posts:create"
+	PermissionEditAnyPost      Permission = "posts:edit_any"
+	PermissionDeleteAnyPost    Permission = "posts:delete_any"
+	PermissionReviewPosts      Permission = "posts:review"
+	PermissionCreateComment    Permission = "comments:create"
+	PermissionModerateComments Permission = "comments:moderate"
+	PermissionManageTags       Permission = "tags:manage"
+	PermissionManageUsers      Permission = "users:manage"
+)
+
+// rolePermissions 角色权限表
+var rolePermissions = map[Role][]Permission{
+	RoleAdmin: {
+		PermissionCreatePost,
+		PermissionEditAnyPost,
+		PermissionDeleteAnyPost,
+		PermissionReviewPosts,
+		PermissionCreateComment,
+		PermissionModerateComments,
+		PermissionManageTags,
+		PermissionManageUsers,
+	},
+	RoleEditor: {
+		PermissionCreatePost,
+		PermissionEditAnyPost,
+		PermissionReviewPosts,
+		PermissionCreateComment,
+		PermissionModerateComments,
+	},
+	RoleAuthor: {
+		PermissionCreatePost,
+		PermissionCreateComment,
+	},
+	RoleReader: {
+		PermissionCreateComment,
+	},
+}
+
+// IsValid 检查角色是否合法
+func (r Role) IsValid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// Can 检查角色是否拥有指定权限
+func (r Role) Can(permission Permission) bool {
+	for _, p := range rolePermissions[r] {
+		if p == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Actor 执行操作的用户
+type Actor struct {
+	UserID int
+	Role   Role
+}
+
+// CanModify 检查用户能否修改指定用户拥有的资源（本人或拥有对应权限）
+func (a Actor) CanModify(ownerID int, permission Permission) bool {
+	return a.UserID == ownerID || a.Role.Can(permission)
+}
+
+// UpdateRoleRequest 修改用户角色请求
+type UpdateRoleRequest struct {
+	Role Role `json:"role" binding:"required,oneof=admin editor author reader"`
+}
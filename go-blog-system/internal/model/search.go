@@ -0,0 +1,25 @@
+package model
+
+// SearchQuery 全文搜索参数，可以与文章列表相同的条件组合过滤
+type SearchQuery struct {
+	Q       string     `form:"q" binding:"required,max=200"`
+	UserID  *int       `form:"user_id"`
+	Status  PostStatus `form:"status"`
+	TagID   *int       `form:"tag_id"`
+	Page    int        `form:"page,default=1" binding:"min=1"`
+	PerPage int        `form:"per_page,default=10" binding:"min=1,max=50"`
+}
+
+// PostSearchHit 带相关度的搜索结果
+type PostSearchHit struct {
+	Post
+	Score float64 `db:"score"`
+}
+
+// SearchHitResponse 搜索结果响应，title_highlight和snippet为转义后的HTML，命中的关键词以<mark>标记
+type SearchHitResponse struct {
+	Post           PostResponse `json:"post"`
+	Score          float64      `json:"score"`
+	TitleHighlight string       `json:"title_highlight"`
+	Snippet        string       `json:"snippet"`
+}
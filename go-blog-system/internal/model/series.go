@@ -0,0 +1,81 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// MaxSeriesPosts 系列最多包含的文章数
+const MaxSeriesPosts = 100
+
+// ErrInvalidPostOrder 调整顺序时提交了不在列表中的文章或重复的文章
+var ErrInvalidPostOrder = errors.New("post_ids must only contain posts in the list, each at most once")
+
+// Series 系列：作者将自己的多篇文章按顺序组织在一起，一篇文章最多属于一个系列
+type Series struct {
+	ID          int       `db:"id" json:"id"`
+	UserID      int       `db:"user_id" json:"user_id"`
+	Title       string    `db:"title" json:"title"`
+	Description *string   `db:"description" json:"description"`
+	PostCount   int       `db:"post_count" json:"-"` // 已发布的文章数
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// ToResponse 转换为响应模型
+func (s *Series) ToResponse() SeriesResponse {
+	return SeriesResponse{
+		ID:          s.ID,
+		Title:       s.Title,
+		Description: s.Description,
+		PostCount:   s.PostCount,
+		CreatedAt:   s.CreatedAt,
+		UpdatedAt:   s.UpdatedAt,
+	}
+}
+
+// SeriesResponse 系列响应，详情中包含按顺序排列的文章
+type SeriesResponse struct {
+	ID          int                 `json:"id"`
+	Title       string              `json:"title"`
+	Description *string             `json:"description"`
+	PostCount   int                 `json:"post_count"`
+	User        *PublicUserResponse `json:"user"`
+	Posts       []PostResponse      `json:"posts,omitempty"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+}
+
+// PostSeriesInfo 文章详情中的系列信息和前后篇导航，位置从1开始
+type PostSeriesInfo struct {
+	ID       int             `json:"id"`
+	Title    string          `json:"title"`
+	Position int             `json:"position"`
+	Total    int             `json:"total"`
+	Prev     *SeriesPostLink `json:"prev"`
+	Next     *SeriesPostLink `json:"next"`
+}
+
+// SeriesPostLink 系列导航中的相邻文章
+type SeriesPostLink struct {
+	ID     int        `db:"id" json:"id"`
+	Title  string     `db:"title" json:"title"`
+	Slug   *string    `db:"slug" json:"slug"`
+	Status PostStatus `db:"status" json:"-"`
+}
+
+// SeriesRequest 创建或更新系列请求
+type SeriesRequest struct {
+	Title       string  `json:"title" binding:"required,max=255"`
+	Description *string `json:"description" binding:"omitempty,max=1000"`
+}
+
+// AddSeriesPostRequest 向系列末尾添加文章请求
+type AddSeriesPostRequest struct {
+	PostID int `json:"post_id" binding:"required"`
+}
+
+// ReorderPostsRequest 调整系列或合集中文章顺序的请求，未列出的文章排在最后
+type ReorderPostsRequest struct {
+	PostIDs []int `json:"post_ids" binding:"required"`
+}
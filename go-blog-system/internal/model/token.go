@@ -0,0 +1,72 @@
+package model
+
+import "time"
+
+// Session 登录会话，同一会话下轮换出的刷新令牌构成一个令牌族
+type Session struct {
+	ID         string     `db:"id" json:"id"`
+	UserID     int        `db:"user_id" json:"user_id"`
+	MFA        bool       `db:"mfa" json:"mfa"`
+	UserAgent  string     `db:"user_agent" json:"user_agent"`
+	IP         string     `db:"ip" json:"ip"`
+	LastSeenAt *time.Time `db:"last_seen_at" json:"last_seen_at"`
+	ExpiresAt  time.Time  `db:"expires_at" json:"expires_at"`
+	RevokedAt  *time.Time `db:"revoked_at" json:"revoked_at"`
+	CreatedAt  time.Time  `db:"created_at" json:"created_at"`
+}
+
+// ClientInfo 发起请求的客户端信息，由处理器填充
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+// SessionResponse 会话（登录设备）响应
+type SessionResponse struct {
+	ID         string     `json:"id"`
+	UserAgent  string     `json:"user_agent"`
+	IP         string     `json:"ip"`
+	MFA        bool       `json:"mfa"`
+	Current    bool       `json:"current"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastSeenAt *time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+}
+
+// RefreshToken 刷新令牌模型（仅保存哈希）
+type RefreshToken struct {
+	ID        int        `db:"id" json:"id"`
+	SessionID string     `db:"session_id" json:"session_id"`
+	TokenHash string     `db:"token_hash" json:"-"`
+	ExpiresAt time.Time  `db:"expires_at" json:"expires_at"`
+	UsedAt    *time.Time `db:"used_at" json:"used_at"`
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
+}
+
+// TokenPair 访问令牌与刷新令牌
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"` // 访问令牌有效期（秒）
+}
+
+// RefreshTokenRequest 刷新令牌请求
+type RefreshTokenRequest struct {
+	RefreshToken string     `json:"refresh_token" binding:"required"`
+	Client       ClientInfo `json:"-"`
+}
+
+// LoginResponse 登录响应，令牌字段与TokenPair一致；开启两步验证时只返回质询令牌
+type LoginResponse struct {
+	AccessToken    string        `json:"access_token,omitempty"`
+	Token          string        `json:"token,omitempty"` // 已弃用，与AccessToken相同，保留给旧的客户端
+	RefreshToken   string        `json:"refresh_token,omitempty"`
+	TokenType      string        `json:"token_type,omitempty"`
+	ExpiresIn      int64         `json:"expires_in"`
+	User           *UserResponse `json:"user,omitempty"`
+	MFARequired    bool          `json:"mfa_required,omitempty"`
+	ChallengeToken string        `json:"challenge_token,omitempty"`
+	// AccountRestored 登录时撤销了宽限期内的注销申请
+	AccountRestored bool `json:"account_restored,omitempty"`
+}
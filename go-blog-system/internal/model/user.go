@@ -4,38 +4,168 @@ import "time"
 
 // User 用户模型
 type User struct {
-	ID        int       `db:"id" json:"id"`
-	Username  string    `db:"username" json:"username"`
-	Email     string    `db:"email" json:"email"`
-	Password  string    `db:"password" json:"-"` // 不在JSON中返回密码
-	Avatar    *string   `db:"avatar" json:"avatar"`
-	Bio       *string   `db:"bio" json:"bio"`
-	CreatedAt time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	ID                 int        `db:"id" json:"id"`
+	Username           string     `db:"username" json:"username"`
+	Email              string     `db:"email" json:"email"`
+	EmailVerifiedAt    *time.Time `db:"email_verified_at" json:"email_verified_at"`
+	VerificationSentAt *time.Time `db:"verification_sent_at" json:"-"`
+	Password           string     `db:"password" json:"-"` // 不在JSON中返回密码
+	Role               Role       `db:"role" json:"role"`
+	TOTPSecret         *string    `db:"totp_secret" json:"-"`
+	TOTPEnabledAt      *time.Time `db:"totp_enabled_at" json:"-"`
+	TOTPLastStep       int64      `db:"totp_last_step" json:"-"`
+	Avatar             *string    `db:"avatar" json:"avatar"`
+	Bio                *string    `db:"bio" json:"bio"`
+	DeletedAt          *time.Time `db:"deleted_at" json:"deleted_at"`
+	DeletedBy          *int       `db:"deleted_by" json:"-"` // 注销操作人，本人注销时等于ID
+	AnonymizedAt       *time.Time `db:"anonymized_at" json:"anonymized_at"`
+	CreatedAt          time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt          time.Time  `db:"updated_at" json:"updated_at"`
 }
 
 // UserResponse 用户响应模型（不包含敏感信息）
 type UserResponse struct {
+	ID              int        `json:"id"`
+	Username        string     `json:"username"`
+	Email           string     `json:"email"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	Role            Role       `json:"role"`
+	Avatar          *string    `json:"avatar"`
+	Bio             *string    `json:"bio"`
+	Wallets         []string   `json:"wallets,omitempty"`
+	DeletedAt       *time.Time `json:"deleted_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// PublicUserResponse 公开的用户信息（不包含邮箱等私人信息），用于公开主页、文章和评论
+type PublicUserResponse struct {
 	ID        int       `json:"id"`
 	Username  string    `json:"username"`
-	Email     string    `json:"email"`
+	Role      Role      `json:"role"`
 	Avatar    *string   `json:"avatar"`
 	Bio       *string   `json:"bio"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// UserProfileResponse 用户公开主页
+type UserProfileResponse struct {
+	PublicUserResponse
+	Email          string        `json:"email,omitempty"` // 仅本人和管理员可见
+	PostCount      int           `json:"post_count"`
+	CommentCount   int           `json:"comment_count"`
+	FollowerCount  int           `json:"follower_count"`
+	FollowingCount int           `json:"following_count"`
+	RecentPosts    []PostSummary `json:"recent_posts"`
+}
+
+// PostSummary 文章摘要
+type PostSummary struct {
+	ID          int        `json:"id"`
+	Title       string     `json:"title"`
+	Slug        *string    `json:"slug"`
+	PublishedAt *time.Time `json:"published_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// UserStats 用户活跃度统计
+type UserStats struct {
+	PostCount      int `db:"post_count"`
+	CommentCount   int `db:"comment_count"`
+	FollowerCount  int `db:"follower_count"`
+	FollowingCount int `db:"following_count"`
+}
+
+// UserDirectoryEntry 用户目录条目
+type UserDirectoryEntry struct {
+	ID           int       `db:"id" json:"id"`
+	Username     string    `db:"username" json:"username"`
+	Role         Role      `db:"role" json:"role"`
+	Avatar       *string   `db:"avatar" json:"avatar"`
+	Bio          *string   `db:"bio" json:"bio"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+	PostCount    int       `db:"post_count" json:"post_count"`
+	CommentCount int       `db:"comment_count" json:"comment_count"`
+	LastActiveAt time.Time `db:"last_active_at" json:"last_active_at"`
+}
+
+// UserDirectorySort 用户目录排序方式
+type UserDirectorySort string
+
+const (
+	UserSortNewest   UserDirectorySort = "newest"   // 按注册时间倒序
+	UserSortUsername UserDirectorySort = "username" // 按用户名字母顺序
+	UserSortPosts    UserDirectorySort = "posts"    // 按已发布文章数倒序
+	UserSortActive   UserDirectorySort = "active"   // 按最近活跃时间倒序
+)
+
+// UserDirectoryQuery 用户目录查询参数
+type UserDirectoryQuery struct {
+	PageQuery
+	Prefix string            `form:"prefix"`
+	Sort   UserDirectorySort `form:"sort" binding:"omitempty,oneof=newest username posts active"`
+}
+
 // ToResponse 转换为响应模型
 func (u *User) ToResponse() UserResponse {
 	return UserResponse{
+		ID:              u.ID,
+		Username:        u.Username,
+		Email:           u.Email,
+		EmailVerifiedAt: u.EmailVerifiedAt,
+		Role:            u.Role,
+		Avatar:          u.Avatar,
+		Bio:             u.Bio,
+		DeletedAt:       u.DeletedAt,
+		CreatedAt:       u.CreatedAt,
+	}
+}
+
+// ToPublicResponse 转换为公开的响应模型
+func (u *User) ToPublicResponse() *PublicUserResponse {
+	return &PublicUserResponse{
 		ID:        u.ID,
 		Username:  u.Username,
-		Email:     u.Email,
+		Role:      u.Role,
 		Avatar:    u.Avatar,
 		Bio:       u.Bio,
 		CreatedAt: u.CreatedAt,
 	}
 }
 
+// IsEmailVerified 邮箱是否已验证
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// IsMFAEnabled 是否已开启两步验证
+func (u *User) IsMFAEnabled() bool {
+	return u.TOTPEnabledAt != nil
+}
+
+// IsDeleted 账号是否已注销（包括宽限期内和已匿名化）
+func (u *User) IsDeleted() bool {
+	return u.DeletedAt != nil
+}
+
+// IsSelfDeleted 账号是否由本人注销（宽限期内可以通过登录恢复）
+func (u *User) IsSelfDeleted() bool {
+	return u.DeletedBy != nil && *u.DeletedBy == u.ID
+}
+
+// IsAnonymized 账号是否已被匿名化（不可恢复）
+func (u *User) IsAnonymized() bool {
+	return u.AnonymizedAt != nil
+}
+
+// DeletedPostsPolicy 匿名化注销用户时对其文章的处理策略
+type DeletedPostsPolicy string
+
+const (
+	DeletedPostsKeep     DeletedPostsPolicy = "keep"     // 保留文章，作者显示为匿名用户
+	DeletedPostsReassign DeletedPostsPolicy = "reassign" // 将文章转给指定用户
+	DeletedPostsDelete   DeletedPostsPolicy = "delete"   // 删除文章
+)
+
 // CreateUserRequest 创建用户请求
 type CreateUserRequest struct {
 	Username string  `json:"username" binding:"required,min=3,max=50"`
@@ -47,8 +177,9 @@ type CreateUserRequest struct {
 
 // LoginRequest 登录请求
 type LoginRequest struct {
-	Username string `json:"username" binding:"required"`
-	Password string `json:"password" binding:"required"`
+	Username string     `json:"username" binding:"required"`
+	Password string     `json:"password" binding:"required"`
+	Client   ClientInfo `json:"-"`
 }
 
 // UpdateUserRequest 更新用户请求
@@ -56,4 +187,17 @@ type UpdateUserRequest struct {
 	Email  *string `json:"email" binding:"omitempty,email"`
 	Avatar *string `json:"avatar"`
 	Bio    *string `json:"bio"`
-}
\ No newline at end of file
+}
+
+// VerifyEmailRequest 邮箱验证请求
+type VerifyEmailRequest struct {
+	Token string `form:"token" binding:"required"`
+}
+
+// ChangePasswordRequest 修改密码请求
+type ChangePasswordRequest struct {
+	OldPassword string     `json:"old_password" binding:"required"`
+	NewPassword string     `json:"new_password" binding:"required,min=6"`
+	Client      ClientInfo `json:"-"`
+	MFA         bool       `json:"-"` // 当前会话是否通过了两步验证，新令牌沿用
+}
@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// Wallet 用户绑定的以太坊钱包
+type Wallet struct {
+	ID        int       `db:"id" json:"id"`
+	UserID    int       `db:"user_id" json:"user_id"`
+	Address   string    `db:"address" json:"address"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// SIWENonce 以太坊登录随机数
+type SIWENonce struct {
+	Nonce     string     `db:"nonce" json:"nonce"`
+	ExpiresAt time.Time  `db:"expires_at" json:"expires_at"`
+	UsedAt    *time.Time `db:"used_at" json:"used_at"`
+}
+
+// SIWERequest 以太坊登录/绑定钱包请求
+type SIWERequest struct {
+	Message   string     `json:"message" binding:"required"`
+	Signature string     `json:"signature" binding:"required"`
+	Client    ClientInfo `json:"-"`
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// BookmarkRepository 收藏仓库接口
+type BookmarkRepository interface {
+	Add(userID, postID int) error
+	Remove(userID, postID int) error
+	List(userID int, p *model.PageQuery) (*model.Page[model.Bookmark], error)
+	Count(userID int) (int, error)
+	GetBookmarked(userID int, postIDs []int) (map[int]bool, error)
+	ListAll(userID int) ([]model.BookmarkedPost, error)
+}
+
+// bookmarkRepository 收藏仓库实现
+type bookmarkRepository struct {
+	db *sqlx.DB
+}
+
+// NewBookmarkRepository 创建收藏仓库
+func NewBookmarkRepository(db *sqlx.DB) BookmarkRepository {
+	return &bookmarkRepository{db: db}
+}
+
+// bookmarkVisible 收藏列表只包含已发布的文章和用户自己的文章
+const bookmarkVisible = `(p.status = 'published' OR p.user_id = b.user_id)`
+
+// Add 收藏文章（重复收藏不报错）
+func (r *bookmarkRepository) Add(userID, postID int) error {
+	query := `INSERT IGNORE INTO bookmarks (user_id, post_id) VALUES (?, ?)`
+
+	_, err := r.db.Exec(query, userID, postID)
+	if err != nil {
+		return fmt.Errorf("failed to add bookmark: %w", err)
+	}
+
+	return nil
+}
+
+// Remove 取消收藏
+func (r *bookmarkRepository) Remove(userID, postID int) error {
+	query := `DELETE FROM bookmarks WHERE user_id = ? AND post_id = ?`
+
+	_, err := r.db.Exec(query, userID, postID)
+	if err != nil {
+		return fmt.Errorf("failed to remove bookmark: %w", err)
+	}
+
+	return nil
+}
+
+// List 分页获取用户收藏的文章，最近收藏的在前
+func (r *bookmarkRepository) List(userID int, p *model.PageQuery) (*model.Page[model.Bookmark], error) {
+	query := `SELECT p.*, b.created_at AS bookmarked_at FROM bookmarks b
+			  JOIN posts p ON p.id = b.post_id
+			  WHERE b.user_id = ? AND ` + bookmarkVisible
+
+	page, err := bookmarksByCreated.list(r.db, query, []interface{}{userID}, p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bookmarks: %w", err)
+	}
+
+	return page, nil
+}
+
+// bookmarksByCreated 收藏列表按收藏时间倒序分页
+var bookmarksByCreated = keyset[model.Bookmark]{
+	name:     "bookmarked",
+	column:   "b.created_at",
+	idColumn: "b.post_id",
+	kind:     keyTime,
+	desc:     true,
+	value:    func(b *model.Bookmark) interface{} { return b.BookmarkedAt },
+	id:       func(b *model.Bookmark) int { return b.ID },
+}
+
+// Count 统计用户收藏的文章数
+func (r *bookmarkRepository) Count(userID int) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM bookmarks b
+			  JOIN posts p ON p.id = b.post_id
+			  WHERE b.user_id = ? AND ` + bookmarkVisible
+
+	err := r.db.Get(&count, query, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count bookmarks: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetBookmarked 批量查询用户是否收藏了指定文章
+func (r *bookmarkRepository) GetBookmarked(userID int, postIDs []int) (map[int]bool, error) {
+	result := make(map[int]bool, len(postIDs))
+	if len(postIDs) == 0 {
+		return result, nil
+	}
+
+	query, args, err := sqlx.In(`SELECT post_id FROM bookmarks WHERE user_id = ? AND post_id IN (?)`, userID, postIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build bookmarks query: %w", err)
+	}
+
+	var ids []int
+	if err := r.db.Select(&ids, r.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("failed to get bookmarks: %w", err)
+	}
+
+	for _, id := range ids {
+		result[id] = true
+	}
+
+	return result, nil
+}
+
+// ListAll 获取用户的全部收藏记录，最近收藏的在前
+func (r *bookmarkRepository) ListAll(userID int) ([]model.BookmarkedPost, error) {
+	var bookmarks []model.BookmarkedPost
+	query := `SELECT b.post_id, p.title, b.created_at AS bookmarked_at FROM bookmarks b
+			  JOIN posts p ON p.id = b.post_id
+			  WHERE b.user_id = ? AND ` + bookmarkVisible + `
+			  ORDER BY b.created_at DESC, b.post_id DESC`
+
+	err := r.db.Select(&bookmarks, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bookmarks: %w", err)
+	}
+
+	return bookmarks, nil
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// collectionColumns 查询合集时同时统计已发布的文章数
+const collectionColumns = `c.*, (SELECT COUNT(*) FROM collection_posts cp JOIN posts p ON p.id = cp.post_id
+	WHERE cp.collection_id = c.id AND p.status = 'published') AS post_count`
+
+// CollectionRepository 合集仓库接口
+type CollectionRepository interface {
+	Create(collection *model.Collection) error
+	GetByID(id int) (*model.Collection, error)
+	ListByUserID(userID int) ([]model.Collection, error)
+	Update(collection *model.Collection) error
+	Delete(id int) error
+	HasPost(id, postID int) (bool, error)
+	GetEntries(id int) ([]model.CollectionEntry, error)
+	AddPost(id, postID int, note *string) (bool, error)
+	RemovePost(id, postID int) error
+	Reorder(id int, postIDs []int) error
+}
+
+// collectionRepository 合集仓库实现
+type collectionRepository struct {
+	db *sqlx.DB
+}
+
+// NewCollectionRepository 创建合集仓库
+func NewCollectionRepository(db *sqlx.DB) CollectionRepository {
+	return &collectionRepository{db: db}
+}
+
+// Create 创建合集
+func (r *collectionRepository) Create(collection *model.Collection) error {
+	query := `INSERT INTO collections (user_id, title, description) VALUES (?, ?, ?)`
+
+	result, err := r.db.Exec(query, collection.UserID, collection.Title, collection.Description)
+	if err != nil {
+		return fmt.Errorf("failed to create collection: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	collection.ID = int(id)
+	return nil
+}
+
+// GetByID 根据ID获取合集
+func (r *collectionRepository) GetByID(id int) (*model.Collection, error) {
+	var collection model.Collection
+	query := `SELECT ` + collectionColumns + ` FROM collections c WHERE c.id = ?`
+
+	err := r.db.Get(&collection, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collection by id: %w", err)
+	}
+
+	return &collection, nil
+}
+
+// ListByUserID 获取用户的全部合集，按创建时间倒序
+func (r *collectionRepository) ListByUserID(userID int) ([]model.Collection, error) {
+	var collections []model.Collection
+	query := `SELECT ` + collectionColumns + ` FROM collections c WHERE c.user_id = ? ORDER BY c.created_at DESC, c.id DESC`
+
+	err := r.db.Select(&collections, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collections: %w", err)
+	}
+
+	return collections, nil
+}
+
+// Update 更新合集
+func (r *collectionRepository) Update(collection *model.Collection) error {
+	query := `UPDATE collections SET title = ?, description = ? WHERE id = ?`
+
+	_, err := r.db.Exec(query, collection.Title, collection.Description, collection.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update collection: %w", err)
+	}
+
+	return nil
+}
+
+// Delete 删除合集
+func (r *collectionRepository) Delete(id int) error {
+	query := `DELETE FROM collections WHERE id = ?`
+
+	_, err := r.db.Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete collection: %w", err)
+	}
+
+	return nil
+}
+
+// HasPost 文章是否已在合集中
+func (r *collectionRepository) HasPost(id, postID int) (bool, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM collection_posts WHERE collection_id = ? AND post_id = ?`
+
+	err := r.db.Get(&count, query, id, postID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check collection post: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// GetEntries 按顺序获取合集中已发布的文章，作者撤回发布的文章不再展示
+func (r *collectionRepository) GetEntries(id int) ([]model.CollectionEntry, error) {
+	entries := []model.CollectionEntry{}
+	query := `SELECT p.*, cp.note, cp.created_at AS added_at FROM collection_posts cp
+			  JOIN posts p ON p.id = cp.post_id
+			  WHERE cp.collection_id = ? AND p.status = 'published'
+			  ORDER BY cp.position, cp.created_at`
+
+	err := r.db.Select(&entries, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collection posts: %w", err)
+	}
+
+	return entries, nil
+}
+
+// AddPost 将文章添加到合集末尾，合集已满时返回false
+func (r *collectionRepository) AddPost(id, postID int, note *string) (bool, error) {
+	return collectionPosts.add(r.db, id, model.MaxCollectionPosts, func(tx *sqlx.Tx, position int) error {
+		query := `INSERT INTO collection_posts (collection_id, post_id, position, note) VALUES (?, ?, ?, ?)`
+
+		if _, err := tx.Exec(query, id, postID, position, note); err != nil {
+			return fmt.Errorf("failed to add post to collection: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// RemovePost 从合集中移除文章
+func (r *collectionRepository) RemovePost(id, postID int) error {
+	return collectionPosts.remove(r.db, id, postID)
+}
+
+// Reorder 调整合集中文章的顺序
+func (r *collectionRepository) Reorder(id int, postIDs []int) error {
+	return collectionPosts.reorder(r.db, id, postIDs)
+}
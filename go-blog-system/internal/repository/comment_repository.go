@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 
 	"github.com/duanyu/go-blog-system/internal/model"
@@ -13,8 +15,10 @@ type CommentRepository interface {
 	GetByID(id int) (*model.Comment, error)
 	Update(comment *model.Comment) error
 	Delete(id int) error
-	GetByPostID(postID int) ([]model.Comment, error)
+	GetByPostID(postID int, p *model.PageQuery) (*model.Page[model.Comment], error)
+	CountByPostID(postID int) (int, error)
 	GetReplies(commentID int) ([]model.Comment, error)
+	GetByUserID(userID int) ([]model.Comment, error)
 }
 
 // commentRepository 评论仓库实现
@@ -29,10 +33,16 @@ func NewCommentRepository(db *sqlx.DB) CommentRepository {
 
 // Create 创建评论
 func (r *commentRepository) Create(comment *model.Comment) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
 	query := `INSERT INTO comments (content, user_id, post_id, parent_id) 
 			VALUES (?, ?, ?, ?)`
 
-	result, err := r.db.Exec(query, comment.Content, comment.UserID, comment.PostID, comment.ParentID)
+	result, err := tx.Exec(query, comment.Content, comment.UserID, comment.PostID, comment.ParentID)
 	if err != nil {
 		return fmt.Errorf("failed to create comment: %w", err)
 	}
@@ -42,6 +52,16 @@ func (r *commentRepository) Create(comment *model.Comment) error {
 		return fmt.Errorf("failed to get last insert id: %w", err)
 	}
 
+	// 评论数与评论在同一事务中原子更新
+	_, err = tx.Exec(`UPDATE posts SET comment_count = comment_count + 1, updated_at = updated_at WHERE id = ?`, comment.PostID)
+	if err != nil {
+		return fmt.Errorf("failed to update comment count: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	comment.ID = int(id)
 	return nil
 }
@@ -71,29 +91,77 @@ func (r *commentRepository) Update(comment *model.Comment) error {
 	return nil
 }
 
-// Delete 删除评论
+// Delete 删除评论（回复保留并成为顶级评论）
 func (r *commentRepository) Delete(id int) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 锁定评论行，并发删除同一评论时只有一个事务会扣减评论数
+	var postID int
+	err = tx.Get(&postID, `SELECT post_id FROM comments WHERE id = ? FOR UPDATE`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get comment: %w", err)
+	}
+
+	_, err = tx.Exec(`UPDATE posts SET comment_count = comment_count - 1, updated_at = updated_at WHERE id = ?`, postID)
+	if err != nil {
+		return fmt.Errorf("failed to update comment count: %w", err)
+	}
+
 	query := `DELETE FROM comments WHERE id = ?`
 
-	_, err := r.db.Exec(query, id)
+	_, err = tx.Exec(query, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete comment: %w", err)
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return nil
 }
 
-// GetByPostID 获取文章的所有顶级评论（不包括回复）
-func (r *commentRepository) GetByPostID(postID int) ([]model.Comment, error) {
-	query := `SELECT * FROM comments WHERE post_id = ? AND parent_id IS NULL ORDER BY created_at DESC`
+// GetByPostID 分页获取文章的顶级评论（不包括回复），最新的在前
+func (r *commentRepository) GetByPostID(postID int, p *model.PageQuery) (*model.Page[model.Comment], error) {
+	query := `SELECT * FROM comments WHERE post_id = ? AND parent_id IS NULL`
 
-	var comments []model.Comment
-	err := r.db.Select(&comments, query, postID)
+	page, err := commentsByCreated.list(r.db, query, []interface{}{postID}, p)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get comments by post id: %w", err)
 	}
 
-	return comments, nil
+	return page, nil
+}
+
+// CountByPostID 获取文章的顶级评论数
+func (r *commentRepository) CountByPostID(postID int) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM comments WHERE post_id = ? AND parent_id IS NULL`
+
+	err := r.db.Get(&count, query, postID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count comments: %w", err)
+	}
+
+	return count, nil
+}
+
+// commentsByCreated 评论按创建时间倒序分页
+var commentsByCreated = keyset[model.Comment]{
+	name:     "created",
+	column:   "created_at",
+	idColumn: "id",
+	kind:     keyTime,
+	desc:     true,
+	value:    func(c *model.Comment) interface{} { return c.CreatedAt },
+	id:       func(c *model.Comment) int { return c.ID },
 }
 
 // GetReplies 获取评论的所有回复
@@ -107,4 +175,17 @@ func (r *commentRepository) GetReplies(commentID int) ([]model.Comment, error) {
 	}
 
 	return replies, nil
+}
+
+// GetByUserID 获取用户发表的所有评论
+func (r *commentRepository) GetByUserID(userID int) ([]model.Comment, error) {
+	query := `SELECT * FROM comments WHERE user_id = ? ORDER BY created_at ASC`
+
+	var comments []model.Comment
+	err := r.db.Select(&comments, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comments by user id: %w", err)
+	}
+
+	return comments, nil
 }
\ No newline at end of file
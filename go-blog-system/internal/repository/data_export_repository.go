@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// DataExportRepository 数据导出仓库接口
+type DataExportRepository interface {
+	Create(export *model.DataExport) error
+	GetByID(id int) (*model.DataExport, error)
+	GetLatestByUserID(userID int) (*model.DataExport, error)
+	UpdateStatus(id int, status model.DataExportStatus) error
+	Complete(id int, filePath string, expiresAt time.Time) error
+	Fail(id int, message string) error
+	FailInProgress(instanceID, message string) error
+	ListExpired(before time.Time) ([]model.DataExport, error)
+	Delete(id int) error
+}
+
+// dataExportRepository 数据导出仓库实现
+type dataExportRepository struct {
+	db *sqlx.DB
+}
+
+// NewDataExportRepository 创建数据导出仓库
+func NewDataExportRepository(db *sqlx.DB) DataExportRepository {
+	return &dataExportRepository{db: db}
+}
+
+// Create 创建导出任务
+func (r *dataExportRepository) Create(export *model.DataExport) error {
+	query := `INSERT INTO data_exports (user_id, status, instance_id) VALUES (?, ?, ?)`
+
+	result, err := r.db.Exec(query, export.UserID, export.Status, export.InstanceID)
+	if err != nil {
+		return fmt.Errorf("failed to create data export: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	export.ID = int(id)
+	return nil
+}
+
+// GetByID 根据ID获取导出任务
+func (r *dataExportRepository) GetByID(id int) (*model.DataExport, error) {
+	var export model.DataExport
+	query := `SELECT * FROM data_exports WHERE id = ?`
+
+	err := r.db.Get(&export, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data export by id: %w", err)
+	}
+
+	return &export, nil
+}
+
+// GetLatestByUserID 获取用户最近一次导出任务
+func (r *dataExportRepository) GetLatestByUserID(userID int) (*model.DataExport, error) {
+	var export model.DataExport
+	query := `SELECT * FROM data_exports WHERE user_id = ? ORDER BY id DESC LIMIT 1`
+
+	err := r.db.Get(&export, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest data export: %w", err)
+	}
+
+	return &export, nil
+}
+
+// UpdateStatus 更新导出任务状态
+func (r *dataExportRepository) UpdateStatus(id int, status model.DataExportStatus) error {
+	query := `UPDATE data_exports SET status = ? WHERE id = ?`
+
+	_, err := r.db.Exec(query, status, id)
+	if err != nil {
+		return fmt.Errorf("failed to update data export status: %w", err)
+	}
+
+	return nil
+}
+
+// Complete 标记导出完成
+func (r *dataExportRepository) Complete(id int, filePath string, expiresAt time.Time) error {
+	query := `UPDATE data_exports SET status = ?, file_path = ?, expires_at = ?, completed_at = ? WHERE id = ?`
+
+	_, err := r.db.Exec(query, model.DataExportCompleted, filePath, expiresAt, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to complete data export: %w", err)
+	}
+
+	return nil
+}
+
+// Fail 标记导出失败
+func (r *dataExportRepository) Fail(id int, message string) error {
+	query := `UPDATE data_exports SET status = ?, error = ?, completed_at = ? WHERE id = ?`
+
+	_, err := r.db.Exec(query, model.DataExportFailed, message, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to mark data export failed: %w", err)
+	}
+
+	return nil
+}
+
+// FailInProgress 将指定实例进行中的导出标记为失败（用于服务重启后清理中断的任务）
+func (r *dataExportRepository) FailInProgress(instanceID, message string) error {
+	query := `UPDATE data_exports SET status = ?, error = ?, completed_at = ? WHERE instance_id = ? AND status IN (?, ?)`
+
+	_, err := r.db.Exec(
+		query,
+		model.DataExportFailed,
+		message,
+		time.Now(),
+		instanceID,
+		model.DataExportPending,
+		model.DataExportProcessing,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to fail in-progress data exports: %w", err)
+	}
+
+	return nil
+}
+
+// ListExpired 获取已过期的导出任务
+func (r *dataExportRepository) ListExpired(before time.Time) ([]model.DataExport, error) {
+	query := `SELECT * FROM data_exports WHERE expires_at IS NOT NULL AND expires_at < ?`
+
+	var exports []model.DataExport
+	err := r.db.Select(&exports, query, before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired data exports: %w", err)
+	}
+
+	return exports, nil
+}
+
+// Delete 删除导出任务
+func (r *dataExportRepository) Delete(id int) error {
+	query := `DELETE FROM data_exports WHERE id = ?`
+
+	_, err := r.db.Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete data export: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/mysql"
+	"github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/jmoiron/sqlx"
+)
+
+// testDSNEnv 集成测试使用的数据库连接串，例如
+// blog:secret@tcp(127.0.0.1:3306)/blog_test?parseTime=True&loc=UTC&multiStatements=true
+const testDSNEnv = "BLOG_TEST_DATABASE_DSN"
+
+// openTestDB 连接测试数据库并执行全部迁移，未设置testDSNEnv时跳过测试。
+// 测试会写入数据，不要指向正在使用的数据库
+func openTestDB(t *testing.T) *sqlx.DB {
+	t.Helper()
+
+	dsn := os.Getenv(testDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testDSNEnv)
+	}
+
+	db, err := sqlx.Connect("mysql", dsn)
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	driver, err := mysql.WithInstance(db.DB, &mysql.Config{})
+	if err != nil {
+		t.Fatalf("failed to create migration driver: %v", err)
+	}
+	source, err := (&file.File{}).Open("file://../../migrations")
+	if err != nil {
+		t.Fatalf("failed to open migration files: %v", err)
+	}
+	m, err := migrate.NewWithInstance("file", source, "mysql", driver)
+	if err != nil {
+		t.Fatalf("failed to create migration instance: %v", err)
+	}
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	return db
+}
+
+// createTestUsers 创建n个测试用户，返回用户ID
+func createTestUsers(t *testing.T, db *sqlx.DB, n int) []int {
+	t.Helper()
+
+	prefix := fmt.Sprintf("t%d", time.Now().UnixNano())
+	ids := make([]int, n)
+	for i := range ids {
+		name := fmt.Sprintf("%s_%d", prefix, i)
+		result, err := db.Exec(`INSERT INTO users (username, email, password) VALUES (?, ?, '')`, name, name+"@example.com")
+		if err != nil {
+			t.Fatalf("failed to create test user: %v", err)
+		}
+		id, _ := result.LastInsertId()
+		ids[i] = int(id)
+	}
+
+	return ids
+}
+
+// createTestPost 创建一篇测试文章，返回文章ID
+func createTestPost(t *testing.T, db *sqlx.DB, userID int) int {
+	t.Helper()
+
+	result, err := db.Exec(`INSERT INTO posts (title, content, user_id) VALUES ('test', 'test', ?)`, userID)
+	if err != nil {
+		t.Fatalf("failed to create test post: %v", err)
+	}
+	id, _ := result.LastInsertId()
+
+	return int(id)
+}
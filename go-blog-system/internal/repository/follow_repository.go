@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// FollowRepository 关注关系仓库接口
+type FollowRepository interface {
+	FollowUser(followerID, followeeID int) error
+	UnfollowUser(followerID, followeeID int) error
+	FollowTag(userID, tagID int) error
+	UnfollowTag(userID, tagID int) error
+	ListFollowedUsers(userID int) ([]model.FollowedUser, error)
+	ListFollowedTags(userID int) ([]model.FollowedTag, error)
+}
+
+// followRepository 关注关系仓库实现
+type followRepository struct {
+	db *sqlx.DB
+}
+
+// NewFollowRepository 创建关注关系仓库
+func NewFollowRepository(db *sqlx.DB) FollowRepository {
+	return &followRepository{db: db}
+}
+
+// FollowUser 关注用户（重复关注不报错）
+func (r *followRepository) FollowUser(followerID, followeeID int) error {
+	query := `INSERT IGNORE INTO user_follows (follower_id, followee_id) VALUES (?, ?)`
+
+	_, err := r.db.Exec(query, followerID, followeeID)
+	if err != nil {
+		return fmt.Errorf("failed to follow user: %w", err)
+	}
+
+	return nil
+}
+
+// UnfollowUser 取消关注用户
+func (r *followRepository) UnfollowUser(followerID, followeeID int) error {
+	query := `DELETE FROM user_follows WHERE follower_id = ? AND followee_id = ?`
+
+	_, err := r.db.Exec(query, followerID, followeeID)
+	if err != nil {
+		return fmt.Errorf("failed to unfollow user: %w", err)
+	}
+
+	return nil
+}
+
+// FollowTag 关注标签（重复关注不报错）
+func (r *followRepository) FollowTag(userID, tagID int) error {
+	query := `INSERT IGNORE INTO tag_follows (user_id, tag_id) VALUES (?, ?)`
+
+	_, err := r.db.Exec(query, userID, tagID)
+	if err != nil {
+		return fmt.Errorf("failed to follow tag: %w", err)
+	}
+
+	return nil
+}
+
+// UnfollowTag 取消关注标签
+func (r *followRepository) UnfollowTag(userID, tagID int) error {
+	query := `DELETE FROM tag_follows WHERE user_id = ? AND tag_id = ?`
+
+	_, err := r.db.Exec(query, userID, tagID)
+	if err != nil {
+		return fmt.Errorf("failed to unfollow tag: %w", err)
+	}
+
+	return nil
+}
+
+// ListFollowedUsers 获取用户关注的全部用户，最近关注的在前
+func (r *followRepository) ListFollowedUsers(userID int) ([]model.FollowedUser, error) {
+	var users []model.FollowedUser
+	query := `SELECT u.id AS user_id, u.username, f.created_at AS followed_at FROM user_follows f
+			  JOIN users u ON u.id = f.followee_id
+			  WHERE f.follower_id = ? ORDER BY f.created_at DESC, u.id DESC`
+
+	err := r.db.Select(&users, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list followed users: %w", err)
+	}
+
+	return users, nil
+}
+
+// ListFollowedTags 获取用户关注的全部标签，最近关注的在前
+func (r *followRepository) ListFollowedTags(userID int) ([]model.FollowedTag, error) {
+	var tags []model.FollowedTag
+	query := `SELECT t.id AS tag_id, t.name, f.created_at AS followed_at FROM tag_follows f
+			  JOIN tags t ON t.id = f.tag_id
+			  WHERE f.user_id = ? ORDER BY f.created_at DESC, t.id DESC`
+
+	err := r.db.Select(&tags, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list followed tags: %w", err)
+	}
+
+	return tags, nil
+}
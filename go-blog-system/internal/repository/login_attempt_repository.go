@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// LoginAttemptRepository 登录失败记录仓库接口
+type LoginAttemptRepository interface {
+	Get(key string) (*model.LoginAttempt, error)
+	IncrementFailures(key string, at, resetBefore time.Time) (*model.LoginAttempt, error)
+	SetBlockedUntil(key string, until time.Time) error
+	Delete(key string) error
+	DeleteExpired(before time.Time) error
+}
+
+// loginAttemptRepository 基于数据库的登录失败记录仓库，适用于多实例部署
+type loginAttemptRepository struct {
+	db *sqlx.DB
+}
+
+// NewLoginAttemptRepository 创建基于数据库的登录失败记录仓库
+func NewLoginAttemptRepository(db *sqlx.DB) LoginAttemptRepository {
+	return &loginAttemptRepository{db: db}
+}
+
+// Get 获取登录失败记录，不存在时返回nil
+func (r *loginAttemptRepository) Get(key string) (*model.LoginAttempt, error) {
+	var attempt model.LoginAttempt
+	query := `SELECT * FROM login_attempts WHERE attempt_key = ?`
+
+	err := r.db.Get(&attempt, query, key)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get login attempt: %w", err)
+	}
+
+	return &attempt, nil
+}
+
+// IncrementFailures 原子地增加失败次数，上次失败早于resetBefore时重新计数
+func (r *loginAttemptRepository) IncrementFailures(key string, at, resetBefore time.Time) (*model.LoginAttempt, error) {
+	query := `INSERT INTO login_attempts (attempt_key, failures, last_failure_at) VALUES (?, 1, ?)
+			  ON DUPLICATE KEY UPDATE
+			  failures = IF(last_failure_at < ?, 1, failures + 1),
+			  blocked_until = IF(last_failure_at < ?, NULL, blocked_until),
+			  last_failure_at = VALUES(last_failure_at)`
+
+	if _, err := r.db.Exec(query, key, at, resetBefore, resetBefore); err != nil {
+		return nil, fmt.Errorf("failed to record login failure: %w", err)
+	}
+
+	return r.Get(key)
+}
+
+// SetBlockedUntil 设置禁止登录的截止时间
+func (r *loginAttemptRepository) SetBlockedUntil(key string, until time.Time) error {
+	query := `UPDATE login_attempts SET blocked_until = ? WHERE attempt_key = ?`
+
+	_, err := r.db.Exec(query, until, key)
+	if err != nil {
+		return fmt.Errorf("failed to set login attempt blocked until: %w", err)
+	}
+
+	return nil
+}
+
+// Delete 删除登录失败记录
+func (r *loginAttemptRepository) Delete(key string) error {
+	query := `DELETE FROM login_attempts WHERE attempt_key = ?`
+
+	_, err := r.db.Exec(query, key)
+	if err != nil {
+		return fmt.Errorf("failed to delete login attempt: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteExpired 清理早于before且已解除限制的记录
+func (r *loginAttemptRepository) DeleteExpired(before time.Time) error {
+	query := `DELETE FROM login_attempts
+			  WHERE last_failure_at < ? AND (blocked_until IS NULL OR blocked_until < ?)`
+
+	_, err := r.db.Exec(query, before, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to delete expired login attempts: %w", err)
+	}
+
+	return nil
+}
+
+// memoryLoginAttemptRepository 基于内存的登录失败记录仓库，仅适用于单实例部署
+type memoryLoginAttemptRepository struct {
+	mu       sync.Mutex
+	attempts map[string]model.LoginAttempt
+}
+
+// NewMemoryLoginAttemptRepository 创建基于内存的登录失败记录仓库
+func NewMemoryLoginAttemptRepository() LoginAttemptRepository {
+	return &memoryLoginAttemptRepository{attempts: make(map[string]model.LoginAttempt)}
+}
+
+// Get 获取登录失败记录，不存在时返回nil
+func (r *memoryLoginAttemptRepository) Get(key string) (*model.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempt, ok := r.attempts[key]
+	if !ok {
+		return nil, nil
+	}
+
+	return &attempt, nil
+}
+
+// IncrementFailures 增加失败次数，上次失败早于resetBefore时重新计数
+func (r *memoryLoginAttemptRepository) IncrementFailures(key string, at, resetBefore time.Time) (*model.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempt, ok := r.attempts[key]
+	if !ok || attempt.LastFailureAt.Before(resetBefore) {
+		attempt = model.LoginAttempt{Key: key}
+	}
+
+	attempt.Failures++
+	attempt.LastFailureAt = at
+	r.attempts[key] = attempt
+
+	return &attempt, nil
+}
+
+// SetBlockedUntil 设置禁止登录的截止时间
+func (r *memoryLoginAttemptRepository) SetBlockedUntil(key string, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if attempt, ok := r.attempts[key]; ok {
+		attempt.BlockedUntil = &until
+		r.attempts[key] = attempt
+	}
+
+	return nil
+}
+
+// Delete 删除登录失败记录
+func (r *memoryLoginAttemptRepository) Delete(key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.attempts, key)
+	return nil
+}
+
+// DeleteExpired 清理早于before且已解除限制的记录
+func (r *memoryLoginAttemptRepository) DeleteExpired(before time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for key, attempt := range r.attempts {
+		if attempt.LastFailureAt.Before(before) && (attempt.BlockedUntil == nil || attempt.BlockedUntil.Before(now)) {
+			delete(r.attempts, key)
+		}
+	}
+
+	return nil
+}
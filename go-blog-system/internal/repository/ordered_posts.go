@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// orderedPosts 系列和合集共用的有序文章列表，修改顺序时锁定所属的系列或合集行，
+// 避免并发添加和调整顺序时位置冲突
+type orderedPosts struct {
+	parent string // 系列或合集表
+	table  string // 文章关联表
+	column string // 关联表中指向parent的列
+}
+
+var (
+	seriesPosts     = orderedPosts{parent: "series", table: "series_posts", column: "series_id"}
+	collectionPosts = orderedPosts{parent: "collections", table: "collection_posts", column: "collection_id"}
+)
+
+// add 在事务中锁定列表并把文章追加到末尾，insert负责以给定位置插入关联记录。
+// 数量检查与插入在同一事务中进行，列表已有limit篇文章时返回false
+func (o orderedPosts) add(db *sqlx.DB, id, limit int, insert func(tx *sqlx.Tx, position int) error) (bool, error) {
+	tx, err := db.Beginx()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := o.lock(tx, id); err != nil {
+		return false, err
+	}
+
+	var next struct {
+		Count    int `db:"count"`
+		Position int `db:"position"`
+	}
+	query := fmt.Sprintf(`SELECT COUNT(*) AS count, COALESCE(MAX(position), 0) + 1 AS position FROM %s WHERE %s = ?`, o.table, o.column)
+	if err := tx.Get(&next, query, id); err != nil {
+		return false, fmt.Errorf("failed to get next position: %w", err)
+	}
+	if next.Count >= limit {
+		return false, nil
+	}
+
+	if err := insert(tx, next.Position); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+// remove 从列表中移除文章，剩余文章的相对顺序不变
+func (o orderedPosts) remove(db *sqlx.DB, id, postID int) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE %s = ? AND post_id = ?`, o.table, o.column)
+
+	_, err := db.Exec(query, id, postID)
+	if err != nil {
+		return fmt.Errorf("failed to remove post from %s: %w", o.parent, err)
+	}
+
+	return nil
+}
+
+// reorder 按postIDs重新编号。postIDs只能包含列表中的文章且不能重复，
+// 未列出的文章（如合集中已下线的文章）保持原有相对顺序排在最后
+func (o orderedPosts) reorder(db *sqlx.DB, id int, postIDs []int) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := o.lock(tx, id); err != nil {
+		return err
+	}
+
+	var current []int
+	query := fmt.Sprintf(`SELECT post_id FROM %s WHERE %s = ? ORDER BY position, post_id`, o.table, o.column)
+	if err := tx.Select(&current, query, id); err != nil {
+		return fmt.Errorf("failed to get %s posts: %w", o.parent, err)
+	}
+
+	order, ok := mergePostOrder(current, postIDs)
+	if !ok {
+		return model.ErrInvalidPostOrder
+	}
+
+	query = fmt.Sprintf(`UPDATE %s SET position = ? WHERE %s = ? AND post_id = ?`, o.table, o.column)
+	for i, postID := range order {
+		if _, err := tx.Exec(query, i+1, id, postID); err != nil {
+			return fmt.Errorf("failed to update position: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// lock 锁定系列或合集行
+func (o orderedPosts) lock(tx *sqlx.Tx, id int) error {
+	var locked int
+	query := fmt.Sprintf(`SELECT id FROM %s WHERE id = ? FOR UPDATE`, o.parent)
+	if err := tx.Get(&locked, query, id); err != nil {
+		return fmt.Errorf("failed to lock %s: %w", o.parent, err)
+	}
+
+	return nil
+}
+
+// mergePostOrder 计算新的顺序：requested在前，current中未列出的文章按原顺序在后。
+// requested包含不在列表中的文章或重复的文章时返回false
+func mergePostOrder(current, requested []int) ([]int, bool) {
+	remaining := make(map[int]bool, len(current))
+	for _, id := range current {
+		remaining[id] = true
+	}
+
+	order := make([]int, 0, len(current))
+	for _, id := range requested {
+		if !remaining[id] {
+			return nil, false
+		}
+		delete(remaining, id)
+		order = append(order, id)
+	}
+
+	for _, id := range current {
+		if remaining[id] {
+			order = append(order, id)
+		}
+	}
+
+	return order, true
+}
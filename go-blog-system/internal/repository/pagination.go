@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// keyKind 排序列的类型，决定游标中的值如何编码和解析
+type keyKind int
+
+const (
+	keyTime keyKind = iota
+	keyString
+	keyInt
+)
+
+// keyset 键集分页的排序方式：按column和idColumn同向排序。
+// NULL按MySQL的规则视为最小值：正序时排在最前，倒序时排在最后
+type keyset[T any] struct {
+	name     string // 游标中记录的排序名
+	column   string
+	idColumn string
+	kind     keyKind
+	desc     bool
+	nullable bool
+	// value 返回记录的排序值（time.Time、string或int），nil表示NULL
+	value func(item *T) interface{}
+	// id 返回记录的ID
+	id func(item *T) int
+}
+
+// list 执行分页查询。query为包含WHERE条件的SELECT语句（不含ORDER BY和LIMIT），
+// 多取一条记录用于判断是否还有下一页，向前翻页时按相反顺序查询后再恢复正序
+func (k keyset[T]) list(db *sqlx.DB, query string, args []interface{}, p *model.PageQuery) (*model.Page[T], error) {
+	var cursor *model.Cursor
+	if !p.UseOffset() {
+		var err error
+		if cursor, err = model.DecodeCursor(p.Cursor); err != nil {
+			return nil, err
+		}
+		if cursor != nil && cursor.Sort != k.name {
+			return nil, model.ErrInvalidCursor
+		}
+	}
+
+	desc := k.desc
+	if cursor != nil {
+		if cursor.Backward {
+			desc = !desc
+		}
+
+		condition, conditionArgs, err := k.after(cursor, desc)
+		if err != nil {
+			return nil, err
+		}
+		query += " AND " + condition
+		args = append(args, conditionArgs...)
+	}
+
+	direction := "ASC"
+	if desc {
+		direction = "DESC"
+	}
+	query += fmt.Sprintf(" ORDER BY %s %s, %s %s LIMIT ?", k.column, direction, k.idColumn, direction)
+	args = append(args, p.PerPage+1)
+
+	if p.UseOffset() {
+		query += " OFFSET ?"
+		args = append(args, (p.Page-1)*p.PerPage)
+	}
+
+	items := []T{}
+	if err := db.Select(&items, query, args...); err != nil {
+		return nil, err
+	}
+
+	more := len(items) > p.PerPage
+	if more {
+		items = items[:p.PerPage]
+	}
+
+	hasNext, hasPrev := more, cursor != nil
+	if cursor != nil && cursor.Backward {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+		hasNext, hasPrev = true, more
+	}
+
+	page := &model.Page[T]{Items: items}
+	if len(items) > 0 {
+		if hasNext {
+			page.NextCursor = k.cursor(&items[len(items)-1], false).Encode()
+		}
+		if hasPrev {
+			page.PrevCursor = k.cursor(&items[0], true).Encode()
+		}
+	}
+
+	return page, nil
+}
+
+// after 生成排在游标之后的条件，desc为实际查询的排序方向
+func (k keyset[T]) after(cursor *model.Cursor, desc bool) (string, []interface{}, error) {
+	cmp := ">"
+	if desc {
+		cmp = "<"
+	}
+
+	if cursor.Value == nil {
+		if !k.nullable {
+			return "", nil, model.ErrInvalidCursor
+		}
+		// 所有非NULL值都大于NULL
+		if desc {
+			return fmt.Sprintf("(%s IS NULL AND %s < ?)", k.column, k.idColumn), []interface{}{cursor.ID}, nil
+		}
+		return fmt.Sprintf("(%s IS NOT NULL OR %s > ?)", k.column, k.idColumn), []interface{}{cursor.ID}, nil
+	}
+
+	value, err := k.parse(*cursor.Value)
+	if err != nil {
+		return "", nil, model.ErrInvalidCursor
+	}
+
+	condition := fmt.Sprintf("(%s %s ? OR (%s = ? AND %s %s ?)", k.column, cmp, k.column, k.idColumn, cmp)
+	if desc && k.nullable {
+		condition += fmt.Sprintf(" OR %s IS NULL", k.column)
+	}
+	condition += ")"
+
+	return condition, []interface{}{value, value, cursor.ID}, nil
+}
+
+// cursor 生成指向item的游标
+func (k keyset[T]) cursor(item *T, backward bool) *model.Cursor {
+	cursor := &model.Cursor{Sort: k.name, ID: k.id(item), Backward: backward}
+
+	var s string
+	switch v := k.value(item).(type) {
+	case nil:
+		return cursor
+	case time.Time:
+		s = v.UTC().Format(time.RFC3339Nano)
+	case string:
+		s = v
+	case int:
+		s = strconv.Itoa(v)
+	default:
+		s = fmt.Sprint(v)
+	}
+	cursor.Value = &s
+
+	return cursor
+}
+
+// parse 解析游标中的排序值
+func (k keyset[T]) parse(s string) (interface{}, error) {
+	switch k.kind {
+	case keyTime:
+		return time.Parse(time.RFC3339Nano, s)
+	case keyInt:
+		return strconv.Atoi(s)
+	default:
+		return s, nil
+	}
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// PasswordResetRepository 密码重置令牌仓库接口
+type PasswordResetRepository interface {
+	Create(reset *model.PasswordReset) error
+	GetByTokenHash(hash string) (*model.PasswordReset, error)
+	MarkUsed(id int) (bool, error)
+	InvalidateByUserID(userID int) error
+	DeleteExpired(before time.Time) error
+}
+
+// passwordResetRepository 密码重置令牌仓库实现
+type passwordResetRepository struct {
+	db *sqlx.DB
+}
+
+// NewPasswordResetRepository 创建密码重置令牌仓库
+func NewPasswordResetRepository(db *sqlx.DB) PasswordResetRepository {
+	return &passwordResetRepository{db: db}
+}
+
+// Create 创建密码重置令牌
+func (r *passwordResetRepository) Create(reset *model.PasswordReset) error {
+	query := `INSERT INTO password_resets (user_id, token_hash, expires_at) VALUES (?, ?, ?)`
+
+	result, err := r.db.Exec(query, reset.UserID, reset.TokenHash, reset.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to create password reset: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	reset.ID = int(id)
+	return nil
+}
+
+// GetByTokenHash 根据哈希获取密码重置令牌
+func (r *passwordResetRepository) GetByTokenHash(hash string) (*model.PasswordReset, error) {
+	var reset model.PasswordReset
+	query := `SELECT * FROM password_resets WHERE token_hash = ?`
+
+	err := r.db.Get(&reset, query, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get password reset by hash: %w", err)
+	}
+
+	return &reset, nil
+}
+
+// MarkUsed 标记令牌已使用，返回false表示令牌已被使用过
+func (r *passwordResetRepository) MarkUsed(id int) (bool, error) {
+	query := `UPDATE password_resets SET used_at = ? WHERE id = ? AND used_at IS NULL`
+
+	result, err := r.db.Exec(query, time.Now(), id)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark password reset used: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected == 1, nil
+}
+
+// InvalidateByUserID 使用户所有未使用的重置令牌失效
+func (r *passwordResetRepository) InvalidateByUserID(userID int) error {
+	query := `UPDATE password_resets SET used_at = ? WHERE user_id = ? AND used_at IS NULL`
+
+	_, err := r.db.Exec(query, time.Now(), userID)
+	if err != nil {
+		return fmt.Errorf("failed to invalidate password resets: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteExpired 清理已过期或已使用的重置令牌
+func (r *passwordResetRepository) DeleteExpired(before time.Time) error {
+	query := `DELETE FROM password_resets WHERE expires_at < ? OR used_at IS NOT NULL`
+
+	_, err := r.db.Exec(query, before)
+	if err != nil {
+		return fmt.Errorf("failed to delete expired password resets: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// PersonalAccessTokenRepository 个人访问令牌仓库接口
+type PersonalAccessTokenRepository interface {
+	Create(token *model.PersonalAccessToken) error
+	GetByHash(hash string) (*model.PersonalAccessToken, error)
+	ListByUserID(userID int) ([]model.PersonalAccessToken, error)
+	Delete(userID, id int) (bool, error)
+	TouchLastUsed(id int, ip string, at time.Time, interval time.Duration) error
+}
+
+// personalAccessTokenRepository 个人访问令牌仓库实现
+type personalAccessTokenRepository struct {
+	db *sqlx.DB
+}
+
+// NewPersonalAccessTokenRepository 创建个人访问令牌仓库
+func NewPersonalAccessTokenRepository(db *sqlx.DB) PersonalAccessTokenRepository {
+	return &personalAccessTokenRepository{db: db}
+}
+
+// Create 创建个人访问令牌
+func (r *personalAccessTokenRepository) Create(token *model.PersonalAccessToken) error {
+	query := `INSERT INTO personal_access_tokens (user_id, name, token_prefix, token_hash, scopes, expires_at)
+			  VALUES (?, ?, ?, ?, ?, ?)`
+
+	result, err := r.db.Exec(
+		query,
+		token.UserID,
+		token.Name,
+		token.TokenPrefix,
+		token.TokenHash,
+		token.Scopes,
+		token.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create personal access token: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	token.ID = int(id)
+	return nil
+}
+
+// GetByHash 根据哈希获取个人访问令牌
+func (r *personalAccessTokenRepository) GetByHash(hash string) (*model.PersonalAccessToken, error) {
+	var token model.PersonalAccessToken
+	query := `SELECT * FROM personal_access_tokens WHERE token_hash = ?`
+
+	err := r.db.Get(&token, query, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get personal access token by hash: %w", err)
+	}
+
+	return &token, nil
+}
+
+// ListByUserID 获取用户的所有个人访问令牌
+func (r *personalAccessTokenRepository) ListByUserID(userID int) ([]model.PersonalAccessToken, error) {
+	query := `SELECT * FROM personal_access_tokens WHERE user_id = ? ORDER BY created_at DESC`
+
+	var tokens []model.PersonalAccessToken
+	err := r.db.Select(&tokens, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list personal access tokens: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// Delete 删除用户的个人访问令牌，返回false表示令牌不存在
+func (r *personalAccessTokenRepository) Delete(userID, id int) (bool, error) {
+	query := `DELETE FROM personal_access_tokens WHERE id = ? AND user_id = ?`
+
+	result, err := r.db.Exec(query, id, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete personal access token: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected == 1, nil
+}
+
+// TouchLastUsed 记录最近使用时间和IP，距上次记录不足interval时跳过以减少写入
+func (r *personalAccessTokenRepository) TouchLastUsed(id int, ip string, at time.Time, interval time.Duration) error {
+	query := `UPDATE personal_access_tokens SET last_used_at = ?, last_used_ip = ?
+			  WHERE id = ? AND (last_used_at IS NULL OR last_used_at < ? OR last_used_ip <> ?)`
+
+	_, err := r.db.Exec(query, at, ip, id, at.Add(-interval), ip)
+	if err != nil {
+		return fmt.Errorf("failed to update personal access token usage: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/duanyu/go-blog-system/pkg/filter"
+)
+
+// filterKind 过滤字段的类型，决定允许的运算符和值的解析方式
+type filterKind int
+
+const (
+	filterInt    filterKind = iota // 整数，支持全部比较运算符
+	filterTime                     // 时间（2006-01-02或RFC3339），支持全部比较运算符
+	filterText                     // 文本，:表示包含
+	filterStatus                   // 文章状态
+	filterTag                      // 标签名
+	filterAuthor                   // 作者用户名
+)
+
+// postFilterFields 文章过滤表达式可用的字段，不在此列表中的字段一律拒绝
+var postFilterFields = map[string]struct {
+	column string
+	kind   filterKind
+}{
+	"view_count":    {"view_count", filterInt},
+	"comment_count": {"comment_count", filterInt},
+	"user_id":       {"user_id", filterInt},
+	"published_at":  {"published_at", filterTime},
+	"created_at":    {"created_at", filterTime},
+	"updated_at":    {"updated_at", filterTime},
+	"title":         {"title", filterText},
+	"status":        {"status", filterStatus},
+	"tag":           {"", filterTag},
+	"author":        {"", filterAuthor},
+}
+
+// compilePostFilter 将过滤表达式编译为参数化的SQL条件
+func compilePostFilter(s string) (string, []interface{}, error) {
+	expr, err := filter.Parse(s)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %v", model.ErrInvalidFilter, err)
+	}
+
+	var args []interface{}
+	sql, err := compileFilterExpr(expr, &args)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %v", model.ErrInvalidFilter, err)
+	}
+
+	return sql, args, nil
+}
+
+// compileFilterExpr 递归编译语法树，参数按占位符顺序追加到args
+func compileFilterExpr(expr filter.Expr, args *[]interface{}) (string, error) {
+	switch e := expr.(type) {
+	case *filter.And:
+		return compileFilterBinary("AND", e.Left, e.Right, args)
+	case *filter.Or:
+		return compileFilterBinary("OR", e.Left, e.Right, args)
+	case *filter.Not:
+		x, err := compileFilterExpr(e.X, args)
+		if err != nil {
+			return "", err
+		}
+		return "NOT (" + x + ")", nil
+	case *filter.Condition:
+		return compileFilterCondition(e, args)
+	default:
+		return "", fmt.Errorf("unsupported expression %T", expr)
+	}
+}
+
+// compileFilterBinary 编译AND和OR
+func compileFilterBinary(op string, left, right filter.Expr, args *[]interface{}) (string, error) {
+	l, err := compileFilterExpr(left, args)
+	if err != nil {
+		return "", err
+	}
+
+	r, err := compileFilterExpr(right, args)
+	if err != nil {
+		return "", err
+	}
+
+	return "(" + l + " " + op + " " + r + ")", nil
+}
+
+// compileFilterCondition 编译单个比较条件
+func compileFilterCondition(c *filter.Condition, args *[]interface{}) (string, error) {
+	field, ok := postFilterFields[c.Field]
+	if !ok {
+		return "", fmt.Errorf("unknown field %q at position %d", c.Field, c.Pos)
+	}
+
+	op := c.Op
+	ordered := op == filter.OpGt || op == filter.OpGe || op == filter.OpLt || op == filter.OpLe
+	if ordered && field.kind != filterInt && field.kind != filterTime {
+		return "", fmt.Errorf("operator %s is not supported for field %q", op, c.Field)
+	}
+
+	// 除文本字段外，field:value等同于field=value
+	negate := op == filter.OpNe
+	if op == filter.OpContains && field.kind != filterText {
+		op = filter.OpEq
+	}
+
+	switch field.kind {
+	case filterInt:
+		n, err := strconv.Atoi(c.Value)
+		if err != nil {
+			return "", fmt.Errorf("field %q expects an integer, got %q", c.Field, c.Value)
+		}
+		*args = append(*args, n)
+		return fmt.Sprintf("%s %s ?", field.column, op), nil
+
+	case filterTime:
+		t, err := parseFilterTime(c.Value)
+		if err != nil {
+			return "", fmt.Errorf("field %q expects a date or RFC3339 time, got %q", c.Field, c.Value)
+		}
+		*args = append(*args, t)
+		return fmt.Sprintf("%s %s ?", field.column, op), nil
+
+	case filterText:
+		if op == filter.OpContains {
+			*args = append(*args, "%"+escapeLike(c.Value)+"%")
+			return fmt.Sprintf("%s LIKE ?", field.column), nil
+		}
+		*args = append(*args, c.Value)
+		return fmt.Sprintf("%s %s ?", field.column, op), nil
+
+	case filterStatus:
+		if !validPostStatus(model.PostStatus(c.Value)) {
+			return "", fmt.Errorf("unknown status %q", c.Value)
+		}
+		*args = append(*args, c.Value)
+		return fmt.Sprintf("%s %s ?", field.column, op), nil
+
+	case filterTag:
+		*args = append(*args, c.Value)
+		return notIf(negate, "id IN (SELECT pt.post_id FROM post_tags pt JOIN tags t ON t.id = pt.tag_id WHERE t.name = ?)"), nil
+
+	case filterAuthor:
+		*args = append(*args, c.Value)
+		return notIf(negate, "user_id IN (SELECT id FROM users WHERE username = ?)"), nil
+	}
+
+	return "", fmt.Errorf("unsupported field %q", c.Field)
+}
+
+// notIf negate为true时对条件取反
+func notIf(negate bool, condition string) string {
+	if negate {
+		return "NOT " + condition
+	}
+
+	return condition
+}
+
+// parseFilterTime 解析日期（按UTC零点）或RFC3339时间
+func parseFilterTime(s string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return t.UTC(), nil
+}
+
+// escapeLike 转义LIKE模式中的通配符
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// validPostStatus 是否为合法的文章状态
+func validPostStatus(status model.PostStatus) bool {
+	switch status {
+	case model.PostStatusDraft, model.PostStatusInReview, model.PostStatusApproved,
+		model.PostStatusScheduled, model.PostStatusPublished, model.PostStatusArchived:
+		return true
+	}
+
+	return false
+}
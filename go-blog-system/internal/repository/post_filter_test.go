@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+)
+
+func TestCompilePostFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name:     "integer comparison",
+			input:    "view_count>=100",
+			wantSQL:  "view_count >= ?",
+			wantArgs: []interface{}{100},
+		},
+		{
+			name:     "colon means equal for integers",
+			input:    "user_id:7",
+			wantSQL:  "user_id = ?",
+			wantArgs: []interface{}{7},
+		},
+		{
+			name:     "date",
+			input:    "published_at<2024-03-01",
+			wantSQL:  "published_at < ?",
+			wantArgs: []interface{}{time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:     "RFC3339 time is converted to UTC",
+			input:    "created_at>2024-03-01T08:00:00+08:00",
+			wantSQL:  "created_at > ?",
+			wantArgs: []interface{}{time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:     "text contains",
+			input:    `title:"go blog"`,
+			wantSQL:  "title LIKE ?",
+			wantArgs: []interface{}{"%go blog%"},
+		},
+		{
+			name:     "text equal",
+			input:    "title=Hello",
+			wantSQL:  "title = ?",
+			wantArgs: []interface{}{"Hello"},
+		},
+		{
+			name:     "LIKE wildcards are escaped",
+			input:    `title:"100%_off\\now"`,
+			wantSQL:  "title LIKE ?",
+			wantArgs: []interface{}{`%100\%\_off\\now%`},
+		},
+		{
+			name:     "status",
+			input:    "status!=draft",
+			wantSQL:  "status != ?",
+			wantArgs: []interface{}{"draft"},
+		},
+		{
+			name:     "tag",
+			input:    "tag:go",
+			wantSQL:  "id IN (SELECT pt.post_id FROM post_tags pt JOIN tags t ON t.id = pt.tag_id WHERE t.name = ?)",
+			wantArgs: []interface{}{"go"},
+		},
+		{
+			name:     "negated author",
+			input:    "author!=bob",
+			wantSQL:  "NOT user_id IN (SELECT id FROM users WHERE username = ?)",
+			wantArgs: []interface{}{"bob"},
+		},
+		{
+			name:     "precedence and argument order",
+			input:    "view_count>1 OR comment_count>2 title:x",
+			wantSQL:  "(view_count > ? OR (comment_count > ? AND title LIKE ?))",
+			wantArgs: []interface{}{1, 2, "%x%"},
+		},
+		{
+			name:     "parentheses",
+			input:    "(view_count>1 OR comment_count>2) title:x",
+			wantSQL:  "((view_count > ? OR comment_count > ?) AND title LIKE ?)",
+			wantArgs: []interface{}{1, 2, "%x%"},
+		},
+		{
+			name:     "not",
+			input:    "NOT (tag:go OR tag:rust)",
+			wantSQL:  "NOT ((id IN (SELECT pt.post_id FROM post_tags pt JOIN tags t ON t.id = pt.tag_id WHERE t.name = ?) OR id IN (SELECT pt.post_id FROM post_tags pt JOIN tags t ON t.id = pt.tag_id WHERE t.name = ?)))",
+			wantArgs: []interface{}{"go", "rust"},
+		},
+		{
+			name:     "values are never interpolated",
+			input:    `title="x' OR 1=1 --"`,
+			wantSQL:  "title = ?",
+			wantArgs: []interface{}{"x' OR 1=1 --"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := compilePostFilter(tt.input)
+			if err != nil {
+				t.Fatalf("compilePostFilter(%q) error: %v", tt.input, err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("sql = %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %#v, want %#v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestCompilePostFilterErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantMsg string
+	}{
+		{name: "syntax error", input: "view_count>", wantMsg: "expected value"},
+		{name: "too deep", input: strings.Repeat("NOT ", 10) + "view_count>1", wantMsg: "nested more than 10 levels"},
+		{name: "unknown field", input: "password:x", wantMsg: `unknown field "password" at position 0`},
+		{name: "unknown field inside not", input: "NOT secret=1", wantMsg: `unknown field "secret" at position 4`},
+		{name: "ordered operator on text", input: "title>a", wantMsg: `operator > is not supported for field "title"`},
+		{name: "ordered operator on status", input: "status<=draft", wantMsg: `operator <= is not supported for field "status"`},
+		{name: "ordered operator on tag", input: "tag>go", wantMsg: `operator > is not supported for field "tag"`},
+		{name: "integer expected", input: "view_count>many", wantMsg: `field "view_count" expects an integer`},
+		{name: "time expected", input: "published_at>yesterday", wantMsg: `field "published_at" expects a date or RFC3339 time`},
+		{name: "unknown status", input: "status=deleted", wantMsg: `unknown status "deleted"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := compilePostFilter(tt.input)
+			if err == nil {
+				t.Fatalf("compilePostFilter(%q) succeeded, want error", tt.input)
+			}
+			if !errors.Is(err, model.ErrInvalidFilter) {
+				t.Errorf("compilePostFilter(%q) error %v is not ErrInvalidFilter", tt.input, err)
+			}
+			if !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("compilePostFilter(%q) error = %q, want it to contain %q", tt.input, err, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	tests := map[string]string{
+		"plain":  "plain",
+		"50%":    `50\%`,
+		"a_b":    `a\_b`,
+		`c:\dir`: `c:\\dir`,
+		`\%_`:    `\\\%\_`,
+		"中文_标题%": `中文\_标题\%`,
+	}
+
+	for input, want := range tests {
+		if got := escapeLike(input); got != want {
+			t.Errorf("escapeLike(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -3,23 +3,38 @@ package repository
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/duanyu/go-blog-system/pkg/search"
 	"github.com/jmoiron/sqlx"
 )
 
 // PostRepository 文章仓库接口
 type PostRepository interface {
-	Create(post *model.Post) error
+	Create(post *model.Post, edit *model.PostEdit) error
 	GetByID(id int) (*model.Post, error)
-	Update(post *model.Post) error
+	GetBySlug(slug string) (*model.Post, error)
+	GetByRedirectSlug(slug string) (*model.Post, error)
+	SlugTaken(slug string, excludePostID int) (bool, error)
+	ChangeSlug(postID int, oldSlug *string, newSlug string) error
+	ListWithoutSlug(limit int) ([]model.Post, error)
+	ListStaleRendered(version, limit int) ([]model.Post, error)
+	UpdateRendered(post *model.Post) error
+	PublishDue(now time.Time, limit int) ([]int, error)
+	UpdateStatus(id int, from, to model.PostStatus) (bool, error)
+	ListReviewQueue(limit, offset int) ([]model.Post, error)
+	Edit(id int, fn func(post *model.Post) (*model.PostEdit, error)) error
 	Delete(id int) error
-	List(query *model.PostQuery) ([]model.Post, error)
+	List(query *model.PostQuery) (*model.Page[model.Post], error)
 	Count(query *model.PostQuery) (int, error)
+	Search(query *model.PostQuery, titleBoost float64) ([]model.PostSearchHit, error)
 	IncrementViewCount(id int) error
 	AddTags(postID int, tagIDs []int) error
 	RemoveTags(postID int) error
 	GetPostTags(postID int) ([]model.Tag, error)
+	GetTagsByPostIDs(postIDs []int) (map[int][]model.Tag, error)
+	ListFeed(userID int, p *model.PageQuery) (*model.Page[model.Post], error)
 }
 
 // postRepository 文章仓库实现
@@ -32,12 +47,65 @@ func NewPostRepository(db *sqlx.DB) PostRepository {
 	return &postRepository{db: db}
 }
 
-// Create 创建文章
-func (r *postRepository) Create(post *model.Post) error {
-	query := `INSERT INTO posts (title, content, user_id, status) 
-			VALUES (?, ?, ?, ?)`
+// Create 在同一事务中创建文章及其第一个修订版本、状态变更记录和标签（edit中的Baseline和NewSlug不使用），
+// 修订版本和状态变更记录的PostID在插入文章后设置
+func (r *postRepository) Create(post *model.Post, edit *model.PostEdit) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := createPost(tx, post); err != nil {
+		return err
+	}
+
+	edit.Revision.PostID = post.ID
+	if err := createRevision(tx, edit.Revision); err != nil {
+		return err
+	}
+
+	if edit.Transition != nil {
+		edit.Transition.PostID = post.ID
+		if err := createTransition(tx, edit.Transition); err != nil {
+			return err
+		}
+	}
+
+	if err := addTags(tx, post.ID, edit.TagIDs); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
 
-	result, err := r.db.Exec(query, post.Title, post.Content, post.UserID, post.Status)
+// createPost 插入文章
+func createPost(db sqlx.Execer, post *model.Post) error {
+	query := `INSERT INTO posts (title, slug, content, summary, excerpt, word_count, reading_time, content_format, content_html, toc,
+			render_version, user_id, status, published_at) 
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	result, err := db.Exec(
+		query,
+		post.Title,
+		post.Slug,
+		post.Content,
+		post.Summary,
+		post.Excerpt,
+		post.WordCount,
+		post.ReadingTime,
+		post.ContentFormat,
+		post.ContentHTML,
+		post.TOC,
+		post.RenderVersion,
+		post.UserID,
+		post.Status,
+		post.PublishedAt,
+	)
 	if err != nil {
 		return fmt.Errorf("failed to create post: %w", err)
 	}
@@ -64,11 +132,190 @@ func (r *postRepository) GetByID(id int) (*model.Post, error) {
 	return &post, nil
 }
 
-// Update 更新文章
-func (r *postRepository) Update(post *model.Post) error {
-	query := `UPDATE posts SET title = ?, content = ?, status = ? WHERE id = ?`
+// GetBySlug 根据slug获取文章
+func (r *postRepository) GetBySlug(slug string) (*model.Post, error) {
+	var post model.Post
+	query := `SELECT * FROM posts WHERE slug = ?`
+
+	err := r.db.Get(&post, query, slug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get post by slug: %w", err)
+	}
+
+	return &post, nil
+}
+
+// GetByRedirectSlug 根据文章曾经使用过的slug获取文章
+func (r *postRepository) GetByRedirectSlug(slug string) (*model.Post, error) {
+	var post model.Post
+	query := `SELECT p.* FROM posts p
+			  JOIN post_slug_redirects r ON r.post_id = p.id
+			  WHERE r.slug = ?`
+
+	err := r.db.Get(&post, query, slug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get post by redirect slug: %w", err)
+	}
+
+	return &post, nil
+}
+
+// SlugTaken 检查slug是否已被其他文章使用（包括其他文章保留的旧slug）
+func (r *postRepository) SlugTaken(slug string, excludePostID int) (bool, error) {
+	var count int
+	query := `SELECT
+			  (SELECT COUNT(*) FROM posts WHERE slug = ? AND id <> ?) +
+			  (SELECT COUNT(*) FROM post_slug_redirects WHERE slug = ? AND post_id <> ?)`
+
+	err := r.db.Get(&count, query, slug, excludePostID, slug, excludePostID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check slug: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// ChangeSlug 修改文章slug，旧slug保留为重定向
+func (r *postRepository) ChangeSlug(postID int, oldSlug *string, newSlug string) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := changeSlug(tx, postID, oldSlug, newSlug); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// changeSlug 在事务中修改文章slug
+func changeSlug(tx *sqlx.Tx, postID int, oldSlug *string, newSlug string) error {
+	// 新slug可能是本文章以前用过的，不再需要重定向
+	if _, err := tx.Exec(`DELETE FROM post_slug_redirects WHERE slug = ? AND post_id = ?`, newSlug, postID); err != nil {
+		return fmt.Errorf("failed to delete slug redirect: %w", err)
+	}
+
+	if oldSlug != nil && *oldSlug != newSlug {
+		if _, err := tx.Exec(`INSERT INTO post_slug_redirects (slug, post_id) VALUES (?, ?)`, *oldSlug, postID); err != nil {
+			return fmt.Errorf("failed to create slug redirect: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(`UPDATE posts SET slug = ?, updated_at = updated_at WHERE id = ?`, newSlug, postID); err != nil {
+		return fmt.Errorf("failed to update post slug: %w", err)
+	}
+
+	return nil
+}
+
+// ListWithoutSlug 获取尚未生成slug的文章
+func (r *postRepository) ListWithoutSlug(limit int) ([]model.Post, error) {
+	query := `SELECT * FROM posts WHERE slug IS NULL ORDER BY id LIMIT ?`
+
+	var posts []model.Post
+	err := r.db.Select(&posts, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list posts without slug: %w", err)
+	}
+
+	return posts, nil
+}
+
+// Edit 锁定文章后调用fn修改文章，并在同一事务中写入文章、修订版本、状态变更记录、slug重定向和标签。
+// fn收到的是加锁后读取的文章，返回错误时不做任何修改
+func (r *postRepository) Edit(id int, fn func(post *model.Post) (*model.PostEdit, error)) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var post model.Post
+	if err := tx.Get(&post, `SELECT * FROM posts WHERE id = ? FOR UPDATE`, id); err != nil {
+		return fmt.Errorf("failed to get post by id: %w", err)
+	}
 
-	_, err := r.db.Exec(query, post.Title, post.Content, post.Status, post.ID)
+	edit, err := fn(&post)
+	if err != nil {
+		return err
+	}
+
+	// 迁移前创建的文章没有修订记录，先保存修改前的版本
+	if edit.Baseline != nil {
+		var count int
+		if err := tx.Get(&count, `SELECT COUNT(*) FROM post_revisions WHERE post_id = ?`, id); err != nil {
+			return fmt.Errorf("failed to count post revisions: %w", err)
+		}
+		if count == 0 {
+			if err := createRevision(tx, edit.Baseline); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := updatePost(tx, &post); err != nil {
+		return err
+	}
+
+	if err := createRevision(tx, edit.Revision); err != nil {
+		return err
+	}
+
+	if edit.Transition != nil {
+		if err := createTransition(tx, edit.Transition); err != nil {
+			return err
+		}
+	}
+
+	if edit.NewSlug != "" {
+		if err := changeSlug(tx, id, post.Slug, edit.NewSlug); err != nil {
+			return err
+		}
+	}
+
+	if edit.TagIDs != nil {
+		if _, err := tx.Exec(`DELETE FROM post_tags WHERE post_id = ?`, id); err != nil {
+			return fmt.Errorf("failed to remove tags from post: %w", err)
+		}
+		if err := addTags(tx, id, edit.TagIDs); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// updatePost 更新文章字段
+func updatePost(db sqlx.Execer, post *model.Post) error {
+	query := `UPDATE posts SET title = ?, content = ?, summary = ?, excerpt = ?, word_count = ?, reading_time = ?, content_format = ?,
+			  content_html = ?, toc = ?, render_version = ?, status = ?, published_at = ? WHERE id = ?`
+
+	_, err := db.Exec(
+		query,
+		post.Title,
+		post.Content,
+		post.Summary,
+		post.Excerpt,
+		post.WordCount,
+		post.ReadingTime,
+		post.ContentFormat,
+		post.ContentHTML,
+		post.TOC,
+		post.RenderVersion,
+		post.Status,
+		post.PublishedAt,
+		post.ID,
+	)
 	if err != nil {
 		return fmt.Errorf("failed to update post: %w", err)
 	}
@@ -76,6 +323,32 @@ func (r *postRepository) Update(post *model.Post) error {
 	return nil
 }
 
+// ListStaleRendered 获取尚未渲染或渲染规则版本低于version的文章
+func (r *postRepository) ListStaleRendered(version, limit int) ([]model.Post, error) {
+	query := `SELECT * FROM posts WHERE render_version < ? ORDER BY id LIMIT ?`
+
+	var posts []model.Post
+	err := r.db.Select(&posts, query, version, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stale rendered posts: %w", err)
+	}
+
+	return posts, nil
+}
+
+// UpdateRendered 更新文章的渲染缓存和摘要（不修改updated_at）
+func (r *postRepository) UpdateRendered(post *model.Post) error {
+	query := `UPDATE posts SET content_html = ?, toc = ?, excerpt = ?, word_count = ?, reading_time = ?, render_version = ?,
+			  updated_at = updated_at WHERE id = ?`
+
+	_, err := r.db.Exec(query, post.ContentHTML, post.TOC, post.Excerpt, post.WordCount, post.ReadingTime, post.RenderVersion, post.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update rendered content: %w", err)
+	}
+
+	return nil
+}
+
 // Delete 删除文章
 func (r *postRepository) Delete(id int) error {
 	query := `DELETE FROM posts WHERE id = ?`
@@ -88,34 +361,64 @@ func (r *postRepository) Delete(id int) error {
 	return nil
 }
 
-// List 获取文章列表
-func (r *postRepository) List(query *model.PostQuery) ([]model.Post, error) {
-	baseQuery := `SELECT * FROM posts WHERE 1=1`
-	whereClause, args := r.buildWhereClause(query)
+// List 获取文章列表，默认按发布时间倒序（未发布的文章排在最后并按创建顺序倒序）
+func (r *postRepository) List(query *model.PostQuery) (*model.Page[model.Post], error) {
+	whereClause, args, err := r.buildWhereClause(query)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := postSortKeyset(query.Sort, query.Order).list(r.db, `SELECT * FROM posts WHERE 1=1`+whereClause, args, &query.PageQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list posts: %w", err)
+	}
+
+	return page, nil
+}
+
+// Search 全文搜索文章，按相关度倒序排列，标题命中的得分乘以titleBoost
+func (r *postRepository) Search(query *model.PostQuery, titleBoost float64) ([]model.PostSearchHit, error) {
+	terms := search.Terms(query.Keyword)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	booleanQuery := search.BooleanQuery(terms)
+
+	baseQuery := `SELECT *,
+					 MATCH(title) AGAINST(? IN BOOLEAN MODE) * ? + MATCH(title, content) AGAINST(? IN BOOLEAN MODE) AS score
+				  FROM posts WHERE 1=1`
+	whereClause, whereArgs, err := r.buildWhereClause(query)
+	if err != nil {
+		return nil, err
+	}
 
 	offset := (query.Page - 1) * query.PerPage
-	finalQuery := baseQuery + whereClause + ` ORDER BY created_at DESC LIMIT ? OFFSET ?`
+	finalQuery := baseQuery + whereClause + ` ORDER BY score DESC, published_at DESC, id DESC LIMIT ? OFFSET ?`
 
+	args := append([]interface{}{booleanQuery, titleBoost, booleanQuery}, whereArgs...)
 	args = append(args, query.PerPage, offset)
 
-	var posts []model.Post
-	err := r.db.Select(&posts, finalQuery, args...)
+	var hits []model.PostSearchHit
+	err = r.db.Select(&hits, finalQuery, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list posts: %w", err)
+		return nil, fmt.Errorf("failed to search posts: %w", err)
 	}
 
-	return posts, nil
+	return hits, nil
 }
 
 // Count 获取文章总数
 func (r *postRepository) Count(query *model.PostQuery) (int, error) {
 	baseQuery := `SELECT COUNT(*) FROM posts WHERE 1=1`
-	whereClause, args := r.buildWhereClause(query)
+	whereClause, args, err := r.buildWhereClause(query)
+	if err != nil {
+		return 0, err
+	}
 
 	finalQuery := baseQuery + whereClause
 
 	var count int
-	err := r.db.Get(&count, finalQuery, args...)
+	err = r.db.Get(&count, finalQuery, args...)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count posts: %w", err)
 	}
@@ -137,6 +440,11 @@ func (r *postRepository) IncrementViewCount(id int) error {
 
 // AddTags 添加文章标签
 func (r *postRepository) AddTags(postID int, tagIDs []int) error {
+	return addTags(r.db, postID, tagIDs)
+}
+
+// addTags 批量插入文章标签
+func addTags(db sqlx.Execer, postID int, tagIDs []int) error {
 	if len(tagIDs) == 0 {
 		return nil
 	}
@@ -155,7 +463,7 @@ func (r *postRepository) AddTags(postID int, tagIDs []int) error {
 		strings.Join(values, ", "),
 	)
 
-	_, err := r.db.Exec(query, args...)
+	_, err := db.Exec(query, args...)
 	if err != nil {
 		return fmt.Errorf("failed to add tags to post: %w", err)
 	}
@@ -193,8 +501,8 @@ func (r *postRepository) GetPostTags(postID int) ([]model.Tag, error) {
 	return tags, nil
 }
 
-// buildWhereClause 构建WHERE子句
-func (r *postRepository) buildWhereClause(query *model.PostQuery) (string, []interface{}) {
+// buildWhereClause 构建WHERE子句，所有条件均使用占位符传参，过滤表达式中的未知字段返回错误
+func (r *postRepository) buildWhereClause(query *model.PostQuery) (string, []interface{}, error) {
 	var conditions []string
 	var args []interface{}
 
@@ -203,6 +511,13 @@ func (r *postRepository) buildWhereClause(query *model.PostQuery) (string, []int
 		args = append(args, *query.UserID)
 	}
 
+	if len(query.UserIDs) > 0 {
+		conditions = append(conditions, "user_id IN ("+placeholders(len(query.UserIDs))+")")
+		for _, id := range query.UserIDs {
+			args = append(args, id)
+		}
+	}
+
 	if query.Status != "" {
 		conditions = append(conditions, "status = ?")
 		args = append(args, query.Status)
@@ -213,15 +528,247 @@ func (r *postRepository) buildWhereClause(query *model.PostQuery) (string, []int
 		args = append(args, *query.TagID)
 	}
 
-	if query.Keyword != "" {
-		conditions = append(conditions, "(title LIKE ? OR content LIKE ?)")
-		keyword := "%" + query.Keyword + "%"
-		args = append(args, keyword, keyword)
+	if len(query.TagIDs) > 0 {
+		tagCondition := "id IN (SELECT post_id FROM post_tags WHERE tag_id IN (" + placeholders(len(query.TagIDs)) + ")"
+		tagIDs := make(map[int]bool, len(query.TagIDs))
+		for _, id := range query.TagIDs {
+			tagIDs[id] = true
+			args = append(args, id)
+		}
+		if query.TagMode == model.TagMatchAll {
+			// 重复的标签ID只计一次
+			tagCondition += " GROUP BY post_id HAVING COUNT(DISTINCT tag_id) = ?"
+			args = append(args, len(tagIDs))
+		}
+		conditions = append(conditions, tagCondition+")")
+	}
+
+	if query.PublishedFrom != nil {
+		conditions = append(conditions, "published_at >= ?")
+		args = append(args, query.PublishedFrom.UTC())
+	}
+
+	if query.PublishedTo != nil {
+		conditions = append(conditions, "published_at < ?")
+		args = append(args, query.PublishedTo.UTC())
+	}
+
+	if terms := search.Terms(query.Keyword); len(terms) > 0 {
+		conditions = append(conditions, "MATCH(title, content) AGAINST(? IN BOOLEAN MODE)")
+		args = append(args, search.BooleanQuery(terms))
+	}
+
+	if query.Filter != "" {
+		condition, filterArgs, err := compilePostFilter(query.Filter)
+		if err != nil {
+			return "", nil, err
+		}
+		conditions = append(conditions, condition)
+		args = append(args, filterArgs...)
 	}
 
 	if len(conditions) == 0 {
-		return "", args
+		return "", args, nil
+	}
+
+	return " AND " + strings.Join(conditions, " AND "), args, nil
+}
+
+// placeholders 生成n个以逗号分隔的占位符
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// GetTagsByPostIDs 批量获取多篇文章的标签
+func (r *postRepository) GetTagsByPostIDs(postIDs []int) (map[int][]model.Tag, error) {
+	result := make(map[int][]model.Tag, len(postIDs))
+	if len(postIDs) == 0 {
+		return result, nil
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT pt.post_id, t.id, t.name, t.created_at
+		FROM tags t
+		JOIN post_tags pt ON t.id = pt.tag_id
+		WHERE pt.post_id IN (?)
+	`, postIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build post tags query: %w", err)
+	}
+
+	var rows []struct {
+		PostID int `db:"post_id"`
+		model.Tag
+	}
+	if err := r.db.Select(&rows, r.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("failed to get tags by post ids: %w", err)
+	}
+
+	for _, row := range rows {
+		result[row.PostID] = append(result[row.PostID], row.Tag)
 	}
 
-	return " AND " + strings.Join(conditions, " AND "), args
+	return result, nil
+}
+
+// ListFeed 获取用户关注的作者和标签下的已发布文章，按发布时间倒序
+func (r *postRepository) ListFeed(userID int, p *model.PageQuery) (*model.Page[model.Post], error) {
+	query := `
+		SELECT p.* FROM posts p
+		WHERE p.status = 'published'
+		AND (
+			p.user_id IN (SELECT followee_id FROM user_follows WHERE follower_id = ?)
+			OR p.id IN (
+				SELECT pt.post_id FROM post_tags pt
+				JOIN tag_follows tf ON tf.tag_id = pt.tag_id
+				WHERE tf.user_id = ?
+			)
+		)`
+
+	page, err := postsByPublished("p.published_at", "p.id").list(r.db, query, []interface{}{userID, userID}, p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list feed: %w", err)
+	}
+
+	return page, nil
+}
+
+// postSortKeyset 文章列表排序方式对应的分页键，order为空时使用该排序方式的默认方向
+func postSortKeyset(sort model.PostSort, order string) keyset[model.Post] {
+	var k keyset[model.Post]
+	switch sort {
+	case model.PostSortViews:
+		k = keyset[model.Post]{column: "view_count", kind: keyInt, desc: true,
+			value: func(p *model.Post) interface{} { return p.ViewCount }}
+	case model.PostSortComments:
+		k = keyset[model.Post]{column: "comment_count", kind: keyInt, desc: true,
+			value: func(p *model.Post) interface{} { return p.CommentCount }}
+	case model.PostSortUpdated:
+		k = keyset[model.Post]{column: "updated_at", kind: keyTime, desc: true,
+			value: func(p *model.Post) interface{} { return p.UpdatedAt }}
+	case model.PostSortTitle:
+		k = keyset[model.Post]{column: "title", kind: keyString,
+			value: func(p *model.Post) interface{} { return p.Title }}
+	default:
+		sort = model.PostSortPublished
+		k = postsByPublished("published_at", "id")
+	}
+
+	switch order {
+	case "asc":
+		k.desc = false
+	case "desc":
+		k.desc = true
+	}
+
+	// 游标只能用于生成它的排序方式和方向
+	direction := "asc"
+	if k.desc {
+		direction = "desc"
+	}
+	k.name = string(sort) + ":" + direction
+	k.idColumn = "id"
+	k.id = func(p *model.Post) int { return p.ID }
+
+	return k
+}
+
+// postsByPublished 按发布时间倒序分页，column和idColumn为查询中的列名
+func postsByPublished(column, idColumn string) keyset[model.Post] {
+	return keyset[model.Post]{
+		name:     "published",
+		column:   column,
+		idColumn: idColumn,
+		kind:     keyTime,
+		desc:     true,
+		nullable: true,
+		value: func(p *model.Post) interface{} {
+			if p.PublishedAt == nil {
+				return nil
+			}
+			return *p.PublishedAt
+		},
+		id: func(p *model.Post) int { return p.ID },
+	}
+}
+
+// PublishDue 发布到期的定时文章并在同一事务中记录状态变更，返回发布的文章ID。
+// 使用 FOR UPDATE SKIP LOCKED 锁定待发布的行，多个实例同时运行时每篇文章只会被一个实例处理
+func (r *postRepository) PublishDue(now time.Time, limit int) ([]int, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var ids []int
+	query := `SELECT id FROM posts
+			  WHERE status = 'scheduled' AND published_at <= ?
+			  ORDER BY published_at LIMIT ?
+			  FOR UPDATE SKIP LOCKED`
+	if err := tx.Select(&ids, query, now, limit); err != nil {
+		return nil, fmt.Errorf("failed to select due posts: %w", err)
+	}
+
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	update, args, err := sqlx.In(`UPDATE posts SET status = 'published' WHERE id IN (?)`, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build publish query: %w", err)
+	}
+	if _, err := tx.Exec(tx.Rebind(update), args...); err != nil {
+		return nil, fmt.Errorf("failed to publish due posts: %w", err)
+	}
+
+	scheduled := model.PostStatusScheduled
+	for _, id := range ids {
+		transition := &model.PostTransition{PostID: id, FromStatus: &scheduled, ToStatus: model.PostStatusPublished}
+		if err := createTransition(tx, transition); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return ids, nil
+}
+
+// UpdateStatus 在文章仍处于from状态时将其改为to状态，返回false表示状态已被修改
+func (r *postRepository) UpdateStatus(id int, from, to model.PostStatus) (bool, error) {
+	query := `UPDATE posts SET status = ? WHERE id = ? AND status = ?`
+
+	result, err := r.db.Exec(query, to, id, from)
+	if err != nil {
+		return false, fmt.Errorf("failed to update post status: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected == 1, nil
+}
+
+// ListReviewQueue 获取等待审核的文章，最早提交的在前
+func (r *postRepository) ListReviewQueue(limit, offset int) ([]model.Post, error) {
+	query := `SELECT p.* FROM posts p
+			  WHERE p.status = 'in_review'
+			  ORDER BY (
+				  SELECT MAX(t.created_at) FROM post_status_transitions t
+				  WHERE t.post_id = p.id AND t.to_status = 'in_review'
+			  ), p.id
+			  LIMIT ? OFFSET ?`
+
+	var posts []model.Post
+	err := r.db.Select(&posts, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list review queue: %w", err)
+	}
+
+	return posts, nil
 }
\ No newline at end of file
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+)
+
+// TestPostCreateAtomic 文章、修订版本、状态记录和标签一起写入，任一步失败时都不留下文章
+func TestPostCreateAtomic(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewPostRepository(db)
+	userID := createTestUsers(t, db, 1)[0]
+
+	newPost := func(title string) (*model.Post, *model.PostEdit) {
+		slug := fmt.Sprintf("atomic-%d", time.Now().UnixNano())
+		post := &model.Post{Title: title, Slug: &slug, Content: "content", ContentFormat: model.ContentFormatMarkdown,
+			UserID: userID, Status: model.PostStatusDraft}
+		edit := &model.PostEdit{
+			Revision: &model.PostRevision{UserID: &userID, Title: title, Content: "content",
+				ContentFormat: model.ContentFormatMarkdown, Status: model.PostStatusDraft},
+			Transition: &model.PostTransition{ToStatus: model.PostStatusDraft, UserID: &userID},
+		}
+		return post, edit
+	}
+
+	post, edit := newPost("created")
+	if err := repo.Create(post, edit); err != nil {
+		t.Fatalf("Create() error: %v", err)
+	}
+
+	var revisions, transitions int
+	if err := db.Get(&revisions, `SELECT COUNT(*) FROM post_revisions WHERE post_id = ? AND revision = 1`, post.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Get(&transitions, `SELECT COUNT(*) FROM post_status_transitions WHERE post_id = ?`, post.ID); err != nil {
+		t.Fatal(err)
+	}
+	if revisions != 1 || transitions != 1 {
+		t.Errorf("got %d revisions and %d transitions, want 1 and 1", revisions, transitions)
+	}
+
+	// 不存在的标签违反外键约束，整个事务回滚
+	post, edit = newPost(fmt.Sprintf("rolled back %d", time.Now().UnixNano()))
+	edit.TagIDs = []int{-1}
+	if err := repo.Create(post, edit); err == nil {
+		t.Fatal("Create() with an unknown tag succeeded, want error")
+	}
+
+	var posts int
+	if err := db.Get(&posts, `SELECT COUNT(*) FROM posts WHERE title = ?`, post.Title); err != nil {
+		t.Fatal(err)
+	}
+	if posts != 0 {
+		t.Errorf("post was created although adding tags failed")
+	}
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// PostRevisionRepository 文章修订版本仓库接口
+type PostRevisionRepository interface {
+	Create(revision *model.PostRevision) error
+	Get(postID, revision int) (*model.PostRevision, error)
+	ListByPostID(postID int) ([]model.PostRevision, error)
+	Count(postID int) (int, error)
+	Prune(postID, keep int) error
+}
+
+// postRevisionRepository 文章修订版本仓库实现
+type postRevisionRepository struct {
+	db *sqlx.DB
+}
+
+// NewPostRevisionRepository 创建文章修订版本仓库
+func NewPostRevisionRepository(db *sqlx.DB) PostRevisionRepository {
+	return &postRevisionRepository{db: db}
+}
+
+// Create 创建修订版本，版本号在该文章已有版本号的基础上递增
+func (r *postRevisionRepository) Create(revision *model.PostRevision) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := createRevision(tx, revision); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// createRevision 在事务中插入修订版本。先锁定文章行，同一文章的并发写入排队计算版本号，
+// 避免两个事务读到相同的MAX(revision)
+func createRevision(tx *sqlx.Tx, revision *model.PostRevision) error {
+	var postID int
+	if err := tx.Get(&postID, `SELECT id FROM posts WHERE id = ? FOR UPDATE`, revision.PostID); err != nil {
+		return fmt.Errorf("failed to lock post: %w", err)
+	}
+
+	query := `INSERT INTO post_revisions (post_id, revision, user_id, title, content, content_format, status)
+			  SELECT ?, COALESCE(MAX(revision), 0) + 1, ?, ?, ?, ?, ? FROM post_revisions WHERE post_id = ?`
+
+	result, err := tx.Exec(
+		query,
+		revision.PostID,
+		revision.UserID,
+		revision.Title,
+		revision.Content,
+		revision.ContentFormat,
+		revision.Status,
+		revision.PostID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create post revision: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	revision.ID = int(id)
+	return nil
+}
+
+// Get 获取文章的指定修订版本
+func (r *postRevisionRepository) Get(postID, revision int) (*model.PostRevision, error) {
+	var rev model.PostRevision
+	query := `SELECT * FROM post_revisions WHERE post_id = ? AND revision = ?`
+
+	err := r.db.Get(&rev, query, postID, revision)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get post revision: %w", err)
+	}
+
+	return &rev, nil
+}
+
+// ListByPostID 获取文章的所有修订版本，最新的在前
+func (r *postRevisionRepository) ListByPostID(postID int) ([]model.PostRevision, error) {
+	query := `SELECT * FROM post_revisions WHERE post_id = ? ORDER BY revision DESC`
+
+	var revisions []model.PostRevision
+	err := r.db.Select(&revisions, query, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list post revisions: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// Count 获取文章的修订版本数
+func (r *postRevisionRepository) Count(postID int) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM post_revisions WHERE post_id = ?`
+
+	err := r.db.Get(&count, query, postID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count post revisions: %w", err)
+	}
+
+	return count, nil
+}
+
+// Prune 只保留文章最近的keep个修订版本
+func (r *postRevisionRepository) Prune(postID, keep int) error {
+	query := `DELETE FROM post_revisions
+			  WHERE post_id = ? AND revision <= (
+				  SELECT max_revision FROM (
+					  SELECT COALESCE(MAX(revision), 0) - ? AS max_revision FROM post_revisions WHERE post_id = ?
+				  ) AS latest
+			  )`
+
+	_, err := r.db.Exec(query, postID, keep, postID)
+	if err != nil {
+		return fmt.Errorf("failed to prune post revisions: %w", err)
+	}
+
+	return nil
+}
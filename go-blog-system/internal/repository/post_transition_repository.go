@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// PostTransitionRepository 文章状态变更记录仓库接口
+type PostTransitionRepository interface {
+	Create(transition *model.PostTransition) error
+	ListByPostID(postID int) ([]model.PostTransition, error)
+}
+
+// postTransitionRepository 文章状态变更记录仓库实现
+type postTransitionRepository struct {
+	db *sqlx.DB
+}
+
+// NewPostTransitionRepository 创建文章状态变更记录仓库
+func NewPostTransitionRepository(db *sqlx.DB) PostTransitionRepository {
+	return &postTransitionRepository{db: db}
+}
+
+// Create 创建状态变更记录
+func (r *postTransitionRepository) Create(transition *model.PostTransition) error {
+	return createTransition(r.db, transition)
+}
+
+// createTransition 插入状态变更记录
+func createTransition(db sqlx.Execer, transition *model.PostTransition) error {
+	query := `INSERT INTO post_status_transitions (post_id, from_status, to_status, user_id, comment)
+			  VALUES (?, ?, ?, ?, ?)`
+
+	result, err := db.Exec(
+		query,
+		transition.PostID,
+		transition.FromStatus,
+		transition.ToStatus,
+		transition.UserID,
+		transition.Comment,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create post transition: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	transition.ID = int(id)
+	return nil
+}
+
+// ListByPostID 获取文章的状态变更历史，按时间先后排列
+func (r *postTransitionRepository) ListByPostID(postID int) ([]model.PostTransition, error) {
+	query := `SELECT * FROM post_status_transitions WHERE post_id = ? ORDER BY created_at, id`
+
+	var transitions []model.PostTransition
+	err := r.db.Select(&transitions, query, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list post transitions: %w", err)
+	}
+
+	return transitions, nil
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// reactionTables 回应对象对应的回应表和计数所在的表
+var reactionTables = map[model.ReactionTarget]struct {
+	table  string // 回应记录表
+	column string // 回应记录表中指向对象的列
+	parent string // 保存回应数量的表
+}{
+	model.ReactionTargetPost:    {"post_reactions", "post_id", "posts"},
+	model.ReactionTargetComment: {"comment_reactions", "comment_id", "comments"},
+}
+
+// ReactionRepository 表情回应仓库接口
+type ReactionRepository interface {
+	Add(target model.ReactionTarget, targetID, userID int, reaction model.Reaction) error
+	Remove(target model.ReactionTarget, targetID, userID int, reaction model.Reaction) error
+	GetUserReactions(target model.ReactionTarget, userID int, targetIDs []int) (map[int][]model.Reaction, error)
+	ListByUserID(userID int) ([]model.UserReaction, error)
+}
+
+// reactionRepository 表情回应仓库实现
+type reactionRepository struct {
+	db *sqlx.DB
+}
+
+// NewReactionRepository 创建表情回应仓库
+func NewReactionRepository(db *sqlx.DB) ReactionRepository {
+	return &reactionRepository{db: db}
+}
+
+// Add 添加回应（重复添加不报错），只有新增记录时才增加计数
+func (r *reactionRepository) Add(target model.ReactionTarget, targetID, userID int, reaction model.Reaction) error {
+	t := reactionTables[target]
+	query := fmt.Sprintf(`INSERT IGNORE INTO %s (%s, user_id, reaction) VALUES (?, ?, ?)`, t.table, t.column)
+
+	return r.change(target, targetID, reaction, 1, query, targetID, userID, reaction)
+}
+
+// Remove 取消回应，只有确实删除了记录时才减少计数
+func (r *reactionRepository) Remove(target model.ReactionTarget, targetID, userID int, reaction model.Reaction) error {
+	t := reactionTables[target]
+	query := fmt.Sprintf(`DELETE FROM %s WHERE %s = ? AND user_id = ? AND reaction = ?`, t.table, t.column)
+
+	return r.change(target, targetID, reaction, -1, query, targetID, userID, reaction)
+}
+
+// change 在同一事务中修改回应记录和计数。记录的主键保证并发添加同一回应时只有一个事务插入成功。
+// 修改记录前先锁定对象所在行：插入记录时的外键检查会对该行加共享锁，之后更新计数需要排他锁，
+// 两个事务先后持有共享锁再互相等待会死锁，先加排他锁可以让同一对象上的修改依次执行
+func (r *reactionRepository) change(target model.ReactionTarget, targetID int, reaction model.Reaction, delta int, query string, args ...interface{}) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	parent := reactionTables[target].parent
+	var id int
+	if err := tx.Get(&id, fmt.Sprintf(`SELECT id FROM %s WHERE id = ? FOR UPDATE`, parent), targetID); err != nil {
+		return fmt.Errorf("failed to lock %s: %w", target, err)
+	}
+
+	result, err := tx.Exec(query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update %s reaction: %w", target, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return nil
+	}
+
+	path := "$." + string(reaction)
+	counter := fmt.Sprintf(`UPDATE %s SET reactions = JSON_SET(COALESCE(reactions, JSON_OBJECT()), ?,
+			   GREATEST(COALESCE(CAST(JSON_EXTRACT(reactions, ?) AS SIGNED), 0) + ?, 0)),
+			   updated_at = updated_at WHERE id = ?`, parent)
+
+	if _, err := tx.Exec(counter, path, path, delta, targetID); err != nil {
+		return fmt.Errorf("failed to update reaction count: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetUserReactions 批量获取用户对多篇文章或多条评论的回应
+func (r *reactionRepository) GetUserReactions(target model.ReactionTarget, userID int, targetIDs []int) (map[int][]model.Reaction, error) {
+	result := make(map[int][]model.Reaction, len(targetIDs))
+	if len(targetIDs) == 0 {
+		return result, nil
+	}
+
+	t := reactionTables[target]
+	query, args, err := sqlx.In(fmt.Sprintf(`
+		SELECT %s AS target_id, reaction FROM %s
+		WHERE user_id = ? AND %s IN (?)
+		ORDER BY created_at, reaction
+	`, t.column, t.table, t.column), userID, targetIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build %s reactions query: %w", target, err)
+	}
+
+	var rows []struct {
+		TargetID int            `db:"target_id"`
+		Reaction model.Reaction `db:"reaction"`
+	}
+	if err := r.db.Select(&rows, r.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("failed to get user %s reactions: %w", target, err)
+	}
+
+	for _, row := range rows {
+		result[row.TargetID] = append(result[row.TargetID], row.Reaction)
+	}
+
+	return result, nil
+}
+
+// ListByUserID 获取用户对文章和评论添加的全部回应，最近的在前
+func (r *reactionRepository) ListByUserID(userID int) ([]model.UserReaction, error) {
+	var reactions []model.UserReaction
+	query := `SELECT 'post' AS target, post_id AS target_id, reaction, created_at FROM post_reactions WHERE user_id = ?
+			  UNION ALL
+			  SELECT 'comment' AS target, comment_id AS target_id, reaction, created_at FROM comment_reactions WHERE user_id = ?
+			  ORDER BY created_at DESC`
+
+	err := r.db.Select(&reactions, query, userID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user reactions: %w", err)
+	}
+
+	return reactions, nil
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+)
+
+// TestReactionConcurrentToggle 多个用户同时反复添加和取消同一篇文章的回应，
+// 不应出现死锁等错误，最终计数与回应记录数一致
+func TestReactionConcurrentToggle(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewReactionRepository(db)
+
+	const users, rounds = 16, 20
+	userIDs := createTestUsers(t, db, users)
+	postID := createTestPost(t, db, userIDs[0])
+
+	var wg sync.WaitGroup
+	errs := make(chan error, users*rounds*2)
+	for i, userID := range userIDs {
+		wg.Add(1)
+		go func(i, userID int) {
+			defer wg.Done()
+			for round := 0; round < rounds; round++ {
+				errs <- repo.Add(model.ReactionTargetPost, postID, userID, model.ReactionLike)
+				// 一半用户最后保留回应
+				if round < rounds-1 || i%2 == 0 {
+					errs <- repo.Remove(model.ReactionTargetPost, postID, userID, model.ReactionLike)
+				}
+			}
+		}(i, userID)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("concurrent reaction failed: %v", err)
+		}
+	}
+
+	var rows, count int
+	if err := db.Get(&rows, `SELECT COUNT(*) FROM post_reactions WHERE post_id = ? AND reaction = 'like'`, postID); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Get(&count, `SELECT COALESCE(CAST(JSON_EXTRACT(reactions, '$.like') AS SIGNED), 0) FROM posts WHERE id = ?`, postID); err != nil {
+		t.Fatal(err)
+	}
+
+	if rows != users/2 {
+		t.Errorf("got %d reaction rows, want %d", rows, users/2)
+	}
+	if count != rows {
+		t.Errorf("reaction count = %d, want %d", count, rows)
+	}
+}
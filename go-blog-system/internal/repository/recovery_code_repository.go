@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// RecoveryCodeRepository 恢复码仓库接口
+type RecoveryCodeRepository interface {
+	Replace(userID int, hashes []string) error
+	Consume(userID int, hash string) (bool, error)
+	CountUnused(userID int) (int, error)
+	DeleteByUserID(userID int) error
+}
+
+// recoveryCodeRepository 恢复码仓库实现
+type recoveryCodeRepository struct {
+	db *sqlx.DB
+}
+
+// NewRecoveryCodeRepository 创建恢复码仓库
+func NewRecoveryCodeRepository(db *sqlx.DB) RecoveryCodeRepository {
+	return &recoveryCodeRepository{db: db}
+}
+
+// Replace 用新的恢复码替换用户的所有恢复码
+func (r *recoveryCodeRepository) Replace(userID int, hashes []string) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM recovery_codes WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+
+	if len(hashes) > 0 {
+		values := make([]string, 0, len(hashes))
+		args := make([]interface{}, 0, len(hashes)*2)
+
+		for _, hash := range hashes {
+			values = append(values, "(?, ?)")
+			args = append(args, userID, hash)
+		}
+
+		query := fmt.Sprintf(
+			"INSERT INTO recovery_codes (user_id, code_hash) VALUES %s",
+			strings.Join(values, ", "),
+		)
+
+		if _, err := tx.Exec(query, args...); err != nil {
+			return fmt.Errorf("failed to create recovery codes: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Consume 使用恢复码，返回false表示恢复码不存在或已被使用
+func (r *recoveryCodeRepository) Consume(userID int, hash string) (bool, error) {
+	query := `UPDATE recovery_codes SET used_at = ? WHERE user_id = ? AND code_hash = ? AND used_at IS NULL`
+
+	result, err := r.db.Exec(query, time.Now(), userID, hash)
+	if err != nil {
+		return false, fmt.Errorf("failed to consume recovery code: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected == 1, nil
+}
+
+// CountUnused 统计未使用的恢复码数量
+func (r *recoveryCodeRepository) CountUnused(userID int) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM recovery_codes WHERE user_id = ? AND used_at IS NULL`
+
+	err := r.db.Get(&count, query, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count recovery codes: %w", err)
+	}
+
+	return count, nil
+}
+
+// DeleteByUserID 删除用户的所有恢复码
+func (r *recoveryCodeRepository) DeleteByUserID(userID int) error {
+	query := `DELETE FROM recovery_codes WHERE user_id = ?`
+
+	_, err := r.db.Exec(query, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+
+	return nil
+}
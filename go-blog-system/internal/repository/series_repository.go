@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// seriesColumns 查询系列时同时统计已发布的文章数
+const seriesColumns = `s.*, (SELECT COUNT(*) FROM series_posts sp JOIN posts p ON p.id = sp.post_id
+	WHERE sp.series_id = s.id AND p.status = 'published') AS post_count`
+
+// SeriesRepository 系列仓库接口
+type SeriesRepository interface {
+	Create(series *model.Series) error
+	GetByID(id int) (*model.Series, error)
+	GetByPostID(postID int) (*model.Series, error)
+	ListByUserID(userID int) ([]model.Series, error)
+	Update(series *model.Series) error
+	Delete(id int) error
+	GetPosts(id int, publishedOnly bool) ([]model.Post, error)
+	GetPostLinks(id int) ([]model.SeriesPostLink, error)
+	AddPost(id, postID int) (bool, error)
+	RemovePost(id, postID int) error
+	Reorder(id int, postIDs []int) error
+}
+
+// seriesRepository 系列仓库实现
+type seriesRepository struct {
+	db *sqlx.DB
+}
+
+// NewSeriesRepository 创建系列仓库
+func NewSeriesRepository(db *sqlx.DB) SeriesRepository {
+	return &seriesRepository{db: db}
+}
+
+// Create 创建系列
+func (r *seriesRepository) Create(series *model.Series) error {
+	query := `INSERT INTO series (user_id, title, description) VALUES (?, ?, ?)`
+
+	result, err := r.db.Exec(query, series.UserID, series.Title, series.Description)
+	if err != nil {
+		return fmt.Errorf("failed to create series: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	series.ID = int(id)
+	return nil
+}
+
+// GetByID 根据ID获取系列
+func (r *seriesRepository) GetByID(id int) (*model.Series, error) {
+	var series model.Series
+	query := `SELECT ` + seriesColumns + ` FROM series s WHERE s.id = ?`
+
+	err := r.db.Get(&series, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get series by id: %w", err)
+	}
+
+	return &series, nil
+}
+
+// GetByPostID 获取文章所属的系列，文章不属于任何系列时返回nil
+func (r *seriesRepository) GetByPostID(postID int) (*model.Series, error) {
+	var series model.Series
+	query := `SELECT ` + seriesColumns + ` FROM series s
+			  JOIN series_posts sp ON sp.series_id = s.id
+			  WHERE sp.post_id = ?`
+
+	err := r.db.Get(&series, query, postID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get series by post id: %w", err)
+	}
+
+	return &series, nil
+}
+
+// ListByUserID 获取用户的全部系列，按创建时间倒序
+func (r *seriesRepository) ListByUserID(userID int) ([]model.Series, error) {
+	var series []model.Series
+	query := `SELECT ` + seriesColumns + ` FROM series s WHERE s.user_id = ? ORDER BY s.created_at DESC, s.id DESC`
+
+	err := r.db.Select(&series, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list series: %w", err)
+	}
+
+	return series, nil
+}
+
+// Update 更新系列
+func (r *seriesRepository) Update(series *model.Series) error {
+	query := `UPDATE series SET title = ?, description = ? WHERE id = ?`
+
+	_, err := r.db.Exec(query, series.Title, series.Description, series.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update series: %w", err)
+	}
+
+	return nil
+}
+
+// Delete 删除系列，其中的文章不受影响
+func (r *seriesRepository) Delete(id int) error {
+	query := `DELETE FROM series WHERE id = ?`
+
+	_, err := r.db.Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete series: %w", err)
+	}
+
+	return nil
+}
+
+// GetPosts 按顺序获取系列中的文章
+func (r *seriesRepository) GetPosts(id int, publishedOnly bool) ([]model.Post, error) {
+	posts := []model.Post{}
+	query := `SELECT p.* FROM series_posts sp
+			  JOIN posts p ON p.id = sp.post_id
+			  WHERE sp.series_id = ?`
+	if publishedOnly {
+		query += ` AND p.status = 'published'`
+	}
+	query += ` ORDER BY sp.position, sp.post_id`
+
+	err := r.db.Select(&posts, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get series posts: %w", err)
+	}
+
+	return posts, nil
+}
+
+// GetPostLinks 按顺序获取系列中文章的标题和slug，用于前后篇导航
+func (r *seriesRepository) GetPostLinks(id int) ([]model.SeriesPostLink, error) {
+	var links []model.SeriesPostLink
+	query := `SELECT p.id, p.title, p.slug, p.status FROM series_posts sp
+			  JOIN posts p ON p.id = sp.post_id
+			  WHERE sp.series_id = ?
+			  ORDER BY sp.position, sp.post_id`
+
+	err := r.db.Select(&links, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get series post links: %w", err)
+	}
+
+	return links, nil
+}
+
+// AddPost 将文章添加到系列末尾，系列已满时返回false
+func (r *seriesRepository) AddPost(id, postID int) (bool, error) {
+	return seriesPosts.add(r.db, id, model.MaxSeriesPosts, func(tx *sqlx.Tx, position int) error {
+		query := `INSERT INTO series_posts (series_id, post_id, position) VALUES (?, ?, ?)`
+
+		if _, err := tx.Exec(query, id, postID, position); err != nil {
+			return fmt.Errorf("failed to add post to series: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// RemovePost 从系列中移除文章
+func (r *seriesRepository) RemovePost(id, postID int) error {
+	return seriesPosts.remove(r.db, id, postID)
+}
+
+// Reorder 调整系列中文章的顺序
+func (r *seriesRepository) Reorder(id int, postIDs []int) error {
+	return seriesPosts.reorder(r.db, id, postIDs)
+}
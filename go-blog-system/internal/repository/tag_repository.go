@@ -12,7 +12,8 @@ type TagRepository interface {
 	Create(tag *model.Tag) error
 	GetByID(id int) (*model.Tag, error)
 	GetByName(name string) (*model.Tag, error)
-	List() ([]model.Tag, error)
+	List(p *model.PageQuery) (*model.Page[model.Tag], error)
+	Count() (int, error)
 	Delete(id int) error
 }
 
@@ -70,17 +71,37 @@ func (r *tagRepository) GetByName(name string) (*model.Tag, error) {
 	return &tag, nil
 }
 
-// List 获取所有标签
-func (r *tagRepository) List() ([]model.Tag, error) {
-	var tags []model.Tag
-	query := `SELECT * FROM tags ORDER BY name ASC`
-
-	err := r.db.Select(&tags, query)
+// List 分页获取标签，按名称字母顺序排列
+func (r *tagRepository) List(p *model.PageQuery) (*model.Page[model.Tag], error) {
+	page, err := tagsByName.list(r.db, `SELECT * FROM tags WHERE 1=1`, nil, p)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list tags: %w", err)
 	}
 
-	return tags, nil
+	return page, nil
+}
+
+// Count 获取标签总数
+func (r *tagRepository) Count() (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM tags`
+
+	err := r.db.Get(&count, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count tags: %w", err)
+	}
+
+	return count, nil
+}
+
+// tagsByName 标签按名称字母顺序分页
+var tagsByName = keyset[model.Tag]{
+	name:     "name",
+	column:   "name",
+	idColumn: "id",
+	kind:     keyString,
+	value:    func(t *model.Tag) interface{} { return t.Name },
+	id:       func(t *model.Tag) int { return t.ID },
 }
 
 // Delete 删除标签
@@ -0,0 +1,274 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// TokenRepository 令牌仓库接口
+type TokenRepository interface {
+	CreateSession(session *model.Session) error
+	GetSessionByID(id string) (*model.Session, error)
+	ListActiveSessions(userID int, now time.Time) ([]model.Session, error)
+	ExtendSession(id string, expiresAt time.Time) error
+	TouchSession(id, ip string, at time.Time, interval time.Duration) error
+	RevokeSession(id string) error
+	RevokeUserSession(userID int, id string) (bool, error)
+	RevokeUserSessions(userID int) error
+	RevokeOtherUserSessions(userID int, keepID string) error
+	CreateRefreshToken(token *model.RefreshToken) error
+	GetRefreshTokenByHash(hash string) (*model.RefreshToken, error)
+	MarkRefreshTokenUsed(id int) (bool, error)
+	RevokeAccessToken(jti string, expiresAt time.Time) error
+	IsAccessTokenRevoked(jti string) (bool, error)
+	UseTokenID(jti string, expiresAt time.Time) (bool, error)
+	DeleteExpired(before time.Time) error
+}
+
+// tokenRepository 令牌仓库实现
+type tokenRepository struct {
+	db *sqlx.DB
+}
+
+// NewTokenRepository 创建令牌仓库
+func NewTokenRepository(db *sqlx.DB) TokenRepository {
+	return &tokenRepository{db: db}
+}
+
+// CreateSession 创建会话
+func (r *tokenRepository) CreateSession(session *model.Session) error {
+	query := `INSERT INTO sessions (id, user_id, mfa, user_agent, ip, last_seen_at, expires_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := r.db.Exec(
+		query,
+		session.ID,
+		session.UserID,
+		session.MFA,
+		session.UserAgent,
+		session.IP,
+		session.LastSeenAt,
+		session.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return nil
+}
+
+// GetSessionByID 根据ID获取会话
+func (r *tokenRepository) GetSessionByID(id string) (*model.Session, error) {
+	var session model.Session
+	query := `SELECT * FROM sessions WHERE id = ?`
+
+	err := r.db.Get(&session, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session by id: %w", err)
+	}
+
+	return &session, nil
+}
+
+// ListActiveSessions 获取用户未吊销且未过期的会话，最近活跃的在前
+func (r *tokenRepository) ListActiveSessions(userID int, now time.Time) ([]model.Session, error) {
+	query := `SELECT * FROM sessions
+			  WHERE user_id = ? AND revoked_at IS NULL AND expires_at > ?
+			  ORDER BY COALESCE(last_seen_at, created_at) DESC`
+
+	var sessions []model.Session
+	err := r.db.Select(&sessions, query, userID, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// ExtendSession 延长会话有效期
+func (r *tokenRepository) ExtendSession(id string, expiresAt time.Time) error {
+	query := `UPDATE sessions SET expires_at = ? WHERE id = ?`
+
+	_, err := r.db.Exec(query, expiresAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to extend session: %w", err)
+	}
+
+	return nil
+}
+
+// TouchSession 记录会话最近活跃时间和IP，距上次记录不足interval且IP未变时跳过以减少写入
+func (r *tokenRepository) TouchSession(id, ip string, at time.Time, interval time.Duration) error {
+	query := `UPDATE sessions SET last_seen_at = ?, ip = ?
+			  WHERE id = ? AND (last_seen_at IS NULL OR last_seen_at < ? OR ip <> ?)`
+
+	_, err := r.db.Exec(query, at, ip, id, at.Add(-interval), ip)
+	if err != nil {
+		return fmt.Errorf("failed to touch session: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeSession 吊销会话（整个刷新令牌族随之失效）
+func (r *tokenRepository) RevokeSession(id string) error {
+	query := `UPDATE sessions SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL`
+
+	_, err := r.db.Exec(query, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeUserSession 吊销用户的指定会话，返回false表示会话不存在或已吊销
+func (r *tokenRepository) RevokeUserSession(userID int, id string) (bool, error) {
+	query := `UPDATE sessions SET revoked_at = ? WHERE id = ? AND user_id = ? AND revoked_at IS NULL`
+
+	result, err := r.db.Exec(query, time.Now(), id, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected == 1, nil
+}
+
+// RevokeUserSessions 吊销用户的所有会话
+func (r *tokenRepository) RevokeUserSessions(userID int) error {
+	query := `UPDATE sessions SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL`
+
+	_, err := r.db.Exec(query, time.Now(), userID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke user sessions: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeOtherUserSessions 吊销用户除keepID之外的所有会话
+func (r *tokenRepository) RevokeOtherUserSessions(userID int, keepID string) error {
+	query := `UPDATE sessions SET revoked_at = ? WHERE user_id = ? AND id <> ? AND revoked_at IS NULL`
+
+	_, err := r.db.Exec(query, time.Now(), userID, keepID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke other sessions: %w", err)
+	}
+
+	return nil
+}
+
+// CreateRefreshToken 创建刷新令牌
+func (r *tokenRepository) CreateRefreshToken(token *model.RefreshToken) error {
+	query := `INSERT INTO refresh_tokens (session_id, token_hash, expires_at) VALUES (?, ?, ?)`
+
+	result, err := r.db.Exec(query, token.SessionID, token.TokenHash, token.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to create refresh token: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	token.ID = int(id)
+	return nil
+}
+
+// GetRefreshTokenByHash 根据哈希获取刷新令牌
+func (r *tokenRepository) GetRefreshTokenByHash(hash string) (*model.RefreshToken, error) {
+	var token model.RefreshToken
+	query := `SELECT * FROM refresh_tokens WHERE token_hash = ?`
+
+	err := r.db.Get(&token, query, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get refresh token by hash: %w", err)
+	}
+
+	return &token, nil
+}
+
+// MarkRefreshTokenUsed 标记刷新令牌已使用，返回false表示令牌已被使用过
+func (r *tokenRepository) MarkRefreshTokenUsed(id int) (bool, error) {
+	query := `UPDATE refresh_tokens SET used_at = ? WHERE id = ? AND used_at IS NULL`
+
+	result, err := r.db.Exec(query, time.Now(), id)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark refresh token used: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected == 1, nil
+}
+
+// RevokeAccessToken 将访问令牌加入黑名单直到其过期
+func (r *tokenRepository) RevokeAccessToken(jti string, expiresAt time.Time) error {
+	query := `INSERT IGNORE INTO revoked_tokens (jti, expires_at) VALUES (?, ?)`
+
+	_, err := r.db.Exec(query, jti, expiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to revoke access token: %w", err)
+	}
+
+	return nil
+}
+
+// IsAccessTokenRevoked 检查访问令牌是否已被吊销
+func (r *tokenRepository) IsAccessTokenRevoked(jti string) (bool, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM revoked_tokens WHERE jti = ?`
+
+	err := r.db.Get(&count, query, jti)
+	if err != nil {
+		return false, fmt.Errorf("failed to check revoked token: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// UseTokenID 将一次性令牌的jti加入黑名单，返回false表示该令牌已被使用过
+func (r *tokenRepository) UseTokenID(jti string, expiresAt time.Time) (bool, error) {
+	query := `INSERT IGNORE INTO revoked_tokens (jti, expires_at) VALUES (?, ?)`
+
+	result, err := r.db.Exec(query, jti, expiresAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to use token id: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected == 1, nil
+}
+
+// DeleteExpired 清理已过期的令牌记录
+func (r *tokenRepository) DeleteExpired(before time.Time) error {
+	if _, err := r.db.Exec(`DELETE FROM revoked_tokens WHERE expires_at < ?`, before); err != nil {
+		return fmt.Errorf("failed to delete expired revoked tokens: %w", err)
+	}
+
+	if _, err := r.db.Exec(`DELETE FROM refresh_tokens WHERE expires_at < ?`, before); err != nil {
+		return fmt.Errorf("failed to delete expired refresh tokens: %w", err)
+	}
+
+	if _, err := r.db.Exec(`DELETE FROM sessions WHERE expires_at < ?`, before); err != nil {
+		return fmt.Errorf("failed to delete expired sessions: %w", err)
+	}
+
+	return nil
+}
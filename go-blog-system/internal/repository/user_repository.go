@@ -2,6 +2,8 @@ package repository
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/duanyu/go-blog-system/internal/model"
 	"github.com/jmoiron/sqlx"
@@ -14,9 +16,23 @@ type UserRepository interface {
 	GetByUsername(username string) (*model.User, error)
 	GetByEmail(email string) (*model.User, error)
 	Update(user *model.User) error
-	Delete(id int) error
-	List(page, perPage int) ([]model.User, error)
+	UpdateRole(id int, role model.Role) error
+	MarkEmailVerified(id int, email string) (bool, error)
+	SetVerificationSentAt(id int, sentAt time.Time) error
+	SetTOTPSecret(id int, secret *string) error
+	EnableTOTP(id int, step int64) error
+	DisableTOTP(id int) error
+	UseTOTPStep(id int, step int64) (bool, error)
+	SoftDelete(id, deletedBy int, at time.Time) (bool, error)
+	Restore(id int, deletedAfter time.Time) (bool, error)
+	ListDeletedBefore(before time.Time, limit int) ([]model.User, error)
+	Anonymize(id int, username, email string, policy model.DeletedPostsPolicy, reassignTo int) error
+	List(p *model.PageQuery) (*model.Page[model.User], error)
 	Count() (int, error)
+	GetByIDs(ids []int) ([]model.User, error)
+	GetStats(id int) (*model.UserStats, error)
+	ListDirectory(query *model.UserDirectoryQuery) (*model.Page[model.UserDirectoryEntry], error)
+	CountDirectory(query *model.UserDirectoryQuery) (int, error)
 }
 
 // userRepository 用户仓库实现
@@ -31,10 +47,10 @@ func NewUserRepository(db *sqlx.DB) UserRepository {
 
 // Create 创建用户
 func (r *userRepository) Create(user *model.User) error {
-	query := `INSERT INTO users (username, email, password, avatar, bio) 
-			VALUES (?, ?, ?, ?, ?)`
+	query := `INSERT INTO users (username, email, password, role, avatar, bio) 
+			VALUES (?, ?, ?, ?, ?, ?)`
 
-	result, err := r.db.Exec(query, user.Username, user.Email, user.Password, user.Avatar, user.Bio)
+	result, err := r.db.Exec(query, user.Username, user.Email, user.Password, user.Role, user.Avatar, user.Bio)
 	if err != nil {
 		return fmt.Errorf("failed to create user: %w", err)
 	}
@@ -89,9 +105,9 @@ func (r *userRepository) GetByEmail(email string) (*model.User, error) {
 
 // Update 更新用户
 func (r *userRepository) Update(user *model.User) error {
-	query := `UPDATE users SET email = ?, password = ?, avatar = ?, bio = ? WHERE id = ?`
+	query := `UPDATE users SET email = ?, email_verified_at = ?, password = ?, avatar = ?, bio = ? WHERE id = ?`
 
-	_, err := r.db.Exec(query, user.Email, user.Password, user.Avatar, user.Bio, user.ID)
+	_, err := r.db.Exec(query, user.Email, user.EmailVerifiedAt, user.Password, user.Avatar, user.Bio, user.ID)
 	if err != nil {
 		return fmt.Errorf("failed to update user: %w", err)
 	}
@@ -99,32 +115,235 @@ func (r *userRepository) Update(user *model.User) error {
 	return nil
 }
 
-// Delete 删除用户
-func (r *userRepository) Delete(id int) error {
-	query := `DELETE FROM users WHERE id = ?`
+// UpdateRole 更新用户角色
+func (r *userRepository) UpdateRole(id int, role model.Role) error {
+	query := `UPDATE users SET role = ? WHERE id = ?`
+
+	_, err := r.db.Exec(query, role, id)
+	if err != nil {
+		return fmt.Errorf("failed to update user role: %w", err)
+	}
+
+	return nil
+}
+
+// MarkEmailVerified 标记邮箱已验证（仅当邮箱未被修改时生效）
+func (r *userRepository) MarkEmailVerified(id int, email string) (bool, error) {
+	query := `UPDATE users SET email_verified_at = ? WHERE id = ? AND email = ?`
+
+	result, err := r.db.Exec(query, time.Now(), id, email)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark email verified: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected == 1, nil
+}
+
+// SetVerificationSentAt 记录验证邮件发送时间
+func (r *userRepository) SetVerificationSentAt(id int, sentAt time.Time) error {
+	query := `UPDATE users SET verification_sent_at = ? WHERE id = ?`
+
+	_, err := r.db.Exec(query, sentAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to set verification sent time: %w", err)
+	}
+
+	return nil
+}
+
+// SetTOTPSecret 保存待确认的TOTP密钥
+func (r *userRepository) SetTOTPSecret(id int, secret *string) error {
+	query := `UPDATE users SET totp_secret = ? WHERE id = ?`
+
+	_, err := r.db.Exec(query, secret, id)
+	if err != nil {
+		return fmt.Errorf("failed to set totp secret: %w", err)
+	}
+
+	return nil
+}
+
+// EnableTOTP 开启两步验证，并记录确认时使用的时间步
+func (r *userRepository) EnableTOTP(id int, step int64) error {
+	query := `UPDATE users SET totp_enabled_at = ?, totp_last_step = ? WHERE id = ? AND totp_secret IS NOT NULL`
+
+	_, err := r.db.Exec(query, time.Now(), step, id)
+	if err != nil {
+		return fmt.Errorf("failed to enable totp: %w", err)
+	}
+
+	return nil
+}
+
+// DisableTOTP 关闭两步验证
+func (r *userRepository) DisableTOTP(id int) error {
+	query := `UPDATE users SET totp_secret = NULL, totp_enabled_at = NULL, totp_last_step = 0 WHERE id = ?`
 
 	_, err := r.db.Exec(query, id)
 	if err != nil {
-		return fmt.Errorf("failed to delete user: %w", err)
+		return fmt.Errorf("failed to disable totp: %w", err)
 	}
 
 	return nil
 }
 
-// List 获取用户列表
-func (r *userRepository) List(page, perPage int) ([]model.User, error) {
-	offset := (page - 1) * perPage
-	query := `SELECT * FROM users LIMIT ? OFFSET ?`
+// UseTOTPStep 记录已使用的时间步，返回false表示该验证码已被使用过
+func (r *userRepository) UseTOTPStep(id int, step int64) (bool, error) {
+	query := `UPDATE users SET totp_last_step = ? WHERE id = ? AND totp_last_step < ?`
+
+	result, err := r.db.Exec(query, step, id, step)
+	if err != nil {
+		return false, fmt.Errorf("failed to update totp step: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected == 1, nil
+}
+
+// SoftDelete 标记用户已注销并记录操作人，返回false表示用户不存在或已注销
+func (r *userRepository) SoftDelete(id, deletedBy int, at time.Time) (bool, error) {
+	query := `UPDATE users SET deleted_at = ?, deleted_by = ? WHERE id = ? AND deleted_at IS NULL`
+
+	result, err := r.db.Exec(query, at, deletedBy, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to soft delete user: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected == 1, nil
+}
+
+// Restore 撤销宽限期内的注销，注销时间不晚于deletedAfter时不恢复
+func (r *userRepository) Restore(id int, deletedAfter time.Time) (bool, error) {
+	query := `UPDATE users SET deleted_at = NULL, deleted_by = NULL
+			  WHERE id = ? AND deleted_at > ? AND anonymized_at IS NULL`
+
+	result, err := r.db.Exec(query, id, deletedAfter)
+	if err != nil {
+		return false, fmt.Errorf("failed to restore user: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected == 1, nil
+}
+
+// ListDeletedBefore 获取注销时间早于before且尚未匿名化的用户
+func (r *userRepository) ListDeletedBefore(before time.Time, limit int) ([]model.User, error) {
+	query := `SELECT * FROM users
+			  WHERE deleted_at IS NOT NULL AND deleted_at < ? AND anonymized_at IS NULL
+			  ORDER BY deleted_at ASC LIMIT ?`
 
 	var users []model.User
-	err := r.db.Select(&users, query, perPage, offset)
+	err := r.db.Select(&users, query, before, limit)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list users: %w", err)
+		return nil, fmt.Errorf("failed to list deleted users: %w", err)
 	}
 
 	return users, nil
 }
 
+// Anonymize 匿名化用户：按策略处理文章和系列，清除个人信息、登录凭据、合集和收藏，
+// 评论保留以维持评论串完整，表情回应保留以维持计数一致
+func (r *userRepository) Anonymize(id int, username, email string, policy model.DeletedPostsPolicy, reassignTo int) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	switch policy {
+	case model.DeletedPostsReassign:
+		if _, err := tx.Exec(`UPDATE posts SET user_id = ? WHERE user_id = ?`, reassignTo, id); err != nil {
+			return fmt.Errorf("failed to reassign posts: %w", err)
+		}
+		if _, err := tx.Exec(`UPDATE series SET user_id = ? WHERE user_id = ?`, reassignTo, id); err != nil {
+			return fmt.Errorf("failed to reassign series: %w", err)
+		}
+	case model.DeletedPostsDelete:
+		if _, err := tx.Exec(`DELETE FROM posts WHERE user_id = ?`, id); err != nil {
+			return fmt.Errorf("failed to delete posts: %w", err)
+		}
+		if _, err := tx.Exec(`DELETE FROM series WHERE user_id = ?`, id); err != nil {
+			return fmt.Errorf("failed to delete series: %w", err)
+		}
+	}
+
+	// 清除关注关系
+	if _, err := tx.Exec(`DELETE FROM user_follows WHERE follower_id = ? OR followee_id = ?`, id, id); err != nil {
+		return fmt.Errorf("failed to delete user follows: %w", err)
+	}
+
+	// 清除与身份相关的记录（会话删除时刷新令牌级联删除）
+	for _, table := range []string{
+		"tag_follows",
+		"sessions",
+		"password_resets",
+		"recovery_codes",
+		"user_wallets",
+		"personal_access_tokens",
+		"data_exports",
+		"collections",
+		"bookmarks",
+	} {
+		if _, err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE user_id = ?", table), id); err != nil {
+			return fmt.Errorf("failed to delete %s: %w", table, err)
+		}
+	}
+
+	query := `UPDATE users SET username = ?, email = ?, password = '', role = ?, avatar = NULL, bio = NULL,
+			  email_verified_at = NULL, verification_sent_at = NULL,
+			  totp_secret = NULL, totp_enabled_at = NULL, totp_last_step = 0,
+			  anonymized_at = ?
+			  WHERE id = ? AND anonymized_at IS NULL`
+
+	if _, err := tx.Exec(query, username, email, model.RoleReader, time.Now(), id); err != nil {
+		return fmt.Errorf("failed to anonymize user: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// List 分页获取用户列表，按注册时间排序
+func (r *userRepository) List(p *model.PageQuery) (*model.Page[model.User], error) {
+	page, err := usersByCreated.list(r.db, `SELECT * FROM users WHERE 1=1`, nil, p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	return page, nil
+}
+
+// usersByCreated 用户按注册时间正序分页
+var usersByCreated = keyset[model.User]{
+	name:     "created",
+	column:   "created_at",
+	idColumn: "id",
+	kind:     keyTime,
+	value:    func(u *model.User) interface{} { return u.CreatedAt },
+	id:       func(u *model.User) int { return u.ID },
+}
+
 // Count 获取用户总数
 func (r *userRepository) Count() (int, error) {
 	var count int
@@ -136,4 +355,117 @@ func (r *userRepository) Count() (int, error) {
 	}
 
 	return count, nil
+}
+
+// GetByIDs 批量获取用户
+func (r *userRepository) GetByIDs(ids []int) ([]model.User, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := sqlx.In(`SELECT * FROM users WHERE id IN (?)`, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build users query: %w", err)
+	}
+
+	var users []model.User
+	if err := r.db.Select(&users, r.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("failed to get users by ids: %w", err)
+	}
+
+	return users, nil
+}
+
+// GetStats 获取用户的已发布文章数、评论数和关注数
+func (r *userRepository) GetStats(id int) (*model.UserStats, error) {
+	var stats model.UserStats
+	query := `SELECT
+			  (SELECT COUNT(*) FROM posts WHERE user_id = ? AND status = 'published') AS post_count,
+			  (SELECT COUNT(*) FROM comments WHERE user_id = ?) AS comment_count,
+			  (SELECT COUNT(*) FROM user_follows WHERE followee_id = ?) AS follower_count,
+			  (SELECT COUNT(*) FROM user_follows WHERE follower_id = ?) AS following_count`
+
+	err := r.db.Get(&stats, query, id, id, id, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user stats: %w", err)
+	}
+
+	return &stats, nil
+}
+
+// directorySelect 用户目录查询的字段，最近活跃时间取最近一次发布文章或评论的时间，没有则为注册时间
+const directorySelect = `SELECT u.id, u.username, u.role, u.avatar, u.bio, u.created_at,
+	(SELECT COUNT(*) FROM posts p WHERE p.user_id = u.id AND p.status = 'published') AS post_count,
+	(SELECT COUNT(*) FROM comments c WHERE c.user_id = u.id) AS comment_count,
+	GREATEST(
+		COALESCE((SELECT MAX(p.created_at) FROM posts p WHERE p.user_id = u.id AND p.status = 'published'), u.created_at),
+		COALESCE((SELECT MAX(c.created_at) FROM comments c WHERE c.user_id = u.id), u.created_at)
+	) AS last_active_at
+	FROM users u`
+
+// directoryKeyset 用户目录的排序方式。文章数和最近活跃时间是计算列，
+// 在派生表d中才能用于游标条件，其余排序直接使用users表的列
+func directoryKeyset(sort model.UserDirectorySort) (keyset[model.UserDirectoryEntry], bool) {
+	id := func(e *model.UserDirectoryEntry) int { return e.ID }
+
+	switch sort {
+	case model.UserSortUsername:
+		return keyset[model.UserDirectoryEntry]{name: string(sort), column: "u.username", idColumn: "u.id", kind: keyString,
+			value: func(e *model.UserDirectoryEntry) interface{} { return e.Username }, id: id}, false
+	case model.UserSortPosts:
+		return keyset[model.UserDirectoryEntry]{name: string(sort), column: "d.post_count", idColumn: "d.id", kind: keyInt, desc: true,
+			value: func(e *model.UserDirectoryEntry) interface{} { return e.PostCount }, id: id}, true
+	case model.UserSortActive:
+		return keyset[model.UserDirectoryEntry]{name: string(sort), column: "d.last_active_at", idColumn: "d.id", kind: keyTime, desc: true,
+			value: func(e *model.UserDirectoryEntry) interface{} { return e.LastActiveAt }, id: id}, true
+	default:
+		return keyset[model.UserDirectoryEntry]{name: string(model.UserSortNewest), column: "u.created_at", idColumn: "u.id", kind: keyTime, desc: true,
+			value: func(e *model.UserDirectoryEntry) interface{} { return e.CreatedAt }, id: id}, false
+	}
+}
+
+// ListDirectory 分页获取用户目录（不含已注销用户）
+func (r *userRepository) ListDirectory(query *model.UserDirectoryQuery) (*model.Page[model.UserDirectoryEntry], error) {
+	whereClause, args := r.buildDirectoryWhereClause(query)
+
+	k, derived := directoryKeyset(query.Sort)
+	listQuery := directorySelect + whereClause
+	if derived {
+		listQuery = `SELECT * FROM (` + listQuery + `) d WHERE 1=1`
+	}
+
+	page, err := k.list(r.db, listQuery, args, &query.PageQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user directory: %w", err)
+	}
+
+	return page, nil
+}
+
+// CountDirectory 获取用户目录总数
+func (r *userRepository) CountDirectory(query *model.UserDirectoryQuery) (int, error) {
+	whereClause, args := r.buildDirectoryWhereClause(query)
+
+	var count int
+	err := r.db.Get(&count, `SELECT COUNT(*) FROM users u`+whereClause, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count user directory: %w", err)
+	}
+
+	return count, nil
+}
+
+// buildDirectoryWhereClause 构建用户目录的查询条件
+func (r *userRepository) buildDirectoryWhereClause(query *model.UserDirectoryQuery) (string, []interface{}) {
+	whereClause := ` WHERE u.deleted_at IS NULL`
+	var args []interface{}
+
+	if query.Prefix != "" {
+		// 转义LIKE通配符，只做前缀匹配
+		escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(query.Prefix)
+		whereClause += ` AND u.username LIKE ?`
+		args = append(args, escaped+"%")
+	}
+
+	return whereClause, args
 }
\ No newline at end of file
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// WalletRepository 钱包仓库接口
+type WalletRepository interface {
+	Create(wallet *model.Wallet) error
+	GetByAddress(address string) (*model.Wallet, error)
+	ListByUserID(userID int) ([]model.Wallet, error)
+	Delete(userID int, address string) error
+	CreateNonce(nonce *model.SIWENonce) error
+	ConsumeNonce(nonce string) (bool, error)
+	DeleteExpiredNonces(before time.Time) error
+}
+
+// walletRepository 钱包仓库实现
+type walletRepository struct {
+	db *sqlx.DB
+}
+
+// NewWalletRepository 创建钱包仓库
+func NewWalletRepository(db *sqlx.DB) WalletRepository {
+	return &walletRepository{db: db}
+}
+
+// Create 绑定钱包
+func (r *walletRepository) Create(wallet *model.Wallet) error {
+	query := `INSERT INTO user_wallets (user_id, address) VALUES (?, ?)`
+
+	result, err := r.db.Exec(query, wallet.UserID, wallet.Address)
+	if err != nil {
+		return fmt.Errorf("failed to create wallet: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	wallet.ID = int(id)
+	return nil
+}
+
+// GetByAddress 根据地址获取钱包
+func (r *walletRepository) GetByAddress(address string) (*model.Wallet, error) {
+	var wallet model.Wallet
+	query := `SELECT * FROM user_wallets WHERE address = ?`
+
+	err := r.db.Get(&wallet, query, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet by address: %w", err)
+	}
+
+	return &wallet, nil
+}
+
+// ListByUserID 获取用户绑定的所有钱包
+func (r *walletRepository) ListByUserID(userID int) ([]model.Wallet, error) {
+	query := `SELECT * FROM user_wallets WHERE user_id = ? ORDER BY created_at ASC`
+
+	var wallets []model.Wallet
+	err := r.db.Select(&wallets, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wallets: %w", err)
+	}
+
+	return wallets, nil
+}
+
+// Delete 解绑钱包
+func (r *walletRepository) Delete(userID int, address string) error {
+	query := `DELETE FROM user_wallets WHERE user_id = ? AND address = ?`
+
+	_, err := r.db.Exec(query, userID, address)
+	if err != nil {
+		return fmt.Errorf("failed to delete wallet: %w", err)
+	}
+
+	return nil
+}
+
+// CreateNonce 保存随机数
+func (r *walletRepository) CreateNonce(nonce *model.SIWENonce) error {
+	query := `INSERT INTO siwe_nonces (nonce, expires_at) VALUES (?, ?)`
+
+	_, err := r.db.Exec(query, nonce.Nonce, nonce.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to create siwe nonce: %w", err)
+	}
+
+	return nil
+}
+
+// ConsumeNonce 使用随机数，返回false表示随机数不存在、已过期或已被使用
+func (r *walletRepository) ConsumeNonce(nonce string) (bool, error) {
+	now := time.Now()
+	query := `UPDATE siwe_nonces SET used_at = ? WHERE nonce = ? AND used_at IS NULL AND expires_at > ?`
+
+	result, err := r.db.Exec(query, now, nonce, now)
+	if err != nil {
+		return false, fmt.Errorf("failed to consume siwe nonce: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected == 1, nil
+}
+
+// DeleteExpiredNonces 清理已过期或已使用的随机数
+func (r *walletRepository) DeleteExpiredNonces(before time.Time) error {
+	query := `DELETE FROM siwe_nonces WHERE expires_at < ? OR used_at IS NOT NULL`
+
+	_, err := r.db.Exec(query, before)
+	if err != nil {
+		return fmt.Errorf("failed to delete expired siwe nonces: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/duanyu/go-blog-system/internal/repository"
+)
+
+// BookmarkService 收藏服务接口
+type BookmarkService interface {
+	Add(actor model.Actor, postID int) error
+	Remove(userID, postID int) error
+	List(userID int, query *model.PageQuery) (*model.Page[model.BookmarkResponse], error)
+}
+
+// bookmarkService 收藏服务实现
+type bookmarkService struct {
+	bookmarkRepo repository.BookmarkRepository
+	reactionRepo repository.ReactionRepository
+	postRepo     repository.PostRepository
+	userRepo     repository.UserRepository
+}
+
+// NewBookmarkService 创建收藏服务
+func NewBookmarkService(
+	bookmarkRepo repository.BookmarkRepository,
+	reactionRepo repository.ReactionRepository,
+	postRepo repository.PostRepository,
+	userRepo repository.UserRepository,
+) BookmarkService {
+	return &bookmarkService{
+		bookmarkRepo: bookmarkRepo,
+		reactionRepo: reactionRepo,
+		postRepo:     postRepo,
+		userRepo:     userRepo,
+	}
+}
+
+// Add 收藏文章
+func (s *bookmarkService) Add(actor model.Actor, postID int) error {
+	if _, err := visiblePost(s.postRepo, postID, actor); err != nil {
+		return err
+	}
+
+	return s.bookmarkRepo.Add(actor.UserID, postID)
+}
+
+// Remove 取消收藏
+func (s *bookmarkService) Remove(userID, postID int) error {
+	return s.bookmarkRepo.Remove(userID, postID)
+}
+
+// List 分页获取收藏的文章，最近收藏的在前
+func (s *bookmarkService) List(userID int, query *model.PageQuery) (*model.Page[model.BookmarkResponse], error) {
+	normalizePage(query, 20)
+
+	page, err := s.bookmarkRepo.List(userID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	err = fillTotal(page, query, func() (int, error) { return s.bookmarkRepo.Count(userID) })
+	if err != nil {
+		return nil, fmt.Errorf("failed to count bookmarks: %w", err)
+	}
+
+	posts := make([]model.Post, len(page.Items))
+	for i := range page.Items {
+		posts[i] = page.Items[i].Post
+	}
+
+	postResponses, err := buildPostResponses(s.postRepo, s.userRepo, posts, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyViewerState(s.reactionRepo, s.bookmarkRepo, userID, postResponses); err != nil {
+		return nil, err
+	}
+
+	responses := make([]model.BookmarkResponse, len(page.Items))
+	for i := range page.Items {
+		responses[i] = model.BookmarkResponse{
+			PostResponse: postResponses[i],
+			BookmarkedAt: page.Items[i].BookmarkedAt,
+		}
+	}
+
+	return model.MapPage(page, responses), nil
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/duanyu/go-blog-system/internal/repository"
+)
+
+var (
+	// ErrCollectionNotFound 合集不存在
+	ErrCollectionNotFound = errors.New("collection not found")
+	// ErrCollectionAccessDenied 无权修改合集
+	ErrCollectionAccessDenied = errors.New("you don't have permission to modify this collection")
+	// ErrPostNotPublished 只有已发布的文章可以加入合集
+	ErrPostNotPublished = errors.New("only published posts can be added to a collection")
+	// ErrPostAlreadyInCollection 文章已在合集中
+	ErrPostAlreadyInCollection = errors.New("post is already in this collection")
+	// ErrCollectionFull 合集中的文章数已达上限
+	ErrCollectionFull = errors.New("collection has reached the maximum number of posts")
+)
+
+// CollectionService 合集服务接口
+type CollectionService interface {
+	Create(actor model.Actor, req *model.CollectionRequest) (*model.CollectionResponse, error)
+	Get(id int) (*model.CollectionResponse, error)
+	ListByUser(userID int) ([]model.CollectionResponse, error)
+	Update(id int, actor model.Actor, req *model.CollectionRequest) (*model.CollectionResponse, error)
+	Delete(id int, actor model.Actor) error
+	AddPost(id int, actor model.Actor, req *model.AddCollectionPostRequest) (*model.CollectionResponse, error)
+	RemovePost(id int, actor model.Actor, postID int) (*model.CollectionResponse, error)
+	Reorder(id int, actor model.Actor, postIDs []int) (*model.CollectionResponse, error)
+}
+
+// collectionService 合集服务实现
+type collectionService struct {
+	collectionRepo repository.CollectionRepository
+	postRepo       repository.PostRepository
+	userRepo       repository.UserRepository
+}
+
+// NewCollectionService 创建合集服务
+func NewCollectionService(
+	collectionRepo repository.CollectionRepository,
+	postRepo repository.PostRepository,
+	userRepo repository.UserRepository,
+) CollectionService {
+	return &collectionService{
+		collectionRepo: collectionRepo,
+		postRepo:       postRepo,
+		userRepo:       userRepo,
+	}
+}
+
+// Create 创建合集
+func (s *collectionService) Create(actor model.Actor, req *model.CollectionRequest) (*model.CollectionResponse, error) {
+	collection := &model.Collection{
+		UserID:      actor.UserID,
+		Title:       req.Title,
+		Description: optionalText(req.Description),
+	}
+
+	if err := s.collectionRepo.Create(collection); err != nil {
+		return nil, err
+	}
+
+	return s.Get(collection.ID)
+}
+
+// Get 获取合集及其中已发布的文章
+func (s *collectionService) Get(id int) (*model.CollectionResponse, error) {
+	collection, err := s.collectionRepo.GetByID(id)
+	if err != nil {
+		return nil, ErrCollectionNotFound
+	}
+
+	entries, err := s.collectionRepo.GetEntries(id)
+	if err != nil {
+		return nil, err
+	}
+
+	posts := make([]model.Post, len(entries))
+	for i := range entries {
+		posts[i] = entries[i].Post
+	}
+
+	postResponses, err := buildPostResponses(s.postRepo, s.userRepo, posts, false)
+	if err != nil {
+		return nil, err
+	}
+
+	curators, err := loadPublicUsers(s.userRepo, []int{collection.UserID})
+	if err != nil {
+		return nil, err
+	}
+
+	response := collection.ToResponse()
+	response.User = curators[collection.UserID]
+	response.Items = make([]model.CollectionItemResponse, len(entries))
+	for i := range entries {
+		response.Items[i] = model.CollectionItemResponse{
+			Post:    postResponses[i],
+			Note:    entries[i].Note,
+			AddedAt: entries[i].AddedAt,
+		}
+	}
+
+	return &response, nil
+}
+
+// ListByUser 获取用户的全部合集（不包含文章列表）
+func (s *collectionService) ListByUser(userID int) ([]model.CollectionResponse, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil || user.IsDeleted() {
+		return nil, ErrUserNotFound
+	}
+
+	collections, err := s.collectionRepo.ListByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]model.CollectionResponse, len(collections))
+	for i := range collections {
+		responses[i] = collections[i].ToResponse()
+		responses[i].User = user.ToPublicResponse()
+	}
+
+	return responses, nil
+}
+
+// Update 更新合集标题和简介
+func (s *collectionService) Update(id int, actor model.Actor, req *model.CollectionRequest) (*model.CollectionResponse, error) {
+	collection, err := s.getModifiable(id, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	collection.Title = req.Title
+	collection.Description = optionalText(req.Description)
+
+	if err := s.collectionRepo.Update(collection); err != nil {
+		return nil, err
+	}
+
+	return s.Get(id)
+}
+
+// Delete 删除合集
+func (s *collectionService) Delete(id int, actor model.Actor) error {
+	if _, err := s.getModifiable(id, actor); err != nil {
+		return err
+	}
+
+	return s.collectionRepo.Delete(id)
+}
+
+// AddPost 将已发布的文章添加到合集末尾，文章可以来自任何作者
+func (s *collectionService) AddPost(id int, actor model.Actor, req *model.AddCollectionPostRequest) (*model.CollectionResponse, error) {
+	if _, err := s.getModifiable(id, actor); err != nil {
+		return nil, err
+	}
+
+	post, err := s.postRepo.GetByID(req.PostID)
+	if err != nil {
+		return nil, ErrPostNotFound
+	}
+
+	if post.Status != model.PostStatusPublished {
+		return nil, ErrPostNotPublished
+	}
+
+	exists, err := s.collectionRepo.HasPost(id, req.PostID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrPostAlreadyInCollection
+	}
+
+	added, err := s.collectionRepo.AddPost(id, req.PostID, optionalText(req.Note))
+	if err != nil {
+		return nil, err
+	}
+	if !added {
+		return nil, ErrCollectionFull
+	}
+
+	return s.Get(id)
+}
+
+// RemovePost 从合集中移除文章
+func (s *collectionService) RemovePost(id int, actor model.Actor, postID int) (*model.CollectionResponse, error) {
+	if _, err := s.getModifiable(id, actor); err != nil {
+		return nil, err
+	}
+
+	if err := s.collectionRepo.RemovePost(id, postID); err != nil {
+		return nil, err
+	}
+
+	return s.Get(id)
+}
+
+// Reorder 调整合集中文章的顺序
+func (s *collectionService) Reorder(id int, actor model.Actor, postIDs []int) (*model.CollectionResponse, error) {
+	if _, err := s.getModifiable(id, actor); err != nil {
+		return nil, err
+	}
+
+	if err := s.collectionRepo.Reorder(id, postIDs); err != nil {
+		return nil, err
+	}
+
+	return s.Get(id)
+}
+
+// getModifiable 获取合集并检查操作者能否修改（创建者或编辑）
+func (s *collectionService) getModifiable(id int, actor model.Actor) (*model.Collection, error) {
+	collection, err := s.collectionRepo.GetByID(id)
+	if err != nil {
+		return nil, ErrCollectionNotFound
+	}
+
+	if !actor.CanModify(collection.UserID, model.PermissionEditAnyPost) {
+		return nil, ErrCollectionAccessDenied
+	}
+
+	return collection, nil
+}
@@ -13,8 +13,8 @@ type CommentService interface {
 	Create(userID int, req *model.CreateCommentRequest) (*model.CommentResponse, error)
 	GetByID(id int) (*model.CommentResponse, error)
 	Update(id, userID int, req *model.UpdateCommentRequest) (*model.CommentResponse, error)
-	Delete(id, userID int) error
-	GetByPostID(postID int) ([]model.CommentResponse, error)
+	Delete(id int, actor model.Actor) error
+	GetByPostID(postID int, query *model.PageQuery) (*model.Page[model.CommentResponse], error)
 }
 
 // commentService 评论服务实现
@@ -39,8 +39,18 @@ func NewCommentService(
 
 // Create 创建评论
 func (s *commentService) Create(userID int, req *model.CreateCommentRequest) (*model.CommentResponse, error) {
+	// 获取用户信息
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if err := checkEmailVerified(user); err != nil {
+		return nil, err
+	}
+
 	// 检查文章是否存在
-	_, err := s.postRepo.GetByID(req.PostID)
+	_, err = s.postRepo.GetByID(req.PostID)
 	if err != nil {
 		return nil, fmt.Errorf("post not found: %w", err)
 	}
@@ -70,28 +80,16 @@ func (s *commentService) Create(userID int, req *model.CreateCommentRequest) (*m
 		return nil, fmt.Errorf("failed to create comment: %w", err)
 	}
 
-	// 获取用户信息
-	user, err := s.userRepo.GetByID(userID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get user: %w", err)
-	}
-
 	// 构建响应
 	response := &model.CommentResponse{
 		ID:        comment.ID,
 		Content:   comment.Content,
 		PostID:    comment.PostID,
 		ParentID:  comment.ParentID,
+		Reactions: comment.Reactions,
 		CreatedAt: comment.CreatedAt,
 		UpdatedAt: comment.UpdatedAt,
-		User: &model.UserResponse{
-			ID:        user.ID,
-			Username:  user.Username,
-			Email:     user.Email,
-			Avatar:    user.Avatar,
-			Bio:       user.Bio,
-			CreatedAt: user.CreatedAt,
-		},
+		User:      user.ToPublicResponse(),
 	}
 
 	return response, nil
@@ -123,16 +121,10 @@ func (s *commentService) GetByID(id int) (*model.CommentResponse, error) {
 		Content:   comment.Content,
 		PostID:    comment.PostID,
 		ParentID:  comment.ParentID,
+		Reactions: comment.Reactions,
 		CreatedAt: comment.CreatedAt,
 		UpdatedAt: comment.UpdatedAt,
-		User: &model.UserResponse{
-			ID:        user.ID,
-			Username:  user.Username,
-			Email:     user.Email,
-			Avatar:    user.Avatar,
-			Bio:       user.Bio,
-			CreatedAt: user.CreatedAt,
-		},
+		User:      user.ToPublicResponse(),
 	}
 
 	// 添加回复
@@ -178,31 +170,25 @@ func (s *commentService) Update(id, userID int, req *model.UpdateCommentRequest)
 		Content:   comment.Content,
 		PostID:    comment.PostID,
 		ParentID:  comment.ParentID,
+		Reactions: comment.Reactions,
 		CreatedAt: comment.CreatedAt,
 		UpdatedAt: comment.UpdatedAt,
-		User: &model.UserResponse{
-			ID:        user.ID,
-			Username:  user.Username,
-			Email:     user.Email,
-			Avatar:    user.Avatar,
-			Bio:       user.Bio,
-			CreatedAt: user.CreatedAt,
-		},
+		User:      user.ToPublicResponse(),
 	}
 
 	return response, nil
 }
 
 // Delete 删除评论
-func (s *commentService) Delete(id, userID int) error {
+func (s *commentService) Delete(id int, actor model.Actor) error {
 	// 获取评论
 	comment, err := s.commentRepo.GetByID(id)
 	if err != nil {
 		return fmt.Errorf("failed to get comment: %w", err)
 	}
 
-	// 检查权限
-	if comment.UserID != userID {
+	// 检查权限（评论者本人或版主）
+	if !actor.CanModify(comment.UserID, model.PermissionModerateComments) {
 		return errors.New("you don't have permission to delete this comment")
 	}
 
@@ -210,14 +196,22 @@ func (s *commentService) Delete(id, userID int) error {
 	return s.commentRepo.Delete(id)
 }
 
-// GetByPostID 获取文章的所有评论
-func (s *commentService) GetByPostID(postID int) ([]model.CommentResponse, error) {
-	// 获取文章的所有顶级评论
-	comments, err := s.commentRepo.GetByPostID(postID)
+// GetByPostID 分页获取文章的顶级评论及其回复
+func (s *commentService) GetByPostID(postID int, query *model.PageQuery) (*model.Page[model.CommentResponse], error) {
+	normalizePage(query, 20)
+
+	// 获取文章的顶级评论
+	page, err := s.commentRepo.GetByPostID(postID, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get comments: %w", err)
 	}
 
+	err = fillTotal(page, query, func() (int, error) { return s.commentRepo.CountByPostID(postID) })
+	if err != nil {
+		return nil, fmt.Errorf("failed to count comments: %w", err)
+	}
+	comments := page.Items
+
 	// 构建响应
 	responses := make([]model.CommentResponse, len(comments))
 	for i, comment := range comments {
@@ -238,16 +232,10 @@ func (s *commentService) GetByPostID(postID int) ([]model.CommentResponse, error
 			Content:   comment.Content,
 			PostID:    comment.PostID,
 			ParentID:  comment.ParentID,
+			Reactions: comment.Reactions,
 			CreatedAt: comment.CreatedAt,
 			UpdatedAt: comment.UpdatedAt,
-			User: &model.UserResponse{
-				ID:        user.ID,
-				Username:  user.Username,
-				Email:     user.Email,
-				Avatar:    user.Avatar,
-				Bio:       user.Bio,
-				CreatedAt: user.CreatedAt,
-			},
+			User:      user.ToPublicResponse(),
 		}
 
 		// 添加回复
@@ -259,5 +247,5 @@ func (s *commentService) GetByPostID(postID int) ([]model.CommentResponse, error
 		}
 	}
 
-	return responses, nil
-}
\ No newline at end of file
+	return model.MapPage(page, responses), nil
+}
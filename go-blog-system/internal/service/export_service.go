@@ -0,0 +1,595 @@
+package service
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/duanyu/go-blog-system/internal/repository"
+	"github.com/duanyu/go-blog-system/pkg/mailer"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+const (
+	// dataExportPurpose 数据导出下载链接用途
+	dataExportPurpose = "data-export"
+	// exportPageSize 导出文章时每页读取的数量
+	exportPageSize = 100
+)
+
+// ErrExportNotFound 导出任务不存在
+var ErrExportNotFound = errors.New("data export not found")
+
+// DataExportFile 可供下载的导出文件
+type DataExportFile struct {
+	Path     string
+	Filename string
+}
+
+// ExportService 个人数据导出服务接口
+type ExportService interface {
+	Request(userID int) (*model.DataExportResponse, error)
+	Get(userID, id int) (*model.DataExportResponse, error)
+	Download(token string) (*DataExportFile, error)
+	FailInterrupted() error
+	PurgeExpired() error
+}
+
+// exportService 个人数据导出服务实现
+type exportService struct {
+	exportRepo        repository.DataExportRepository
+	userRepo          repository.UserRepository
+	postRepo          repository.PostRepository
+	commentRepo       repository.CommentRepository
+	walletRepo        repository.WalletRepository
+	tokenRepo         repository.TokenRepository
+	personalTokenRepo repository.PersonalAccessTokenRepository
+	followRepo        repository.FollowRepository
+	revisionRepo      repository.PostRevisionRepository
+	seriesRepo        repository.SeriesRepository
+	collectionRepo    repository.CollectionRepository
+	bookmarkRepo      repository.BookmarkRepository
+	reactionRepo      repository.ReactionRepository
+	mailer            mailer.Mailer
+}
+
+// NewExportService 创建个人数据导出服务
+func NewExportService(
+	exportRepo repository.DataExportRepository,
+	userRepo repository.UserRepository,
+	postRepo repository.PostRepository,
+	commentRepo repository.CommentRepository,
+	walletRepo repository.WalletRepository,
+	tokenRepo repository.TokenRepository,
+	personalTokenRepo repository.PersonalAccessTokenRepository,
+	followRepo repository.FollowRepository,
+	revisionRepo repository.PostRevisionRepository,
+	seriesRepo repository.SeriesRepository,
+	collectionRepo repository.CollectionRepository,
+	bookmarkRepo repository.BookmarkRepository,
+	reactionRepo repository.ReactionRepository,
+	mail mailer.Mailer,
+) ExportService {
+	return &exportService{
+		exportRepo:        exportRepo,
+		userRepo:          userRepo,
+		postRepo:          postRepo,
+		commentRepo:       commentRepo,
+		walletRepo:        walletRepo,
+		tokenRepo:         tokenRepo,
+		personalTokenRepo: personalTokenRepo,
+		followRepo:        followRepo,
+		revisionRepo:      revisionRepo,
+		seriesRepo:        seriesRepo,
+		collectionRepo:    collectionRepo,
+		bookmarkRepo:      bookmarkRepo,
+		reactionRepo:      reactionRepo,
+		mailer:            mail,
+	}
+}
+
+// Request 申请导出个人数据，已有进行中的任务时直接返回该任务
+func (s *exportService) Request(userID int) (*model.DataExportResponse, error) {
+	if latest, err := s.exportRepo.GetLatestByUserID(userID); err == nil && latest.IsInProgress() {
+		return s.response(latest), nil
+	}
+
+	export := &model.DataExport{
+		UserID:     userID,
+		Status:     model.DataExportPending,
+		InstanceID: exportInstanceID(),
+		CreatedAt:  time.Now(),
+	}
+
+	if err := s.exportRepo.Create(export); err != nil {
+		return nil, err
+	}
+
+	// 异步生成导出文件
+	go s.process(export.ID, userID)
+
+	return s.response(export), nil
+}
+
+// Get 获取导出任务状态，完成后附带下载链接
+func (s *exportService) Get(userID, id int) (*model.DataExportResponse, error) {
+	export, err := s.exportRepo.GetByID(id)
+	if err != nil || export.UserID != userID {
+		return nil, ErrExportNotFound
+	}
+
+	return s.response(export), nil
+}
+
+// Download 校验下载链接并返回导出文件
+func (s *exportService) Download(token string) (*DataExportFile, error) {
+	payload, err := verifySignedToken(dataExportPurpose, token)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := strconv.Atoi(payload)
+	if err != nil {
+		return nil, ErrInvalidSignedToken
+	}
+
+	export, err := s.exportRepo.GetByID(id)
+	if err != nil || export.Status != model.DataExportCompleted || export.FilePath == nil {
+		return nil, ErrExportNotFound
+	}
+	if export.ExpiresAt != nil && time.Now().After(*export.ExpiresAt) {
+		return nil, ErrInvalidSignedToken
+	}
+
+	return &DataExportFile{
+		Path:     *export.FilePath,
+		Filename: fmt.Sprintf("blog-export-%d-%s.zip", export.UserID, export.CreatedAt.Format("20060102")),
+	}, nil
+}
+
+// FailInterrupted 服务启动时将本实例上次中断的导出任务标记为失败，其他实例的任务不受影响
+func (s *exportService) FailInterrupted() error {
+	return s.exportRepo.FailInProgress(exportInstanceID(), "export was interrupted, please request a new one")
+}
+
+// PurgeExpired 删除过期的导出文件和记录
+func (s *exportService) PurgeExpired() error {
+	exports, err := s.exportRepo.ListExpired(time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, export := range exports {
+		if export.FilePath != nil {
+			if err := os.Remove(*export.FilePath); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove export file: %w", err)
+			}
+		}
+
+		if err := s.exportRepo.Delete(export.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// process 生成导出文件并通知用户
+func (s *exportService) process(id, userID int) {
+	if err := s.exportRepo.UpdateStatus(id, model.DataExportProcessing); err != nil {
+		logrus.Errorf("Failed to start data export %d: %v", id, err)
+		return
+	}
+
+	path, err := s.build(id, userID)
+	if err != nil {
+		logrus.Errorf("Failed to build data export %d: %v", id, err)
+		if err := s.exportRepo.Fail(id, "failed to build export"); err != nil {
+			logrus.Errorf("Failed to mark data export %d failed: %v", id, err)
+		}
+		return
+	}
+
+	expiresAt := time.Now().Add(exportExpiration())
+	if err := s.exportRepo.Complete(id, path, expiresAt); err != nil {
+		logrus.Errorf("Failed to complete data export %d: %v", id, err)
+		return
+	}
+
+	if err := s.notify(id, userID); err != nil {
+		logrus.Errorf("Failed to send data export notification %d: %v", id, err)
+	}
+}
+
+// exportProfile 导出文件中的个人资料
+type exportProfile struct {
+	*model.UserResponse
+	MFAEnabled bool `json:"mfa_enabled"`
+}
+
+// exportPost 导出文件中的文章
+type exportPost struct {
+	model.PostResponse
+	Tags []model.Tag `json:"tags"`
+}
+
+// exportFollows 导出文件中的关注列表
+type exportFollows struct {
+	Users []model.FollowedUser `json:"users"`
+	Tags  []model.FollowedTag  `json:"tags"`
+}
+
+// exportSeries 导出文件中的系列，PostIDs按系列中的顺序排列
+type exportSeries struct {
+	model.Series
+	PostIDs []int `json:"post_ids"`
+}
+
+// exportCollection 导出文件中的合集
+type exportCollection struct {
+	model.Collection
+	Items []exportCollectionItem `json:"items"`
+}
+
+// exportCollectionItem 导出文件中的合集文章
+type exportCollectionItem struct {
+	PostID  int       `json:"post_id"`
+	Title   string    `json:"title"`
+	Note    *string   `json:"note"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+// build 生成包含个人数据的ZIP文件，返回文件路径
+func (s *exportService) build(id, userID int) (path string, err error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return "", err
+	}
+
+	dir := viper.GetString("data_export.dir")
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	suffix, err := randomToken(8)
+	if err != nil {
+		return "", err
+	}
+	path = filepath.Join(dir, fmt.Sprintf("export-%d-%s.zip", id, suffix))
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return "", fmt.Errorf("failed to create export file: %w", err)
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil && closeErr != nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(path)
+		}
+	}()
+
+	archive := zip.NewWriter(file)
+	defer func() {
+		if closeErr := archive.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to finalize export archive: %w", closeErr)
+		}
+	}()
+
+	// 个人资料
+	wallets, err := s.walletRepo.ListByUserID(userID)
+	if err != nil {
+		return "", err
+	}
+	if err := writeJSON(archive, "profile.json", exportProfile{
+		UserResponse: userResponse(user),
+		MFAEnabled:   user.IsMFAEnabled(),
+	}); err != nil {
+		return "", err
+	}
+	if err := writeJSON(archive, "wallets.json", wallets); err != nil {
+		return "", err
+	}
+
+	// 文章（含标签），同时导出为Markdown
+	posts, err := s.collectPosts(userID)
+	if err != nil {
+		return "", err
+	}
+	if err := writeJSON(archive, "posts.json", posts); err != nil {
+		return "", err
+	}
+	for _, post := range posts {
+		if err := writeFile(archive, fmt.Sprintf("posts/%d.md", post.ID), postMarkdown(post)); err != nil {
+			return "", err
+		}
+
+		// 修订历史，最新的版本在前
+		revisions, err := s.revisionRepo.ListByPostID(post.ID)
+		if err != nil {
+			return "", err
+		}
+		if err := writeJSON(archive, fmt.Sprintf("revisions/%d.json", post.ID), revisions); err != nil {
+			return "", err
+		}
+	}
+
+	// 系列和合集
+	series, err := s.collectSeries(userID)
+	if err != nil {
+		return "", err
+	}
+	if err := writeJSON(archive, "series.json", series); err != nil {
+		return "", err
+	}
+
+	collections, err := s.collectCollections(userID)
+	if err != nil {
+		return "", err
+	}
+	if err := writeJSON(archive, "collections.json", collections); err != nil {
+		return "", err
+	}
+
+	// 评论
+	comments, err := s.commentRepo.GetByUserID(userID)
+	if err != nil {
+		return "", err
+	}
+	if err := writeJSON(archive, "comments.json", comments); err != nil {
+		return "", err
+	}
+
+	// 收藏和表情回应
+	bookmarks, err := s.bookmarkRepo.ListAll(userID)
+	if err != nil {
+		return "", err
+	}
+	if err := writeJSON(archive, "bookmarks.json", bookmarks); err != nil {
+		return "", err
+	}
+
+	reactions, err := s.reactionRepo.ListByUserID(userID)
+	if err != nil {
+		return "", err
+	}
+	if err := writeJSON(archive, "reactions.json", reactions); err != nil {
+		return "", err
+	}
+
+	// 关注的用户和标签
+	followedUsers, err := s.followRepo.ListFollowedUsers(userID)
+	if err != nil {
+		return "", err
+	}
+	followedTags, err := s.followRepo.ListFollowedTags(userID)
+	if err != nil {
+		return "", err
+	}
+	if err := writeJSON(archive, "follows.json", exportFollows{Users: followedUsers, Tags: followedTags}); err != nil {
+		return "", err
+	}
+
+	// 会话和个人访问令牌的元数据
+	sessions, err := s.tokenRepo.ListActiveSessions(userID, time.Now())
+	if err != nil {
+		return "", err
+	}
+	if err := writeJSON(archive, "sessions.json", sessions); err != nil {
+		return "", err
+	}
+
+	tokens, err := s.personalTokenRepo.ListByUserID(userID)
+	if err != nil {
+		return "", err
+	}
+	tokenResponses := make([]*model.PersonalAccessTokenResponse, 0, len(tokens))
+	for i := range tokens {
+		tokenResponses = append(tokenResponses, personalTokenResponse(&tokens[i]))
+	}
+	if err := writeJSON(archive, "personal_access_tokens.json", tokenResponses); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+// collectPosts 分页读取用户的所有文章及其标签
+func (s *exportService) collectPosts(userID int) ([]exportPost, error) {
+	var result []exportPost
+
+	query := &model.PostQuery{
+		UserID:    &userID,
+		PageQuery: model.PageQuery{PerPage: exportPageSize},
+	}
+	for {
+		page, err := s.postRepo.List(query)
+		if err != nil {
+			return nil, err
+		}
+
+		posts := page.Items
+		for i := range posts {
+			tags, err := s.postRepo.GetPostTags(posts[i].ID)
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, exportPost{
+				PostResponse: posts[i].ToResponse(),
+				Tags:         tags,
+			})
+		}
+
+		if page.NextCursor == "" {
+			return result, nil
+		}
+		query.Cursor = page.NextCursor
+	}
+}
+
+// collectSeries 读取用户的全部系列及其中的文章（包括未发布的文章）
+func (s *exportService) collectSeries(userID int) ([]exportSeries, error) {
+	series, err := s.seriesRepo.ListByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]exportSeries, len(series))
+	for i := range series {
+		posts, err := s.seriesRepo.GetPosts(series[i].ID, false)
+		if err != nil {
+			return nil, err
+		}
+
+		result[i] = exportSeries{Series: series[i], PostIDs: make([]int, len(posts))}
+		for j := range posts {
+			result[i].PostIDs[j] = posts[j].ID
+		}
+	}
+
+	return result, nil
+}
+
+// collectCollections 读取用户的全部合集及其中已发布的文章和推荐语
+func (s *exportService) collectCollections(userID int) ([]exportCollection, error) {
+	collections, err := s.collectionRepo.ListByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]exportCollection, len(collections))
+	for i := range collections {
+		entries, err := s.collectionRepo.GetEntries(collections[i].ID)
+		if err != nil {
+			return nil, err
+		}
+
+		result[i] = exportCollection{Collection: collections[i], Items: make([]exportCollectionItem, len(entries))}
+		for j, entry := range entries {
+			result[i].Items[j] = exportCollectionItem{
+				PostID:  entry.ID,
+				Title:   entry.Title,
+				Note:    entry.Note,
+				AddedAt: entry.AddedAt,
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// notify 发送导出完成通知邮件
+func (s *exportService) notify(id, userID int) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+
+	link := exportDownloadURL(id, exportExpiration())
+	return s.mailer.Send(&mailer.Message{
+		To:      []string{user.Email},
+		Subject: "您的个人数据导出已完成",
+		Body: fmt.Sprintf("%s，您好：\n\n您申请的个人数据导出已生成，请在%d小时内通过以下链接下载：\n\n%s\n\n如果这不是您本人的操作，请尽快修改密码。\n",
+			user.Username, int(exportExpiration().Hours()), link),
+	})
+}
+
+// response 构建导出任务响应，已完成且未过期的任务附带下载链接
+func (s *exportService) response(export *model.DataExport) *model.DataExportResponse {
+	resp := &model.DataExportResponse{
+		ID:          export.ID,
+		Status:      export.Status,
+		Error:       export.Error,
+		CreatedAt:   export.CreatedAt,
+		CompletedAt: export.CompletedAt,
+		ExpiresAt:   export.ExpiresAt,
+	}
+
+	if export.Status == model.DataExportCompleted && export.ExpiresAt != nil {
+		if ttl := time.Until(*export.ExpiresAt); ttl > 0 {
+			resp.DownloadURL = exportDownloadURL(export.ID, ttl)
+		}
+	}
+
+	return resp
+}
+
+// exportDownloadURL 生成限时下载链接
+func exportDownloadURL(id int, ttl time.Duration) string {
+	token := signToken(dataExportPurpose, strconv.Itoa(id), ttl)
+	return fmt.Sprintf("%s/api/exports/download?token=%s", viper.GetString("app.base_url"), token)
+}
+
+// exportExpiration 导出文件的保留时间
+func exportExpiration() time.Duration {
+	return time.Hour * time.Duration(viper.GetInt("data_export.expiration"))
+}
+
+// exportInstanceID 当前实例的标识，未配置时使用主机名
+func exportInstanceID() string {
+	if id := viper.GetString("data_export.instance_id"); id != "" {
+		return id
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return ""
+	}
+
+	return hostname
+}
+
+// postMarkdown 将文章转换为带元信息的Markdown
+func postMarkdown(post exportPost) string {
+	tagNames := make([]string, 0, len(post.Tags))
+	for _, tag := range post.Tags {
+		tagNames = append(tagNames, strconv.Quote(tag.Name))
+	}
+
+	var b strings.Builder
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "title: %s\n", strconv.Quote(post.Title))
+	fmt.Fprintf(&b, "status: %s\n", post.Status)
+	fmt.Fprintf(&b, "tags: [%s]\n", strings.Join(tagNames, ", "))
+	fmt.Fprintf(&b, "created_at: %s\n", post.CreatedAt.Format(time.RFC3339))
+	fmt.Fprintf(&b, "updated_at: %s\n", post.UpdatedAt.Format(time.RFC3339))
+	b.WriteString("---\n\n")
+	b.WriteString(post.Content)
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+// writeJSON 将数据以JSON格式写入压缩包
+func writeJSON(archive *zip.Writer, name string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+
+	return writeFile(archive, name, string(data))
+}
+
+// writeFile 向压缩包写入文件
+func writeFile(archive *zip.Writer, name, content string) error {
+	w, err := archive.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to create %s in archive: %w", name, err)
+	}
+
+	if _, err := w.Write([]byte(content)); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/duanyu/go-blog-system/internal/repository"
+)
+
+var (
+	// ErrCannotFollowSelf 不能关注自己
+	ErrCannotFollowSelf = errors.New("cannot follow yourself")
+	// ErrTagNotFound 标签不存在
+	ErrTagNotFound = errors.New("tag not found")
+)
+
+// FollowService 关注服务接口
+type FollowService interface {
+	FollowUser(followerID, followeeID int) error
+	UnfollowUser(followerID, followeeID int) error
+	FollowTag(userID, tagID int) error
+	UnfollowTag(userID, tagID int) error
+	Feed(userID int, query *model.PageQuery) (*model.Page[model.PostResponse], error)
+}
+
+// followService 关注服务实现
+type followService struct {
+	followRepo repository.FollowRepository
+	userRepo   repository.UserRepository
+	tagRepo    repository.TagRepository
+	postRepo   repository.PostRepository
+}
+
+// NewFollowService 创建关注服务
+func NewFollowService(
+	followRepo repository.FollowRepository,
+	userRepo repository.UserRepository,
+	tagRepo repository.TagRepository,
+	postRepo repository.PostRepository,
+) FollowService {
+	return &followService{
+		followRepo: followRepo,
+		userRepo:   userRepo,
+		tagRepo:    tagRepo,
+		postRepo:   postRepo,
+	}
+}
+
+// FollowUser 关注用户
+func (s *followService) FollowUser(followerID, followeeID int) error {
+	if followerID == followeeID {
+		return ErrCannotFollowSelf
+	}
+
+	user, err := s.userRepo.GetByID(followeeID)
+	if err != nil || user.IsDeleted() {
+		return ErrUserNotFound
+	}
+
+	return s.followRepo.FollowUser(followerID, followeeID)
+}
+
+// UnfollowUser 取消关注用户
+func (s *followService) UnfollowUser(followerID, followeeID int) error {
+	return s.followRepo.UnfollowUser(followerID, followeeID)
+}
+
+// FollowTag 关注标签
+func (s *followService) FollowTag(userID, tagID int) error {
+	if _, err := s.tagRepo.GetByID(tagID); err != nil {
+		return ErrTagNotFound
+	}
+
+	return s.followRepo.FollowTag(userID, tagID)
+}
+
+// UnfollowTag 取消关注标签
+func (s *followService) UnfollowTag(userID, tagID int) error {
+	return s.followRepo.UnfollowTag(userID, tagID)
+}
+
+// Feed 获取个人动态流：关注的作者和标签下的已发布文章，按发布时间倒序
+func (s *followService) Feed(userID int, query *model.PageQuery) (*model.Page[model.PostResponse], error) {
+	normalizePage(query, 20)
+
+	page, err := s.postRepo.ListFeed(userID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	responses, err := buildPostResponses(s.postRepo, s.userRepo, page.Items, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return model.MapPage(page, responses), nil
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/duanyu/go-blog-system/internal/repository"
+	"github.com/spf13/viper"
+)
+
+// ErrTooManyLoginAttempts 登录失败次数过多
+var ErrTooManyLoginAttempts = errors.New("too many failed login attempts")
+
+// LoginThrottledError 登录被限流，RetryAfter为需要等待的时间
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+// Error 实现error接口
+func (e *LoginThrottledError) Error() string {
+	return fmt.Sprintf("%s, retry after %d seconds", ErrTooManyLoginAttempts, e.RetryAfterSeconds())
+}
+
+// Unwrap 支持errors.Is(err, ErrTooManyLoginAttempts)
+func (e *LoginThrottledError) Unwrap() error {
+	return ErrTooManyLoginAttempts
+}
+
+// RetryAfterSeconds 需要等待的秒数（向上取整）
+func (e *LoginThrottledError) RetryAfterSeconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
+
+// throttlePolicy 限流策略：超过免费次数后指数退避，达到上限后锁定
+type throttlePolicy struct {
+	freeAttempts int
+	maxFailures  int
+	lockout      time.Duration
+}
+
+// blockedUntil 根据失败次数计算禁止登录的截止时间，返回零值表示不限制
+func (p throttlePolicy) blockedUntil(attempt *model.LoginAttempt) time.Time {
+	if p.maxFailures > 0 && attempt.Failures >= p.maxFailures {
+		return attempt.LastFailureAt.Add(p.lockout)
+	}
+
+	if attempt.Failures <= p.freeAttempts {
+		return time.Time{}
+	}
+
+	base := time.Second * time.Duration(viper.GetInt("security.login_throttle.backoff_base"))
+	maxDelay := time.Second * time.Duration(viper.GetInt("security.login_throttle.backoff_max"))
+	if base <= 0 {
+		base = time.Second
+	}
+
+	delay := base
+	for i := p.freeAttempts + 1; i < attempt.Failures && (maxDelay <= 0 || delay < maxDelay); i++ {
+		delay *= 2
+	}
+	if maxDelay > 0 && delay > maxDelay {
+		delay = maxDelay
+	}
+
+	return attempt.LastFailureAt.Add(delay)
+}
+
+// LoginThrottle 登录限流服务接口，按账号和IP分别统计失败次数
+type LoginThrottle interface {
+	Check(username, ip string) error
+	RecordFailure(username, ip string) error
+	RecordSuccess(username string) error
+	Unlock(username string) error
+	PurgeExpired() error
+}
+
+// loginThrottle 登录限流服务实现
+type loginThrottle struct {
+	attemptRepo repository.LoginAttemptRepository
+}
+
+// NewLoginThrottle 创建登录限流服务
+func NewLoginThrottle(attemptRepo repository.LoginAttemptRepository) LoginThrottle {
+	return &loginThrottle{attemptRepo: attemptRepo}
+}
+
+// Check 检查账号和IP当前是否允许尝试登录
+func (t *loginThrottle) Check(username, ip string) error {
+	now := time.Now()
+	var retryAfter time.Duration
+
+	for _, key := range throttleKeys(username, ip) {
+		attempt, err := t.attemptRepo.Get(key)
+		if err != nil {
+			return err
+		}
+		if attempt == nil || attempt.BlockedUntil == nil {
+			continue
+		}
+
+		if wait := attempt.BlockedUntil.Sub(now); wait > retryAfter {
+			retryAfter = wait
+		}
+	}
+
+	if retryAfter > 0 {
+		return &LoginThrottledError{RetryAfter: retryAfter}
+	}
+
+	return nil
+}
+
+// RecordFailure 记录一次登录失败并更新退避或锁定时间
+func (t *loginThrottle) RecordFailure(username, ip string) error {
+	now := time.Now()
+	resetBefore := now.Add(-time.Minute * time.Duration(viper.GetInt("security.login_throttle.reset_after")))
+
+	keys := throttleKeys(username, ip)
+	policies := map[string]throttlePolicy{
+		keys[0]: accountThrottlePolicy(),
+	}
+	if len(keys) > 1 {
+		policies[keys[1]] = ipThrottlePolicy()
+	}
+
+	for _, key := range keys {
+		attempt, err := t.attemptRepo.IncrementFailures(key, now, resetBefore)
+		if err != nil {
+			return err
+		}
+
+		if until := policies[key].blockedUntil(attempt); !until.IsZero() {
+			if err := t.attemptRepo.SetBlockedUntil(key, until); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// RecordSuccess 登录成功后清除账号的失败记录（IP的记录保留，避免被用来重置计数）
+func (t *loginThrottle) RecordSuccess(username string) error {
+	return t.attemptRepo.Delete(accountThrottleKey(username))
+}
+
+// Unlock 解除账号锁定
+func (t *loginThrottle) Unlock(username string) error {
+	return t.attemptRepo.Delete(accountThrottleKey(username))
+}
+
+// PurgeExpired 清理过期的登录失败记录
+func (t *loginThrottle) PurgeExpired() error {
+	resetAfter := time.Minute * time.Duration(viper.GetInt("security.login_throttle.reset_after"))
+	return t.attemptRepo.DeleteExpired(time.Now().Add(-resetAfter))
+}
+
+// accountThrottlePolicy 账号维度的限流策略
+func accountThrottlePolicy() throttlePolicy {
+	return throttlePolicy{
+		freeAttempts: viper.GetInt("security.login_throttle.free_attempts"),
+		maxFailures:  viper.GetInt("security.login_throttle.account_max_failures"),
+		lockout:      time.Minute * time.Duration(viper.GetInt("security.login_throttle.account_lockout")),
+	}
+}
+
+// ipThrottlePolicy IP维度的限流策略
+func ipThrottlePolicy() throttlePolicy {
+	return throttlePolicy{
+		freeAttempts: viper.GetInt("security.login_throttle.ip_free_attempts"),
+		maxFailures:  viper.GetInt("security.login_throttle.ip_max_failures"),
+		lockout:      time.Minute * time.Duration(viper.GetInt("security.login_throttle.ip_lockout")),
+	}
+}
+
+// throttleKeys 返回账号和IP对应的记录键，账号键在前
+func throttleKeys(username, ip string) []string {
+	keys := []string{accountThrottleKey(username)}
+	if ip != "" {
+		keys = append(keys, "ip:"+ip)
+	}
+
+	return keys
+}
+
+// accountThrottleKey 账号对应的记录键（用户名不区分大小写）
+func accountThrottleKey(username string) string {
+	return "user:" + strings.ToLower(strings.TrimSpace(username))
+}
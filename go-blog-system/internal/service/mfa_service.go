@@ -0,0 +1,370 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/duanyu/go-blog-system/internal/repository"
+	"github.com/duanyu/go-blog-system/pkg/totp"
+	"github.com/spf13/viper"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// mfaChallengePurpose 两步验证质询令牌用途
+	mfaChallengePurpose = "mfa-challenge"
+	// recoveryCodeCount 每次生成的恢复码数量
+	recoveryCodeCount = 10
+)
+
+// ErrInvalidMFACode 验证码或恢复码无效
+var ErrInvalidMFACode = errors.New("invalid two-factor authentication code")
+
+// MFAService 两步验证服务接口
+type MFAService interface {
+	Status(userID int) (*model.MFAStatusResponse, error)
+	Setup(userID int) (*model.TOTPSetupResponse, error)
+	Confirm(userID int, code string) ([]string, error)
+	Disable(userID int, req *model.DisableTOTPRequest) error
+	RegenerateRecoveryCodes(userID int, code string) ([]string, error)
+	CompleteLogin(req *model.MFALoginRequest) (*model.LoginResponse, error)
+}
+
+// mfaService 两步验证服务实现
+type mfaService struct {
+	userRepo     repository.UserRepository
+	recoveryRepo repository.RecoveryCodeRepository
+	tokenService TokenService
+	throttle     LoginThrottle
+}
+
+// NewMFAService 创建两步验证服务
+func NewMFAService(
+	userRepo repository.UserRepository,
+	recoveryRepo repository.RecoveryCodeRepository,
+	tokenService TokenService,
+	throttle LoginThrottle,
+) MFAService {
+	return &mfaService{
+		userRepo:     userRepo,
+		recoveryRepo: recoveryRepo,
+		tokenService: tokenService,
+		throttle:     throttle,
+	}
+}
+
+// Status 获取两步验证状态
+func (s *mfaService) Status(userID int) (*model.MFAStatusResponse, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	remaining, err := s.recoveryRepo.CountUnused(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.MFAStatusResponse{
+		Enabled:                user.IsMFAEnabled(),
+		EnabledAt:              user.TOTPEnabledAt,
+		RecoveryCodesRemaining: remaining,
+	}, nil
+}
+
+// Setup 生成新的TOTP密钥，需调用Confirm确认后才生效
+func (s *mfaService) Setup(userID int) (*model.TOTPSetupResponse, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if user.IsMFAEnabled() {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate totp secret: %w", err)
+	}
+
+	if err := s.userRepo.SetTOTPSecret(userID, &secret); err != nil {
+		return nil, err
+	}
+
+	return &model.TOTPSetupResponse{
+		Secret: secret,
+		URI:    totp.URI(viper.GetString("app.name"), user.Username, secret),
+	}, nil
+}
+
+// Confirm 使用验证码确认绑定，开启两步验证并返回恢复码
+func (s *mfaService) Confirm(userID int, code string) ([]string, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if user.IsMFAEnabled() {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+	if user.TOTPSecret == nil {
+		return nil, errors.New("two-factor authentication setup has not been started")
+	}
+
+	step, ok := totp.Validate(*user.TOTPSecret, code, time.Now())
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	if err := s.userRepo.EnableTOTP(userID, step); err != nil {
+		return nil, err
+	}
+
+	return s.generateRecoveryCodes(userID)
+}
+
+// Disable 关闭两步验证（需要密码和验证码）
+func (s *mfaService) Disable(userID int, req *model.DisableTOTPRequest) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+		return errors.New("password is incorrect")
+	}
+
+	if err := s.verifyCode(user, req.Code); err != nil {
+		return err
+	}
+
+	if err := s.userRepo.DisableTOTP(userID); err != nil {
+		return err
+	}
+
+	return s.recoveryRepo.DeleteByUserID(userID)
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码，旧恢复码全部作废
+func (s *mfaService) RegenerateRecoveryCodes(userID int, code string) ([]string, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if !user.IsMFAEnabled() {
+		return nil, errors.New("two-factor authentication is not enabled")
+	}
+
+	if err := s.verifyCode(user, code); err != nil {
+		return nil, err
+	}
+
+	return s.generateRecoveryCodes(userID)
+}
+
+// CompleteLogin 使用质询令牌和验证码完成登录。质询令牌只能使用一次，验证失败需重新登录；
+// 失败次数与密码登录一起按账号和IP计入登录限流
+func (s *mfaService) CompleteLogin(req *model.MFALoginRequest) (*model.LoginResponse, error) {
+	payload, err := verifySignedToken(mfaChallengePurpose, req.ChallengeToken)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := strings.SplitN(payload, "|", 2)
+	if len(fields) != 2 {
+		return nil, ErrInvalidSignedToken
+	}
+
+	userID, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return nil, ErrInvalidSignedToken
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if err := s.throttle.Check(user.Username, req.Client.IP); err != nil {
+		return nil, err
+	}
+
+	unused, err := s.tokenService.UseOnce(fields[1], time.Now().Add(mfaChallengeExpiration()))
+	if err != nil {
+		return nil, err
+	}
+	if !unused {
+		return nil, ErrInvalidSignedToken
+	}
+
+	if err := s.verifyCode(user, req.Code); err != nil {
+		if errors.Is(err, ErrInvalidMFACode) {
+			if err := s.throttle.RecordFailure(user.Username, req.Client.IP); err != nil {
+				return nil, err
+			}
+		}
+		return nil, err
+	}
+
+	if err := s.throttle.RecordSuccess(user.Username); err != nil {
+		return nil, err
+	}
+
+	return completeLogin(s.tokenService, s.userRepo, user, true, req.Client)
+}
+
+// verifyCode 校验TOTP验证码或恢复码，均只能使用一次
+func (s *mfaService) verifyCode(user *model.User, code string) error {
+	if !user.IsMFAEnabled() || user.TOTPSecret == nil {
+		return errors.New("two-factor authentication is not enabled")
+	}
+
+	if step, ok := totp.Validate(*user.TOTPSecret, code, time.Now()); ok {
+		used, err := s.userRepo.UseTOTPStep(user.ID, step)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return ErrInvalidMFACode
+		}
+		return nil
+	}
+
+	consumed, err := s.recoveryRepo.Consume(user.ID, hashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return err
+	}
+	if !consumed {
+		return ErrInvalidMFACode
+	}
+
+	return nil
+}
+
+// generateRecoveryCodes 生成一组新的恢复码，仅保存哈希
+func (s *mfaService) generateRecoveryCodes(userID int) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+
+	for i := range codes {
+		raw, err := randomToken(5)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+
+		codes[i] = raw[:5] + "-" + raw[5:]
+		hashes[i] = hashToken(normalizeRecoveryCode(codes[i]))
+	}
+
+	if err := s.recoveryRepo.Replace(userID, hashes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// beginLogin 第一因素验证通过后，开启两步验证的用户只返回质询令牌
+func beginLogin(
+	tokenService TokenService,
+	userRepo repository.UserRepository,
+	user *model.User,
+	client model.ClientInfo,
+) (*model.LoginResponse, error) {
+	// 被管理员注销、已过宽限期或已匿名化的账号不能通过登录恢复
+	if !loginAllowed(user) {
+		return nil, ErrAccountDeleted
+	}
+
+	if user.IsMFAEnabled() {
+		// 质询令牌带有随机jti，使用后加入黑名单
+		jti, err := randomToken(16)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate challenge id: %w", err)
+		}
+
+		expiration := mfaChallengeExpiration()
+
+		return &model.LoginResponse{
+			MFARequired:    true,
+			ChallengeToken: signToken(mfaChallengePurpose, fmt.Sprintf("%d|%s", user.ID, jti), expiration),
+			ExpiresIn:      int64(expiration.Seconds()),
+		}, nil
+	}
+
+	return completeLogin(tokenService, userRepo, user, false, client)
+}
+
+// completeLogin 所有认证因素通过后签发令牌，宽限期内本人注销的账号随之恢复
+func completeLogin(
+	tokenService TokenService,
+	userRepo repository.UserRepository,
+	user *model.User,
+	mfa bool,
+	client model.ClientInfo,
+) (*model.LoginResponse, error) {
+	restored := false
+	if user.IsDeleted() {
+		if !loginAllowed(user) {
+			return nil, ErrAccountDeleted
+		}
+		// 宽限期可能在登录过程中结束，或账号已被匿名化任务处理
+		ok, err := userRepo.Restore(user.ID, time.Now().Add(-deletionGracePeriod()))
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, ErrAccountDeleted
+		}
+		user.DeletedAt = nil
+		restored = true
+	}
+
+	tokens, err := tokenService.Issue(user, mfa, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue tokens: %w", err)
+	}
+
+	resp := loginResponse(tokens, user)
+	resp.AccountRestored = restored
+	return resp, nil
+}
+
+// loginAllowed 账号未注销，或由本人注销且仍在宽限期内
+func loginAllowed(user *model.User) bool {
+	if user.IsAnonymized() {
+		return false
+	}
+	if !user.IsDeleted() {
+		return true
+	}
+	return user.IsSelfDeleted() && time.Since(*user.DeletedAt) <= deletionGracePeriod()
+}
+
+// mfaChallengeExpiration 两步验证质询令牌的有效期
+func mfaChallengeExpiration() time.Duration {
+	return time.Minute * time.Duration(viper.GetInt("security.mfa_challenge_expiration"))
+}
+
+// loginResponse 构建登录成功响应
+func loginResponse(tokens *model.TokenPair, user *model.User) *model.LoginResponse {
+	return &model.LoginResponse{
+		AccessToken:  tokens.AccessToken,
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		TokenType:    tokens.TokenType,
+		ExpiresIn:    tokens.ExpiresIn,
+		User:         userResponse(user),
+	}
+}
+
+// normalizeRecoveryCode 统一恢复码格式（忽略大小写、空格和连字符）
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/spf13/viper"
+)
+
+// defaultMaxPerPage 未配置时每页数量的上限
+const defaultMaxPerPage = 100
+
+// maxPerPage 每页数量上限，超出时按上限返回
+func maxPerPage() int {
+	if max := viper.GetInt("pagination.max_per_page"); max > 0 {
+		return max
+	}
+
+	return defaultMaxPerPage
+}
+
+// normalizePage 设置分页参数的默认值和上限
+func normalizePage(query *model.PageQuery, defaultPerPage int) {
+	query.Normalize(defaultPerPage, maxPerPage())
+}
+
+// fillTotal 需要时查询总数并写入分页结果
+func fillTotal[T any](page *model.Page[T], query *model.PageQuery, count func() (int, error)) error {
+	if !query.WantTotal() {
+		return nil
+	}
+
+	total, err := count()
+	if err != nil {
+		return err
+	}
+	page.Total = &total
+
+	return nil
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/duanyu/go-blog-system/internal/repository"
+	"github.com/spf13/viper"
+)
+
+const (
+	// personalTokenPrefixLength 保存用于展示的令牌前缀长度
+	personalTokenPrefixLength = 13
+	// personalTokenTouchInterval 最近使用时间的记录间隔
+	personalTokenTouchInterval = time.Minute
+)
+
+var (
+	// ErrInvalidScope 授权范围不合法
+	ErrInvalidScope = errors.New("invalid scope")
+	// ErrPersonalTokenNotFound 个人访问令牌不存在
+	ErrPersonalTokenNotFound = errors.New("personal access token not found")
+	// ErrPersonalTokenExpired 个人访问令牌已过期
+	ErrPersonalTokenExpired = errors.New("personal access token has expired")
+)
+
+// PersonalAccessTokenService 个人访问令牌服务接口
+type PersonalAccessTokenService interface {
+	Create(userID int, req *model.CreatePersonalAccessTokenRequest) (*model.CreatedPersonalAccessTokenResponse, error)
+	List(userID int) ([]*model.PersonalAccessTokenResponse, error)
+	Revoke(userID, id int) error
+	Authenticate(rawToken, ip string) (*AccessClaims, error)
+}
+
+// personalAccessTokenService 个人访问令牌服务实现
+type personalAccessTokenService struct {
+	tokenRepo repository.PersonalAccessTokenRepository
+	userRepo  repository.UserRepository
+}
+
+// NewPersonalAccessTokenService 创建个人访问令牌服务
+func NewPersonalAccessTokenService(
+	tokenRepo repository.PersonalAccessTokenRepository,
+	userRepo repository.UserRepository,
+) PersonalAccessTokenService {
+	return &personalAccessTokenService{
+		tokenRepo: tokenRepo,
+		userRepo:  userRepo,
+	}
+}
+
+// Create 创建个人访问令牌
+func (s *personalAccessTokenService) Create(userID int, req *model.CreatePersonalAccessTokenRequest) (*model.CreatedPersonalAccessTokenResponse, error) {
+	scopes, err := normalizeScopes(req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	days := req.ExpiresInDays
+	if days == 0 {
+		days = viper.GetInt("security.personal_token_default_expiration")
+	}
+	if days <= 0 {
+		days = 30
+	}
+	if maxDays := viper.GetInt("security.personal_token_max_expiration"); maxDays > 0 && days > maxDays {
+		return nil, fmt.Errorf("expires_in_days must not exceed %d", maxDays)
+	}
+
+	secret, err := randomToken(32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate personal access token: %w", err)
+	}
+	rawToken := model.PersonalAccessTokenPrefix + secret
+
+	token := &model.PersonalAccessToken{
+		UserID:      userID,
+		Name:        req.Name,
+		TokenPrefix: rawToken[:personalTokenPrefixLength],
+		TokenHash:   hashToken(rawToken),
+		Scopes:      joinScopes(scopes),
+		ExpiresAt:   time.Now().AddDate(0, 0, days),
+	}
+
+	if err := s.tokenRepo.Create(token); err != nil {
+		return nil, err
+	}
+
+	// CreatedAt由数据库生成，这里使用当前时间填充响应
+	token.CreatedAt = time.Now()
+
+	return &model.CreatedPersonalAccessTokenResponse{
+		PersonalAccessTokenResponse: *personalTokenResponse(token),
+		Token:                       rawToken,
+	}, nil
+}
+
+// List 获取用户的个人访问令牌列表
+func (s *personalAccessTokenService) List(userID int) ([]*model.PersonalAccessTokenResponse, error) {
+	tokens, err := s.tokenRepo.ListByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*model.PersonalAccessTokenResponse, 0, len(tokens))
+	for i := range tokens {
+		responses = append(responses, personalTokenResponse(&tokens[i]))
+	}
+
+	return responses, nil
+}
+
+// Revoke 吊销个人访问令牌
+func (s *personalAccessTokenService) Revoke(userID, id int) error {
+	deleted, err := s.tokenRepo.Delete(userID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrPersonalTokenNotFound
+	}
+
+	return nil
+}
+
+// Authenticate 校验个人访问令牌并记录使用情况
+func (s *personalAccessTokenService) Authenticate(rawToken, ip string) (*AccessClaims, error) {
+	token, err := s.tokenRepo.GetByHash(hashToken(rawToken))
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if time.Now().After(token.ExpiresAt) {
+		return nil, ErrPersonalTokenExpired
+	}
+
+	// 每次请求重新读取用户，使角色变更立即生效
+	user, err := s.userRepo.GetByID(token.UserID)
+	if err != nil || user.IsDeleted() {
+		return nil, ErrInvalidToken
+	}
+
+	if err := s.tokenRepo.TouchLastUsed(token.ID, ip, time.Now(), personalTokenTouchInterval); err != nil {
+		return nil, err
+	}
+
+	return &AccessClaims{
+		UserID:          user.ID,
+		Role:            user.Role,
+		ExpiresAt:       token.ExpiresAt,
+		PersonalTokenID: token.ID,
+		Scopes:          token.ScopeList(),
+	}, nil
+}
+
+// normalizeScopes 校验并去重授权范围
+func normalizeScopes(scopes []model.Scope) ([]model.Scope, error) {
+	seen := make(map[model.Scope]bool, len(scopes))
+	result := make([]model.Scope, 0, len(scopes))
+
+	for _, scope := range scopes {
+		if !scope.IsValid() {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidScope, scope)
+		}
+		if seen[scope] {
+			continue
+		}
+		seen[scope] = true
+		result = append(result, scope)
+	}
+
+	return result, nil
+}
+
+// joinScopes 将授权范围拼接为逗号分隔的字符串
+func joinScopes(scopes []model.Scope) string {
+	parts := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		parts = append(parts, string(scope))
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// personalTokenResponse 构建个人访问令牌响应
+func personalTokenResponse(token *model.PersonalAccessToken) *model.PersonalAccessTokenResponse {
+	return &model.PersonalAccessTokenResponse{
+		ID:          token.ID,
+		Name:        token.Name,
+		TokenPrefix: token.TokenPrefix,
+		Scopes:      token.ScopeList(),
+		ExpiresAt:   token.ExpiresAt,
+		LastUsedAt:  token.LastUsedAt,
+		LastUsedIP:  token.LastUsedIP,
+		CreatedAt:   token.CreatedAt,
+	}
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/duanyu/go-blog-system/internal/repository"
+	"github.com/duanyu/go-blog-system/pkg/diff"
+)
+
+var (
+	// ErrPostNotFound 文章不存在
+	ErrPostNotFound = errors.New("post not found")
+	// ErrRevisionNotFound 修订版本不存在
+	ErrRevisionNotFound = errors.New("revision not found")
+	// ErrPostHistoryAccessDenied 无权查看文章的修订和审核历史
+	ErrPostHistoryAccessDenied = errors.New("you don't have permission to access this post's history")
+)
+
+// PostRevisionService 文章修订版本服务接口
+type PostRevisionService interface {
+	List(postID int, actor model.Actor) ([]model.PostRevisionResponse, error)
+	Get(postID, revision int, actor model.Actor) (*model.PostRevisionResponse, error)
+	Diff(postID int, actor model.Actor, query *model.RevisionDiffQuery) (*model.RevisionDiffResponse, error)
+	Restore(postID, revision int, actor model.Actor) (*model.PostResponse, error)
+}
+
+// postRevisionService 文章修订版本服务实现
+type postRevisionService struct {
+	revisionRepo repository.PostRevisionRepository
+	postRepo     repository.PostRepository
+	userRepo     repository.UserRepository
+	postService  PostService
+}
+
+// NewPostRevisionService 创建文章修订版本服务
+func NewPostRevisionService(
+	revisionRepo repository.PostRevisionRepository,
+	postRepo repository.PostRepository,
+	userRepo repository.UserRepository,
+	postService PostService,
+) PostRevisionService {
+	return &postRevisionService{
+		revisionRepo: revisionRepo,
+		postRepo:     postRepo,
+		userRepo:     userRepo,
+		postService:  postService,
+	}
+}
+
+// List 获取文章的修订历史（不含正文）
+func (s *postRevisionService) List(postID int, actor model.Actor) ([]model.PostRevisionResponse, error) {
+	if err := s.checkAccess(postID, actor); err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.revisionRepo.ListByPostID(postID)
+	if err != nil {
+		return nil, err
+	}
+
+	editors, err := s.loadEditors(revisions)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]model.PostRevisionResponse, len(revisions))
+	for i := range revisions {
+		responses[i] = revisionResponse(&revisions[i], editors)
+		responses[i].Content = ""
+	}
+
+	return responses, nil
+}
+
+// Get 获取指定修订版本
+func (s *postRevisionService) Get(postID, revision int, actor model.Actor) (*model.PostRevisionResponse, error) {
+	if err := s.checkAccess(postID, actor); err != nil {
+		return nil, err
+	}
+
+	rev, err := s.revisionRepo.Get(postID, revision)
+	if err != nil {
+		return nil, ErrRevisionNotFound
+	}
+
+	editors, err := s.loadEditors([]model.PostRevision{*rev})
+	if err != nil {
+		return nil, err
+	}
+
+	response := revisionResponse(rev, editors)
+	return &response, nil
+}
+
+// Diff 比较两个修订版本的正文
+func (s *postRevisionService) Diff(postID int, actor model.Actor, query *model.RevisionDiffQuery) (*model.RevisionDiffResponse, error) {
+	if err := s.checkAccess(postID, actor); err != nil {
+		return nil, err
+	}
+
+	from, err := s.revisionRepo.Get(postID, query.From)
+	if err != nil {
+		return nil, ErrRevisionNotFound
+	}
+
+	to, err := s.revisionRepo.Get(postID, query.To)
+	if err != nil {
+		return nil, ErrRevisionNotFound
+	}
+
+	response := &model.RevisionDiffResponse{
+		From:      from.Revision,
+		To:        to.Revision,
+		Mode:      query.Mode,
+		TitleFrom: from.Title,
+		TitleTo:   to.Title,
+	}
+
+	if query.Mode == model.DiffModeWords {
+		segments := diff.Words(from.Content, to.Content)
+		response.Segments = make([]model.DiffSegment, len(segments))
+		for i, seg := range segments {
+			response.Segments[i] = model.DiffSegment{Op: string(seg.Op), Text: seg.Text}
+		}
+	} else {
+		response.Unified = diff.Unified(
+			from.Content,
+			to.Content,
+			fmt.Sprintf("revision %d", from.Revision),
+			fmt.Sprintf("revision %d", to.Revision),
+		)
+	}
+
+	return response, nil
+}
+
+// Restore 将文章的标题、正文和内容格式恢复为指定修订版本（发布状态不变），恢复本身记录为新的修订版本
+func (s *postRevisionService) Restore(postID, revision int, actor model.Actor) (*model.PostResponse, error) {
+	if err := s.checkAccess(postID, actor); err != nil {
+		return nil, err
+	}
+
+	rev, err := s.revisionRepo.Get(postID, revision)
+	if err != nil {
+		return nil, ErrRevisionNotFound
+	}
+
+	return s.postService.Update(postID, actor, &model.UpdatePostRequest{
+		Title:         &rev.Title,
+		Content:       &rev.Content,
+		ContentFormat: &rev.ContentFormat,
+	})
+}
+
+// checkAccess 只有可以编辑文章的用户才能查看和恢复修订版本
+func (s *postRevisionService) checkAccess(postID int, actor model.Actor) error {
+	return checkPostHistoryAccess(s.postRepo, postID, actor)
+}
+
+// loadEditors 批量加载修订版本的修改人
+func (s *postRevisionService) loadEditors(revisions []model.PostRevision) (map[int]*model.PublicUserResponse, error) {
+	ids := make([]int, 0, len(revisions))
+	for _, rev := range revisions {
+		if rev.UserID != nil {
+			ids = append(ids, *rev.UserID)
+		}
+	}
+
+	return loadPublicUsers(s.userRepo, ids)
+}
+
+// checkPostHistoryAccess 文章的修订和审核历史只对可以编辑该文章的用户开放
+func checkPostHistoryAccess(postRepo repository.PostRepository, postID int, actor model.Actor) error {
+	post, err := postRepo.GetByID(postID)
+	if err != nil {
+		return ErrPostNotFound
+	}
+
+	if !actor.CanModify(post.UserID, model.PermissionEditAnyPost) {
+		return ErrPostHistoryAccessDenied
+	}
+
+	return nil
+}
+
+// loadPublicUsers 批量加载用户的公开信息（重复的ID只查询一次）
+func loadPublicUsers(userRepo repository.UserRepository, ids []int) (map[int]*model.PublicUserResponse, error) {
+	unique := make([]int, 0, len(ids))
+	seen := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	users, err := userRepo.GetByIDs(unique)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[int]*model.PublicUserResponse, len(users))
+	for i := range users {
+		result[users[i].ID] = users[i].ToPublicResponse()
+	}
+
+	return result, nil
+}
+
+// revisionResponse 构建修订版本响应
+func revisionResponse(rev *model.PostRevision, editors map[int]*model.PublicUserResponse) model.PostRevisionResponse {
+	response := model.PostRevisionResponse{
+		Revision:      rev.Revision,
+		Title:         rev.Title,
+		Content:       rev.Content,
+		ContentFormat: rev.ContentFormat,
+		Status:        rev.Status,
+		CreatedAt:     rev.CreatedAt,
+	}
+	if rev.UserID != nil {
+		response.Editor = editors[*rev.UserID]
+	}
+
+	return response
+}
@@ -1,28 +1,64 @@
 package service
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/duanyu/go-blog-system/internal/model"
 	"github.com/duanyu/go-blog-system/internal/repository"
+	"github.com/duanyu/go-blog-system/pkg/filter"
+	"github.com/duanyu/go-blog-system/pkg/markdown"
+	"github.com/duanyu/go-blog-system/pkg/slug"
+	"github.com/spf13/viper"
+)
+
+var (
+	// ErrInvalidSlug slug中没有可用字符
+	ErrInvalidSlug = errors.New("slug must contain letters or digits")
+	// ErrSlugTaken slug已被其他文章使用
+	ErrSlugTaken = errors.New("slug is already in use")
+	// ErrInvalidPublishTime 发布时间与状态不符
+	ErrInvalidPublishTime = errors.New("scheduled posts need a future published_at, published posts cannot have a future published_at")
+	// ErrPostStatusNotAllowed 只有作者本人和编辑可以按未发布的状态查询文章
+	ErrPostStatusNotAllowed = errors.New("only the author or an editor can list unpublished posts")
+)
+
+const (
+	// defaultSlug 标题无法生成slug时使用的默认值
+	defaultSlug = "post"
+	// slugBackfillBatchSize 每批回填slug的文章数
+	slugBackfillBatchSize = 100
+	// renderBatchSize 每批重新渲染的文章数
+	renderBatchSize = 100
+	// publishBatchSize 每批发布的定时文章数
+	publishBatchSize = 100
 )
 
 // PostService 文章服务接口
 type PostService interface {
-	Create(userID int, req *model.CreatePostRequest) (*model.PostResponse, error)
-	GetByID(id int) (*model.PostResponse, error)
-	Update(id, userID int, req *model.UpdatePostRequest) (*model.PostResponse, error)
-	Delete(id, userID int) error
-	List(query *model.PostQuery) ([]model.PostResponse, int, error)
+	Create(actor model.Actor, req *model.CreatePostRequest) (*model.PostResponse, error)
+	GetByID(id int, actor model.Actor) (*model.PostResponse, error)
+	GetBySlug(slug string, actor model.Actor) (*model.PostResponse, error)
+	Update(id int, actor model.Actor, req *model.UpdatePostRequest) (*model.PostResponse, error)
+	Delete(id int, actor model.Actor) error
+	List(query *model.PostQuery, actor model.Actor) (*model.Page[model.PostResponse], error)
 	IncrementViewCount(id int) error
+	BackfillSlugs() (int, error)
+	RenderStale() (int, error)
+	PublishDue() (int, error)
 }
 
 // postService 文章服务实现
 type postService struct {
-	postRepo repository.PostRepository
-	userRepo repository.UserRepository
-	tagRepo  repository.TagRepository
+	postRepo     repository.PostRepository
+	userRepo     repository.UserRepository
+	tagRepo      repository.TagRepository
+	revisionRepo repository.PostRevisionRepository
+	seriesRepo   repository.SeriesRepository
+	reactionRepo repository.ReactionRepository
+	bookmarkRepo repository.BookmarkRepository
 }
 
 // NewPostService 创建文章服务
@@ -30,44 +66,85 @@ func NewPostService(
 	postRepo repository.PostRepository,
 	userRepo repository.UserRepository,
 	tagRepo repository.TagRepository,
+	revisionRepo repository.PostRevisionRepository,
+	seriesRepo repository.SeriesRepository,
+	reactionRepo repository.ReactionRepository,
+	bookmarkRepo repository.BookmarkRepository,
 ) PostService {
 	return &postService{
-		postRepo: postRepo,
-		userRepo: userRepo,
-		tagRepo:  tagRepo,
+		postRepo:     postRepo,
+		userRepo:     userRepo,
+		tagRepo:      tagRepo,
+		revisionRepo: revisionRepo,
+		seriesRepo:   seriesRepo,
+		reactionRepo: reactionRepo,
+		bookmarkRepo: bookmarkRepo,
 	}
 }
 
 // Create 创建文章
-func (s *postService) Create(userID int, req *model.CreatePostRequest) (*model.PostResponse, error) {
+func (s *postService) Create(actor model.Actor, req *model.CreatePostRequest) (*model.PostResponse, error) {
+	userID := actor.UserID
+
 	// 检查用户是否存在
 	user, err := s.userRepo.GetByID(userID)
 	if err != nil {
 		return nil, fmt.Errorf("user not found: %w", err)
 	}
 
+	if err := checkEmailVerified(user); err != nil {
+		return nil, err
+	}
+
 	// 创建文章
 	status := req.Status
 	if status == "" {
 		status = model.PostStatusDraft
 	}
 
+	// 新文章视为从草稿开始
+	if err := checkTransition(actor, model.PostStatusDraft, status); err != nil {
+		return nil, err
+	}
+
+	postSlug, err := s.resolveSlug(0, req.Slug, req.Title)
+	if err != nil {
+		return nil, err
+	}
+
+	format := req.ContentFormat
+	if format == "" {
+		format = model.ContentFormatMarkdown
+	}
+
 	post := &model.Post{
-		Title:   req.Title,
-		Content: req.Content,
-		UserID:  userID,
-		Status:  status,
+		Title:         req.Title,
+		Slug:          &postSlug,
+		Content:       req.Content,
+		ContentFormat: format,
+		UserID:        userID,
+		Status:        status,
+	}
+	if req.Summary != "" {
+		post.Summary = &req.Summary
 	}
 
-	if err := s.postRepo.Create(post); err != nil {
-		return nil, fmt.Errorf("failed to create post: %w", err)
+	if err := setPublishedAt(post, "", req.PublishedAt, time.Now()); err != nil {
+		return nil, err
 	}
 
-	// 添加标签
-	if len(req.TagIDs) > 0 {
-		if err := s.postRepo.AddTags(post.ID, req.TagIDs); err != nil {
-			return nil, fmt.Errorf("failed to add tags: %w", err)
-		}
+	if err := renderContent(post); err != nil {
+		return nil, err
+	}
+
+	// 文章、第一个修订版本、初始状态记录和标签在同一事务中写入
+	edit := &model.PostEdit{
+		Revision:   newRevision(post, userID),
+		Transition: &model.PostTransition{ToStatus: post.Status, UserID: &userID},
+		TagIDs:     req.TagIDs,
+	}
+	if err := s.postRepo.Create(post, edit); err != nil {
+		return nil, fmt.Errorf("failed to create post: %w", err)
 	}
 
 	// 获取标签
@@ -77,36 +154,43 @@ func (s *postService) Create(userID int, req *model.CreatePostRequest) (*model.P
 	}
 
 	// 构建响应
-	response := &model.PostResponse{
-		ID:        post.ID,
-		Title:     post.Title,
-		Content:   post.Content,
-		Status:    post.Status,
-		ViewCount: post.ViewCount,
-		CreatedAt: post.CreatedAt,
-		UpdatedAt: post.UpdatedAt,
-		User: &model.UserResponse{
-			ID:        user.ID,
-			Username:  user.Username,
-			Email:     user.Email,
-			Avatar:    user.Avatar,
-			Bio:       user.Bio,
-			CreatedAt: user.CreatedAt,
-		},
-		Tags: tags,
-	}
-
-	return response, nil
+	response := post.ToResponse()
+	response.User = user.ToPublicResponse()
+	response.Tags = tags
+
+	return &response, nil
 }
 
-// GetByID 根据ID获取文章
-func (s *postService) GetByID(id int) (*model.PostResponse, error) {
-	// 获取文章
-	post, err := s.postRepo.GetByID(id)
+// GetByID 根据ID获取文章，未发布的文章只有作者本人和编辑可以查看
+func (s *postService) GetByID(id int, actor model.Actor) (*model.PostResponse, error) {
+	post, err := visiblePost(s.postRepo, id, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.buildResponse(post, actor.UserID)
+}
+
+// GetBySlug 根据slug获取文章，旧slug同样可以找到文章（响应中为当前slug）。
+// 未发布的文章只有作者本人和编辑可以查看
+func (s *postService) GetBySlug(postSlug string, actor model.Actor) (*model.PostResponse, error) {
+	post, err := s.postRepo.GetBySlug(postSlug)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get post: %w", err)
+		post, err = s.postRepo.GetByRedirectSlug(postSlug)
+		if err != nil {
+			return nil, ErrPostNotFound
+		}
 	}
 
+	if post.Status != model.PostStatusPublished && !actor.CanModify(post.UserID, model.PermissionEditAnyPost) {
+		return nil, ErrPostNotFound
+	}
+
+	return s.buildResponse(post, actor.UserID)
+}
+
+// buildResponse 加载作者、标签、系列导航和当前用户的回应与收藏状态，构建文章响应
+func (s *postService) buildResponse(post *model.Post, viewerID int) (*model.PostResponse, error) {
 	// 获取作者
 	user, err := s.userRepo.GetByID(post.UserID)
 	if err != nil {
@@ -119,120 +203,153 @@ func (s *postService) GetByID(id int) (*model.PostResponse, error) {
 		return nil, fmt.Errorf("failed to get post tags: %w", err)
 	}
 
-	// 构建响应
-	response := &model.PostResponse{
-		ID:        post.ID,
-		Title:     post.Title,
-		Content:   post.Content,
-		Status:    post.Status,
-		ViewCount: post.ViewCount,
-		CreatedAt: post.CreatedAt,
-		UpdatedAt: post.UpdatedAt,
-		User: &model.UserResponse{
-			ID:        user.ID,
-			Username:  user.Username,
-			Email:     user.Email,
-			Avatar:    user.Avatar,
-			Bio:       user.Bio,
-			CreatedAt: user.CreatedAt,
-		},
-		Tags: tags,
-	}
-
-	return response, nil
-}
-
-// Update 更新文章
-func (s *postService) Update(id, userID int, req *model.UpdatePostRequest) (*model.PostResponse, error) {
-	// 获取文章
-	post, err := s.postRepo.GetByID(id)
+	// 获取系列导航
+	series, err := seriesNavigation(s.seriesRepo, post)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get post: %w", err)
+		return nil, err
 	}
 
-	// 检查权限
-	if post.UserID != userID {
-		return nil, errors.New("you don't have permission to update this post")
+	// 构建响应
+	response := post.ToResponse()
+	response.User = user.ToPublicResponse()
+	response.Tags = tags
+	response.Series = series
+
+	responses := []model.PostResponse{response}
+	if err := applyViewerState(s.reactionRepo, s.bookmarkRepo, viewerID, responses); err != nil {
+		return nil, err
 	}
 
-	// 更新字段
-	if req.Title != nil {
-		post.Title = *req.Title
-	}
+	return &responses[0], nil
+}
 
-	if req.Content != nil {
-		post.Content = *req.Content
-	}
+// Update 更新文章。文章在事务中加锁后再计算修改，文章、修订版本、状态变更、slug和标签一起提交
+func (s *postService) Update(id int, actor model.Actor, req *model.UpdatePostRequest) (*model.PostResponse, error) {
+	var post *model.Post
+	var newSlug string
+	err := s.postRepo.Edit(id, func(locked *model.Post) (*model.PostEdit, error) {
+		post = locked
 
-	if req.Status != nil {
-		post.Status = *req.Status
-	}
+		// 检查权限（作者本人或编辑）
+		if !actor.CanModify(post.UserID, model.PermissionEditAnyPost) {
+			return nil, errors.New("you don't have permission to update this post")
+		}
 
-	// 更新文章
-	if err := s.postRepo.Update(post); err != nil {
-		return nil, fmt.Errorf("failed to update post: %w", err)
-	}
+		// 迁移前创建的文章没有修订记录，保存修改前的版本
+		edit := &model.PostEdit{
+			Baseline: newRevision(post, post.UserID),
+			TagIDs:   req.TagIDs,
+		}
 
-	// 更新标签
-	if req.TagIDs != nil {
-		// 先删除所有标签
-		if err := s.postRepo.RemoveTags(post.ID); err != nil {
-			return nil, fmt.Errorf("failed to remove tags: %w", err)
+		// 计算目标状态：已批准的文章修改内容后需要重新审核
+		contentChanged := (req.Title != nil && *req.Title != post.Title) ||
+			(req.Content != nil && *req.Content != post.Content) ||
+			(req.ContentFormat != nil && *req.ContentFormat != post.ContentFormat)
+
+		prevStatus, fromStatus, targetStatus := post.Status, post.Status, post.Status
+		var transitionComment *string
+		if contentChanged && requiresReapproval(actor, post.Status) {
+			fromStatus, targetStatus = model.PostStatusInReview, model.PostStatusInReview
+			comment := reapprovalComment
+			transitionComment = &comment
+		}
+		if req.Status != nil {
+			targetStatus = *req.Status
 		}
 
-		// 添加新标签
-		if len(req.TagIDs) > 0 {
-			if err := s.postRepo.AddTags(post.ID, req.TagIDs); err != nil {
-				return nil, fmt.Errorf("failed to add tags: %w", err)
+		if err := checkTransition(actor, fromStatus, targetStatus); err != nil {
+			return nil, err
+		}
+
+		// 指定了slug或修改了标题时更新slug
+		var err error
+		if req.Slug != nil && *req.Slug != "" {
+			newSlug, err = s.resolveSlug(post.ID, *req.Slug, "")
+		} else if req.Title != nil && (*req.Title != post.Title || post.Slug == nil) {
+			newSlug, err = s.resolveSlug(post.ID, "", *req.Title)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if newSlug != "" && (post.Slug == nil || *post.Slug != newSlug) {
+			edit.NewSlug = newSlug
+		}
+
+		// 更新字段
+		if req.Title != nil {
+			post.Title = *req.Title
+		}
+
+		if req.Summary != nil {
+			post.Summary = nil
+			if *req.Summary != "" {
+				post.Summary = req.Summary
 			}
 		}
-	}
 
-	// 获取标签
-	tags, err := s.postRepo.GetPostTags(post.ID)
+		// 内容、格式或渲染规则变化时重新渲染
+		rerender := post.RenderVersion < markdown.Version
+		if req.Content != nil && *req.Content != post.Content {
+			post.Content = *req.Content
+			rerender = true
+		}
+
+		if req.ContentFormat != nil && *req.ContentFormat != post.ContentFormat {
+			post.ContentFormat = *req.ContentFormat
+			rerender = true
+		}
+
+		if rerender {
+			if err := renderContent(post); err != nil {
+				return nil, err
+			}
+		}
+
+		post.Status = targetStatus
+
+		if err := setPublishedAt(post, prevStatus, req.PublishedAt, time.Now()); err != nil {
+			return nil, err
+		}
+
+		edit.Revision = newRevision(post, actor.UserID)
+		if post.Status != prevStatus {
+			edit.Transition = &model.PostTransition{
+				PostID:     post.ID,
+				FromStatus: &prevStatus,
+				ToStatus:   post.Status,
+				UserID:     &actor.UserID,
+				Comment:    transitionComment,
+			}
+		}
+
+		return edit, nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get post tags: %w", err)
+		return nil, err
 	}
 
-	// 获取作者
-	user, err := s.userRepo.GetByID(post.UserID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get user: %w", err)
+	// 旧slug保留为重定向
+	if newSlug != "" {
+		post.Slug = &newSlug
 	}
 
-	// 构建响应
-	response := &model.PostResponse{
-		ID:        post.ID,
-		Title:     post.Title,
-		Content:   post.Content,
-		Status:    post.Status,
-		ViewCount: post.ViewCount,
-		CreatedAt: post.CreatedAt,
-		UpdatedAt: post.UpdatedAt,
-		User: &model.UserResponse{
-			ID:        user.ID,
-			Username:  user.Username,
-			Email:     user.Email,
-			Avatar:    user.Avatar,
-			Bio:       user.Bio,
-			CreatedAt: user.CreatedAt,
-		},
-		Tags: tags,
-	}
-
-	return response, nil
+	if err := s.pruneRevisions(post.ID); err != nil {
+		return nil, err
+	}
+
+	return s.buildResponse(post, actor.UserID)
 }
 
 // Delete 删除文章
-func (s *postService) Delete(id, userID int) error {
+func (s *postService) Delete(id int, actor model.Actor) error {
 	// 获取文章
 	post, err := s.postRepo.GetByID(id)
 	if err != nil {
 		return fmt.Errorf("failed to get post: %w", err)
 	}
 
-	// 检查权限
-	if post.UserID != userID {
+	// 检查权限（作者本人或管理员）
+	if !actor.CanModify(post.UserID, model.PermissionDeleteAnyPost) {
 		return errors.New("you don't have permission to delete this post")
 	}
 
@@ -240,59 +357,309 @@ func (s *postService) Delete(id, userID int) error {
 	return s.postRepo.Delete(id)
 }
 
-// List 获取文章列表
-func (s *postService) List(query *model.PostQuery) ([]model.PostResponse, int, error) {
-	// 获取文章列表
-	posts, err := s.postRepo.List(query)
+// List 分页获取文章列表，默认只列出已发布的文章
+func (s *postService) List(query *model.PostQuery, actor model.Actor) (*model.Page[model.PostResponse], error) {
+	normalizePage(&query.PageQuery, 10)
+
+	if err := scopePostQuery(query, actor); err != nil {
+		return nil, err
+	}
+
+	page, err := s.postRepo.List(query)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to list posts: %w", err)
+		return nil, err
 	}
 
-	// 获取文章总数
-	count, err := s.postRepo.Count(query)
+	err = fillTotal(page, &query.PageQuery, func() (int, error) { return s.postRepo.Count(query) })
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count posts: %w", err)
+		return nil, fmt.Errorf("failed to count posts: %w", err)
 	}
 
-	// 构建响应
-	responses := make([]model.PostResponse, len(posts))
-	for i, post := range posts {
-		// 获取作者
-		user, err := s.userRepo.GetByID(post.UserID)
-		if err != nil {
-			return nil, 0, fmt.Errorf("failed to get user: %w", err)
+	responses, err := buildPostResponses(s.postRepo, s.userRepo, page.Items, query.WantsContent())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyViewerState(s.reactionRepo, s.bookmarkRepo, actor.UserID, responses); err != nil {
+		return nil, err
+	}
+
+	return model.MapPage(page, responses), nil
+}
+
+// scopePostQuery 限制文章查询的状态：status参数和筛选表达式都没有指定状态时只查询已发布的文章；
+// 查询其他状态需要是编辑，或者通过user_id只查询自己的文章
+func scopePostQuery(query *model.PostQuery, actor model.Actor) error {
+	statusFilter := false
+	if query.Filter != "" {
+		// 语法错误留给仓库层统一报告
+		if expr, err := filter.Parse(query.Filter); err == nil {
+			for _, field := range filter.Fields(expr) {
+				statusFilter = statusFilter || field == "status"
+			}
 		}
+	}
 
-		// 获取标签
-		tags, err := s.postRepo.GetPostTags(post.ID)
-		if err != nil {
-			return nil, 0, fmt.Errorf("failed to get post tags: %w", err)
-		}
-
-		responses[i] = model.PostResponse{
-			ID:        post.ID,
-			Title:     post.Title,
-			Content:   post.Content,
-			Status:    post.Status,
-			ViewCount: post.ViewCount,
-			CreatedAt: post.CreatedAt,
-			UpdatedAt: post.UpdatedAt,
-			User: &model.UserResponse{
-				ID:        user.ID,
-				Username:  user.Username,
-				Email:     user.Email,
-				Avatar:    user.Avatar,
-				Bio:       user.Bio,
-				CreatedAt: user.CreatedAt,
-			},
-			Tags: tags,
-		}
-	}
-
-	return responses, count, nil
+	if query.Status == "" && !statusFilter {
+		query.Status = model.PostStatusPublished
+		return nil
+	}
+	if query.Status == model.PostStatusPublished && !statusFilter {
+		return nil
+	}
+
+	if actor.Role.Can(model.PermissionEditAnyPost) {
+		return nil
+	}
+	if actor.UserID != 0 && query.UserID != nil && *query.UserID == actor.UserID {
+		return nil
+	}
+
+	return ErrPostStatusNotAllowed
 }
 
 // IncrementViewCount 增加文章浏览量
 func (s *postService) IncrementViewCount(id int) error {
 	return s.postRepo.IncrementViewCount(id)
-}
\ No newline at end of file
+}
+
+// BackfillSlugs 为尚未生成slug的文章（迁移前创建的文章）根据标题生成slug
+func (s *postService) BackfillSlugs() (int, error) {
+	total := 0
+	for {
+		posts, err := s.postRepo.ListWithoutSlug(slugBackfillBatchSize)
+		if err != nil {
+			return total, err
+		}
+		if len(posts) == 0 {
+			return total, nil
+		}
+
+		for _, post := range posts {
+			postSlug, err := s.resolveSlug(post.ID, "", post.Title)
+			if err != nil {
+				return total, err
+			}
+
+			if err := s.postRepo.ChangeSlug(post.ID, nil, postSlug); err != nil {
+				return total, err
+			}
+			total++
+		}
+	}
+}
+
+// resolveSlug 确定文章slug：指定了requested时规范化后使用，被占用则报错；
+// 否则根据标题生成，重复时追加数字后缀
+func (s *postService) resolveSlug(postID int, requested, title string) (string, error) {
+	if requested != "" {
+		postSlug := slug.Make(requested)
+		if postSlug == "" {
+			return "", ErrInvalidSlug
+		}
+
+		taken, err := s.postRepo.SlugTaken(postSlug, postID)
+		if err != nil {
+			return "", err
+		}
+		if taken {
+			return "", ErrSlugTaken
+		}
+
+		return postSlug, nil
+	}
+
+	base := slug.Make(title)
+	if base == "" {
+		base = defaultSlug
+	}
+
+	postSlug := base
+	for n := 2; ; n++ {
+		taken, err := s.postRepo.SlugTaken(postSlug, postID)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return postSlug, nil
+		}
+
+		postSlug = fmt.Sprintf("%s-%d", base, n)
+	}
+}
+
+// RenderStale 重新渲染尚未渲染或按旧渲染规则渲染的文章
+func (s *postService) RenderStale() (int, error) {
+	total := 0
+	for {
+		posts, err := s.postRepo.ListStaleRendered(markdown.Version, renderBatchSize)
+		if err != nil {
+			return total, err
+		}
+		if len(posts) == 0 {
+			return total, nil
+		}
+
+		for i := range posts {
+			if err := renderContent(&posts[i]); err != nil {
+				return total, err
+			}
+
+			if err := s.postRepo.UpdateRendered(&posts[i]); err != nil {
+				return total, err
+			}
+			total++
+		}
+	}
+}
+
+// renderContent 按内容格式渲染经过过滤的HTML，Markdown同时生成目录，并根据渲染结果生成摘要和字数统计
+func renderContent(post *model.Post) error {
+	var (
+		html string
+		toc  []markdown.Heading
+	)
+
+	switch post.ContentFormat {
+	case model.ContentFormatHTML:
+		html = markdown.Sanitize(post.Content)
+	case model.ContentFormatPlain:
+		html = markdown.RenderPlain(post.Content)
+	default:
+		result, err := markdown.Render(post.Content)
+		if err != nil {
+			return err
+		}
+		html, toc = result.HTML, result.TOC
+	}
+
+	post.ContentHTML = &html
+	stats := markdown.Summarize(html)
+	post.Excerpt, post.WordCount, post.ReadingTime = stats.Excerpt, stats.WordCount, stats.ReadingTime
+
+	post.TOC = nil
+	if len(toc) > 0 {
+		data, err := json.Marshal(toc)
+		if err != nil {
+			return fmt.Errorf("failed to encode table of contents: %w", err)
+		}
+		tocJSON := string(data)
+		post.TOC = &tocJSON
+	}
+	post.RenderVersion = markdown.Version
+
+	return nil
+}
+
+// pruneRevisions 按保留数量清理旧版本
+func (s *postService) pruneRevisions(postID int) error {
+	if keep := viper.GetInt("posts.revision_retention"); keep > 0 {
+		if err := s.revisionRepo.Prune(postID, keep); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// newRevision 文章当前版本的快照
+func newRevision(post *model.Post, editorID int) *model.PostRevision {
+	return &model.PostRevision{
+		PostID:        post.ID,
+		UserID:        &editorID,
+		Title:         post.Title,
+		Content:       post.Content,
+		ContentFormat: post.ContentFormat,
+		Status:        post.Status,
+	}
+}
+
+// PublishDue 发布已到发布时间的定时文章
+func (s *postService) PublishDue() (int, error) {
+	total := 0
+	for {
+		ids, err := s.postRepo.PublishDue(time.Now().UTC(), publishBatchSize)
+		if err != nil {
+			return total, err
+		}
+
+		total += len(ids)
+		if len(ids) < publishBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// setPublishedAt 根据状态设置发布时间：
+// 定时发布必须指定将来的时间；发布时可以指定过去的时间，未指定时首次发布（或提前发布定时文章）取当前时间；
+// 退回草稿清除发布时间
+func setPublishedAt(post *model.Post, prevStatus model.PostStatus, publishAt *time.Time, now time.Time) error {
+	switch post.Status {
+	case model.PostStatusScheduled:
+		if publishAt == nil {
+			publishAt = post.PublishedAt
+		}
+		if publishAt == nil || !publishAt.After(now) {
+			return ErrInvalidPublishTime
+		}
+	case model.PostStatusPublished:
+		if publishAt != nil && publishAt.After(now) {
+			return ErrInvalidPublishTime
+		}
+		if publishAt == nil {
+			if post.PublishedAt != nil && prevStatus != model.PostStatusScheduled {
+				return nil
+			}
+			publishAt = &now
+		}
+	case model.PostStatusDraft:
+		post.PublishedAt = nil
+		return nil
+	default:
+		if publishAt == nil {
+			return nil
+		}
+	}
+
+	utc := publishAt.UTC()
+	post.PublishedAt = &utc
+	return nil
+}
+
+// buildPostResponses 批量加载作者和标签构建文章响应，避免逐篇查询。
+// withContent为false时构建不含正文的精简响应
+func buildPostResponses(postRepo repository.PostRepository, userRepo repository.UserRepository, posts []model.Post, withContent bool) ([]model.PostResponse, error) {
+	responses := make([]model.PostResponse, len(posts))
+	if len(posts) == 0 {
+		return responses, nil
+	}
+
+	postIDs := make([]int, len(posts))
+	userIDs := make([]int, len(posts))
+	for i, post := range posts {
+		postIDs[i] = post.ID
+		userIDs[i] = post.UserID
+	}
+
+	authors, err := loadPublicUsers(userRepo, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := postRepo.GetTagsByPostIDs(postIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range posts {
+		posts[i].User = authors[posts[i].UserID]
+		posts[i].Tags = tags[posts[i].ID]
+		if withContent {
+			responses[i] = posts[i].ToResponse()
+		} else {
+			responses[i] = posts[i].ToListResponse()
+		}
+	}
+
+	return responses, nil
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/spf13/viper"
+)
+
+// ErrTransitionNotAllowed 不允许的文章状态变更
+var ErrTransitionNotAllowed = errors.New("post status transition is not allowed")
+
+// reapprovalComment 已批准的文章修改内容后自动退回审核时记录的说明
+const reapprovalComment = "content changed after approval"
+
+// authorTransitions 启用审核流程时非审核人可以执行的状态变更：
+// 草稿提交审核（或撤回），审核通过后由作者发布或定时发布，已发布的文章可以归档或撤回为草稿
+var authorTransitions = map[model.PostStatus][]model.PostStatus{
+	model.PostStatusDraft:     {model.PostStatusInReview},
+	model.PostStatusInReview:  {model.PostStatusDraft},
+	model.PostStatusApproved:  {model.PostStatusPublished, model.PostStatusScheduled, model.PostStatusDraft},
+	model.PostStatusScheduled: {model.PostStatusPublished, model.PostStatusDraft},
+	model.PostStatusPublished: {model.PostStatusArchived, model.PostStatusDraft},
+	model.PostStatusArchived:  {model.PostStatusDraft},
+}
+
+// reviewWorkflowEnabled 是否启用文章审核流程
+func reviewWorkflowEnabled() bool {
+	return viper.GetBool("posts.review_workflow")
+}
+
+// checkTransition 检查操作者能否把文章从from状态改为to状态。
+// 审核人（编辑和管理员）不受限制；只有审核人可以批准文章；未启用审核流程时其他状态变更不受限制
+func checkTransition(actor model.Actor, from, to model.PostStatus) error {
+	if from == to || actor.Role.Can(model.PermissionReviewPosts) {
+		return nil
+	}
+
+	if to == model.PostStatusApproved {
+		return fmt.Errorf("%w: only reviewers can approve posts", ErrTransitionNotAllowed)
+	}
+
+	if !reviewWorkflowEnabled() {
+		return nil
+	}
+
+	for _, allowed := range authorTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s -> %s", ErrTransitionNotAllowed, from, to)
+}
+
+// requiresReapproval 启用审核流程时，非审核人修改已批准、已安排定时发布或已发布文章的内容需要重新审核，
+// 已发布的文章在重新审核通过并发布前不再公开
+func requiresReapproval(actor model.Actor, status model.PostStatus) bool {
+	if !reviewWorkflowEnabled() || actor.Role.Can(model.PermissionReviewPosts) {
+		return false
+	}
+
+	switch status {
+	case model.PostStatusApproved, model.PostStatusScheduled, model.PostStatusPublished:
+		return true
+	}
+
+	return false
+}
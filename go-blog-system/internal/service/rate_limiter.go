@@ -0,0 +1,79 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/duanyu/go-blog-system/internal/repository"
+)
+
+// ErrRateLimited 请求过于频繁
+var ErrRateLimited = errors.New("too many requests")
+
+// RateLimitedError 请求被限流，RetryAfter为需要等待的时间
+type RateLimitedError struct {
+	RetryAfter time.Duration
+}
+
+// Error 实现error接口
+func (e *RateLimitedError) Error() string {
+	return fmt.Sprintf("%s, retry after %d seconds", ErrRateLimited, e.RetryAfterSeconds())
+}
+
+// Unwrap 支持errors.Is(err, ErrRateLimited)
+func (e *RateLimitedError) Unwrap() error {
+	return ErrRateLimited
+}
+
+// RetryAfterSeconds 需要等待的秒数（向上取整）
+func (e *RateLimitedError) RetryAfterSeconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
+
+// RateLimiter 按固定时间窗口限制请求次数
+type RateLimiter interface {
+	Allow(key string) error
+}
+
+// rateLimiter 限流实现。计数与登录限流共用登录失败记录仓库（多实例部署时使用数据库存储），
+// 每个时间窗口一条记录，由登录限流的PurgeExpired一并清理
+type rateLimiter struct {
+	attemptRepo repository.LoginAttemptRepository
+	name        string
+	limit       int
+	window      time.Duration
+}
+
+// NewRateLimiter 创建限流器，每个key在window内最多请求limit次，limit不大于0时不限制
+func NewRateLimiter(attemptRepo repository.LoginAttemptRepository, name string, limit int, window time.Duration) RateLimiter {
+	return &rateLimiter{
+		attemptRepo: attemptRepo,
+		name:        name,
+		limit:       limit,
+		window:      window,
+	}
+}
+
+// Allow 记录一次请求，超过当前窗口的次数上限时返回*RateLimitedError
+func (l *rateLimiter) Allow(key string) error {
+	if l.limit <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+	windowStart := now.Truncate(l.window)
+	recordKey := fmt.Sprintf("rate:%s:%s:%d", l.name, key, windowStart.Unix())
+
+	attempt, err := l.attemptRepo.IncrementFailures(recordKey, now, windowStart)
+	if err != nil {
+		return err
+	}
+
+	if attempt.Failures > l.limit {
+		return &RateLimitedError{RetryAfter: windowStart.Add(l.window).Sub(now)}
+	}
+
+	return nil
+}
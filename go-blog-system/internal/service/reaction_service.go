@@ -0,0 +1,187 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/duanyu/go-blog-system/internal/repository"
+)
+
+var (
+	// ErrInvalidReaction 不在可用列表中的表情回应
+	ErrInvalidReaction = errors.New("invalid reaction")
+	// ErrCommentNotFound 评论不存在
+	ErrCommentNotFound = errors.New("comment not found")
+)
+
+// ReactionService 表情回应服务接口
+type ReactionService interface {
+	ReactToPost(postID int, actor model.Actor, reaction model.Reaction) (*model.ReactionsResponse, error)
+	UnreactToPost(postID int, actor model.Actor, reaction model.Reaction) (*model.ReactionsResponse, error)
+	ReactToComment(commentID int, actor model.Actor, reaction model.Reaction) (*model.ReactionsResponse, error)
+	UnreactToComment(commentID int, actor model.Actor, reaction model.Reaction) (*model.ReactionsResponse, error)
+}
+
+// reactionService 表情回应服务实现
+type reactionService struct {
+	reactionRepo repository.ReactionRepository
+	postRepo     repository.PostRepository
+	commentRepo  repository.CommentRepository
+}
+
+// NewReactionService 创建表情回应服务
+func NewReactionService(
+	reactionRepo repository.ReactionRepository,
+	postRepo repository.PostRepository,
+	commentRepo repository.CommentRepository,
+) ReactionService {
+	return &reactionService{
+		reactionRepo: reactionRepo,
+		postRepo:     postRepo,
+		commentRepo:  commentRepo,
+	}
+}
+
+// ReactToPost 对文章添加回应
+func (s *reactionService) ReactToPost(postID int, actor model.Actor, reaction model.Reaction) (*model.ReactionsResponse, error) {
+	return s.changePost(postID, actor, reaction, s.reactionRepo.Add)
+}
+
+// UnreactToPost 取消对文章的回应
+func (s *reactionService) UnreactToPost(postID int, actor model.Actor, reaction model.Reaction) (*model.ReactionsResponse, error) {
+	return s.changePost(postID, actor, reaction, s.reactionRepo.Remove)
+}
+
+// ReactToComment 对评论添加回应
+func (s *reactionService) ReactToComment(commentID int, actor model.Actor, reaction model.Reaction) (*model.ReactionsResponse, error) {
+	return s.changeComment(commentID, actor, reaction, s.reactionRepo.Add)
+}
+
+// UnreactToComment 取消对评论的回应
+func (s *reactionService) UnreactToComment(commentID int, actor model.Actor, reaction model.Reaction) (*model.ReactionsResponse, error) {
+	return s.changeComment(commentID, actor, reaction, s.reactionRepo.Remove)
+}
+
+// reactionChange 添加或取消回应的仓库方法
+type reactionChange func(target model.ReactionTarget, targetID, userID int, reaction model.Reaction) error
+
+// changePost 修改对文章的回应并返回最新的回应数量
+func (s *reactionService) changePost(postID int, actor model.Actor, reaction model.Reaction, change reactionChange) (*model.ReactionsResponse, error) {
+	if !reaction.IsValid() {
+		return nil, ErrInvalidReaction
+	}
+
+	if _, err := visiblePost(s.postRepo, postID, actor); err != nil {
+		return nil, err
+	}
+
+	if err := change(model.ReactionTargetPost, postID, actor.UserID, reaction); err != nil {
+		return nil, err
+	}
+
+	post, err := s.postRepo.GetByID(postID)
+	if err != nil {
+		return nil, ErrPostNotFound
+	}
+
+	return s.response(model.ReactionTargetPost, postID, actor.UserID, post.Reactions)
+}
+
+// changeComment 修改对评论的回应并返回最新的回应数量
+func (s *reactionService) changeComment(commentID int, actor model.Actor, reaction model.Reaction, change reactionChange) (*model.ReactionsResponse, error) {
+	if !reaction.IsValid() {
+		return nil, ErrInvalidReaction
+	}
+
+	comment, err := s.commentRepo.GetByID(commentID)
+	if err != nil {
+		return nil, ErrCommentNotFound
+	}
+
+	if _, err := visiblePost(s.postRepo, comment.PostID, actor); err != nil {
+		return nil, err
+	}
+
+	if err := change(model.ReactionTargetComment, commentID, actor.UserID, reaction); err != nil {
+		return nil, err
+	}
+
+	comment, err = s.commentRepo.GetByID(commentID)
+	if err != nil {
+		return nil, ErrCommentNotFound
+	}
+
+	return s.response(model.ReactionTargetComment, commentID, actor.UserID, comment.Reactions)
+}
+
+// visiblePost 获取操作者可以看到的文章：已发布的文章或操作者可以编辑的文章
+func visiblePost(postRepo repository.PostRepository, postID int, actor model.Actor) (*model.Post, error) {
+	post, err := postRepo.GetByID(postID)
+	if err != nil {
+		return nil, ErrPostNotFound
+	}
+
+	if post.Status != model.PostStatusPublished && !actor.CanModify(post.UserID, model.PermissionEditAnyPost) {
+		return nil, ErrPostNotFound
+	}
+
+	return post, nil
+}
+
+// response 构建回应数量和当前用户回应的响应
+func (s *reactionService) response(target model.ReactionTarget, targetID, userID int, counts model.ReactionCounts) (*model.ReactionsResponse, error) {
+	viewer, err := s.reactionRepo.GetUserReactions(target, userID, []int{targetID})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &model.ReactionsResponse{
+		Reactions:       counts,
+		ViewerReactions: viewer[targetID],
+	}
+	if response.ViewerReactions == nil {
+		response.ViewerReactions = []model.Reaction{}
+	}
+
+	return response, nil
+}
+
+// applyViewerState 为文章响应填充当前用户的回应和收藏状态，viewerID为0（未登录）时不填充
+func applyViewerState(
+	reactionRepo repository.ReactionRepository,
+	bookmarkRepo repository.BookmarkRepository,
+	viewerID int,
+	responses []model.PostResponse,
+) error {
+	if viewerID == 0 || len(responses) == 0 {
+		return nil
+	}
+
+	postIDs := make([]int, len(responses))
+	for i := range responses {
+		postIDs[i] = responses[i].ID
+	}
+
+	reactions, err := reactionRepo.GetUserReactions(model.ReactionTargetPost, viewerID, postIDs)
+	if err != nil {
+		return err
+	}
+
+	bookmarked, err := bookmarkRepo.GetBookmarked(viewerID, postIDs)
+	if err != nil {
+		return err
+	}
+
+	for i := range responses {
+		state := &model.PostViewerState{
+			Reactions:  reactions[responses[i].ID],
+			Bookmarked: bookmarked[responses[i].ID],
+		}
+		if state.Reactions == nil {
+			state.Reactions = []model.Reaction{}
+		}
+		responses[i].Viewer = state
+	}
+
+	return nil
+}
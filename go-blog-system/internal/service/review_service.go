@@ -0,0 +1,144 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/duanyu/go-blog-system/internal/repository"
+)
+
+// ErrPostNotInReview 文章不在审核中
+var ErrPostNotInReview = errors.New("post is not waiting for review")
+
+// ReviewService 文章审核服务接口
+type ReviewService interface {
+	Approve(postID int, actor model.Actor, comment string) (*model.PostResponse, error)
+	RequestChanges(postID int, actor model.Actor, comment string) (*model.PostResponse, error)
+	Queue(query *model.ReviewQueueQuery) ([]model.PostResponse, int, error)
+	History(postID int, actor model.Actor) ([]model.PostTransitionResponse, error)
+}
+
+// reviewService 文章审核服务实现
+type reviewService struct {
+	postRepo       repository.PostRepository
+	userRepo       repository.UserRepository
+	transitionRepo repository.PostTransitionRepository
+	postService    PostService
+}
+
+// NewReviewService 创建文章审核服务
+func NewReviewService(
+	postRepo repository.PostRepository,
+	userRepo repository.UserRepository,
+	transitionRepo repository.PostTransitionRepository,
+	postService PostService,
+) ReviewService {
+	return &reviewService{
+		postRepo:       postRepo,
+		userRepo:       userRepo,
+		transitionRepo: transitionRepo,
+		postService:    postService,
+	}
+}
+
+// Approve 审核通过，文章进入approved状态等待作者发布
+func (s *reviewService) Approve(postID int, actor model.Actor, comment string) (*model.PostResponse, error) {
+	return s.review(postID, actor, model.PostStatusApproved, comment)
+}
+
+// RequestChanges 要求修改，文章退回草稿
+func (s *reviewService) RequestChanges(postID int, actor model.Actor, comment string) (*model.PostResponse, error) {
+	return s.review(postID, actor, model.PostStatusDraft, comment)
+}
+
+// Queue 获取审核队列，最早提交的在前
+func (s *reviewService) Queue(query *model.ReviewQueueQuery) ([]model.PostResponse, int, error) {
+	posts, err := s.postRepo.ListReviewQueue(query.PerPage, (query.Page-1)*query.PerPage)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	count, err := s.postRepo.Count(&model.PostQuery{Status: model.PostStatusInReview})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	responses, err := buildPostResponses(s.postRepo, s.userRepo, posts, false)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return responses, count, nil
+}
+
+// History 获取文章的状态变更历史
+func (s *reviewService) History(postID int, actor model.Actor) ([]model.PostTransitionResponse, error) {
+	if err := checkPostHistoryAccess(s.postRepo, postID, actor); err != nil {
+		return nil, err
+	}
+
+	transitions, err := s.transitionRepo.ListByPostID(postID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(transitions))
+	for _, t := range transitions {
+		if t.UserID != nil {
+			ids = append(ids, *t.UserID)
+		}
+	}
+
+	users, err := loadPublicUsers(s.userRepo, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]model.PostTransitionResponse, len(transitions))
+	for i, t := range transitions {
+		responses[i] = model.PostTransitionResponse{
+			FromStatus: t.FromStatus,
+			ToStatus:   t.ToStatus,
+			Comment:    t.Comment,
+			CreatedAt:  t.CreatedAt,
+		}
+		if t.UserID != nil {
+			responses[i].User = users[*t.UserID]
+		}
+	}
+
+	return responses, nil
+}
+
+// review 将审核中的文章改为to状态并记录审核意见
+func (s *reviewService) review(postID int, actor model.Actor, to model.PostStatus, comment string) (*model.PostResponse, error) {
+	if _, err := s.postRepo.GetByID(postID); err != nil {
+		return nil, ErrPostNotFound
+	}
+
+	// 条件更新保证并发审核时只有一个结果生效
+	ok, err := s.postRepo.UpdateStatus(postID, model.PostStatusInReview, to)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrPostNotInReview
+	}
+
+	from := model.PostStatusInReview
+	transition := &model.PostTransition{
+		PostID:     postID,
+		FromStatus: &from,
+		ToStatus:   to,
+		UserID:     &actor.UserID,
+	}
+	if comment != "" {
+		transition.Comment = &comment
+	}
+	if err := s.transitionRepo.Create(transition); err != nil {
+		return nil, fmt.Errorf("failed to record review: %w", err)
+	}
+
+	return s.postService.GetByID(postID, actor)
+}
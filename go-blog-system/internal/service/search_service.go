@@ -0,0 +1,113 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/duanyu/go-blog-system/internal/repository"
+	"github.com/duanyu/go-blog-system/pkg/markdown"
+	"github.com/duanyu/go-blog-system/pkg/search"
+	"github.com/spf13/viper"
+)
+
+const (
+	// defaultTitleBoost 标题命中的默认权重
+	defaultTitleBoost = 3.0
+	// defaultSnippetLength 摘要片段的默认长度（字符数）
+	defaultSnippetLength = 120
+)
+
+// ErrInvalidSearchQuery 搜索语句不包含有效的关键词
+var ErrInvalidSearchQuery = errors.New("search query must contain at least one keyword")
+
+// SearchService 搜索服务接口
+type SearchService interface {
+	Search(query *model.SearchQuery, actor model.Actor) ([]model.SearchHitResponse, int, error)
+}
+
+// searchService 搜索服务实现
+type searchService struct {
+	postRepo repository.PostRepository
+	userRepo repository.UserRepository
+}
+
+// NewSearchService 创建搜索服务
+func NewSearchService(postRepo repository.PostRepository, userRepo repository.UserRepository) SearchService {
+	return &searchService{
+		postRepo: postRepo,
+		userRepo: userRepo,
+	}
+}
+
+// Search 全文搜索文章，未指定状态时只搜索已发布的文章，其他状态的限制与文章列表相同
+func (s *searchService) Search(query *model.SearchQuery, actor model.Actor) ([]model.SearchHitResponse, int, error) {
+	terms := search.Terms(query.Q)
+	if len(terms) == 0 {
+		return nil, 0, ErrInvalidSearchQuery
+	}
+
+	postQuery := &model.PostQuery{
+		UserID:  query.UserID,
+		Status:  query.Status,
+		TagID:   query.TagID,
+		Keyword: query.Q,
+		PageQuery: model.PageQuery{
+			Page:    query.Page,
+			PerPage: query.PerPage,
+		},
+	}
+	if err := scopePostQuery(postQuery, actor); err != nil {
+		return nil, 0, err
+	}
+
+	hits, err := s.postRepo.Search(postQuery, titleBoost())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	count, err := s.postRepo.Count(postQuery)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	posts := make([]model.Post, len(hits))
+	for i := range hits {
+		posts[i] = hits[i].Post
+	}
+
+	responses, err := buildPostResponses(s.postRepo, s.userRepo, posts, false)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	length := snippetLength()
+	results := make([]model.SearchHitResponse, len(hits))
+	for i, hit := range hits {
+		results[i] = model.SearchHitResponse{
+			Post:           responses[i],
+			Score:          hit.Score,
+			TitleHighlight: search.Highlight(hit.Title, terms),
+			Snippet:        search.Snippet(markdown.PlainText(hit.HTML()), terms, length),
+		}
+	}
+
+	return results, count, nil
+}
+
+// titleBoost 标题命中的权重
+func titleBoost() float64 {
+	if boost := viper.GetFloat64("search.title_boost"); boost > 0 {
+		return boost
+	}
+
+	return defaultTitleBoost
+}
+
+// snippetLength 摘要片段长度
+func snippetLength() int {
+	if length := viper.GetInt("search.snippet_length"); length > 0 {
+		return length
+	}
+
+	return defaultSnippetLength
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/duanyu/go-blog-system/internal/repository"
+)
+
+var (
+	// ErrSeriesNotFound 系列不存在
+	ErrSeriesNotFound = errors.New("series not found")
+	// ErrSeriesAccessDenied 无权修改系列
+	ErrSeriesAccessDenied = errors.New("you don't have permission to modify this series")
+	// ErrSeriesPostNotOwned 只能添加系列作者自己的文章
+	ErrSeriesPostNotOwned = errors.New("only posts by the series author can be added to the series")
+	// ErrPostAlreadyInSeries 文章已属于某个系列
+	ErrPostAlreadyInSeries = errors.New("post already belongs to a series")
+	// ErrSeriesFull 系列中的文章数已达上限
+	ErrSeriesFull = errors.New("series has reached the maximum number of posts")
+)
+
+// SeriesService 系列服务接口
+type SeriesService interface {
+	Create(actor model.Actor, req *model.SeriesRequest) (*model.SeriesResponse, error)
+	Get(id int, actor model.Actor) (*model.SeriesResponse, error)
+	ListByUser(userID int) ([]model.SeriesResponse, error)
+	Update(id int, actor model.Actor, req *model.SeriesRequest) (*model.SeriesResponse, error)
+	Delete(id int, actor model.Actor) error
+	AddPost(id int, actor model.Actor, postID int) (*model.SeriesResponse, error)
+	RemovePost(id int, actor model.Actor, postID int) (*model.SeriesResponse, error)
+	Reorder(id int, actor model.Actor, postIDs []int) (*model.SeriesResponse, error)
+}
+
+// seriesService 系列服务实现
+type seriesService struct {
+	seriesRepo repository.SeriesRepository
+	postRepo   repository.PostRepository
+	userRepo   repository.UserRepository
+}
+
+// NewSeriesService 创建系列服务
+func NewSeriesService(
+	seriesRepo repository.SeriesRepository,
+	postRepo repository.PostRepository,
+	userRepo repository.UserRepository,
+) SeriesService {
+	return &seriesService{
+		seriesRepo: seriesRepo,
+		postRepo:   postRepo,
+		userRepo:   userRepo,
+	}
+}
+
+// Create 创建系列
+func (s *seriesService) Create(actor model.Actor, req *model.SeriesRequest) (*model.SeriesResponse, error) {
+	series := &model.Series{
+		UserID:      actor.UserID,
+		Title:       req.Title,
+		Description: optionalText(req.Description),
+	}
+
+	if err := s.seriesRepo.Create(series); err != nil {
+		return nil, err
+	}
+
+	return s.Get(series.ID, actor)
+}
+
+// Get 获取系列及其中的文章，系列作者和编辑可以看到未发布的文章
+func (s *seriesService) Get(id int, actor model.Actor) (*model.SeriesResponse, error) {
+	series, err := s.seriesRepo.GetByID(id)
+	if err != nil {
+		return nil, ErrSeriesNotFound
+	}
+
+	posts, err := s.seriesRepo.GetPosts(id, !actor.CanModify(series.UserID, model.PermissionEditAnyPost))
+	if err != nil {
+		return nil, err
+	}
+
+	postResponses, err := buildPostResponses(s.postRepo, s.userRepo, posts, false)
+	if err != nil {
+		return nil, err
+	}
+
+	authors, err := loadPublicUsers(s.userRepo, []int{series.UserID})
+	if err != nil {
+		return nil, err
+	}
+
+	response := series.ToResponse()
+	response.User = authors[series.UserID]
+	response.Posts = postResponses
+	response.PostCount = len(postResponses)
+
+	return &response, nil
+}
+
+// ListByUser 获取用户的全部系列（不包含文章列表）
+func (s *seriesService) ListByUser(userID int) ([]model.SeriesResponse, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil || user.IsDeleted() {
+		return nil, ErrUserNotFound
+	}
+
+	series, err := s.seriesRepo.ListByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]model.SeriesResponse, len(series))
+	for i := range series {
+		responses[i] = series[i].ToResponse()
+		responses[i].User = user.ToPublicResponse()
+	}
+
+	return responses, nil
+}
+
+// Update 更新系列标题和简介
+func (s *seriesService) Update(id int, actor model.Actor, req *model.SeriesRequest) (*model.SeriesResponse, error) {
+	series, err := s.getModifiable(id, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	series.Title = req.Title
+	series.Description = optionalText(req.Description)
+
+	if err := s.seriesRepo.Update(series); err != nil {
+		return nil, err
+	}
+
+	return s.Get(id, actor)
+}
+
+// Delete 删除系列，其中的文章保留
+func (s *seriesService) Delete(id int, actor model.Actor) error {
+	if _, err := s.getModifiable(id, actor); err != nil {
+		return err
+	}
+
+	return s.seriesRepo.Delete(id)
+}
+
+// AddPost 将系列作者的文章添加到系列末尾
+func (s *seriesService) AddPost(id int, actor model.Actor, postID int) (*model.SeriesResponse, error) {
+	series, err := s.getModifiable(id, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	post, err := s.postRepo.GetByID(postID)
+	if err != nil {
+		return nil, ErrPostNotFound
+	}
+
+	if post.UserID != series.UserID {
+		return nil, ErrSeriesPostNotOwned
+	}
+
+	current, err := s.seriesRepo.GetByPostID(postID)
+	if err != nil {
+		return nil, err
+	}
+	if current != nil {
+		return nil, ErrPostAlreadyInSeries
+	}
+
+	added, err := s.seriesRepo.AddPost(id, postID)
+	if err != nil {
+		return nil, err
+	}
+	if !added {
+		return nil, ErrSeriesFull
+	}
+
+	return s.Get(id, actor)
+}
+
+// RemovePost 从系列中移除文章
+func (s *seriesService) RemovePost(id int, actor model.Actor, postID int) (*model.SeriesResponse, error) {
+	if _, err := s.getModifiable(id, actor); err != nil {
+		return nil, err
+	}
+
+	if err := s.seriesRepo.RemovePost(id, postID); err != nil {
+		return nil, err
+	}
+
+	return s.Get(id, actor)
+}
+
+// Reorder 调整系列中文章的顺序
+func (s *seriesService) Reorder(id int, actor model.Actor, postIDs []int) (*model.SeriesResponse, error) {
+	if _, err := s.getModifiable(id, actor); err != nil {
+		return nil, err
+	}
+
+	if err := s.seriesRepo.Reorder(id, postIDs); err != nil {
+		return nil, err
+	}
+
+	return s.Get(id, actor)
+}
+
+// getModifiable 获取系列并检查操作者能否修改（系列作者或编辑）
+func (s *seriesService) getModifiable(id int, actor model.Actor) (*model.Series, error) {
+	series, err := s.seriesRepo.GetByID(id)
+	if err != nil {
+		return nil, ErrSeriesNotFound
+	}
+
+	if !actor.CanModify(series.UserID, model.PermissionEditAnyPost) {
+		return nil, ErrSeriesAccessDenied
+	}
+
+	return series, nil
+}
+
+// seriesNavigation 构建文章详情中的系列信息。导航只包含已发布的文章和当前文章，
+// 文章不属于任何系列时返回nil
+func seriesNavigation(seriesRepo repository.SeriesRepository, post *model.Post) (*model.PostSeriesInfo, error) {
+	series, err := seriesRepo.GetByPostID(post.ID)
+	if err != nil || series == nil {
+		return nil, err
+	}
+
+	links, err := seriesRepo.GetPostLinks(series.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := make([]model.SeriesPostLink, 0, len(links))
+	for _, link := range links {
+		if link.Status == model.PostStatusPublished || link.ID == post.ID {
+			visible = append(visible, link)
+		}
+	}
+
+	info := &model.PostSeriesInfo{
+		ID:    series.ID,
+		Title: series.Title,
+		Total: len(visible),
+	}
+	for i := range visible {
+		if visible[i].ID != post.ID {
+			continue
+		}
+
+		info.Position = i + 1
+		if i > 0 {
+			info.Prev = &visible[i-1]
+		}
+		if i+1 < len(visible) {
+			info.Next = &visible[i+1]
+		}
+	}
+
+	return info, nil
+}
+
+// optionalText 空字符串视为未填写
+func optionalText(s *string) *string {
+	if s == nil || *s == "" {
+		return nil
+	}
+
+	return s
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// ErrInvalidSignedToken 签名令牌无效或已过期
+var ErrInvalidSignedToken = errors.New("invalid or expired link")
+
+// signToken 生成带过期时间的HMAC签名令牌，purpose用于区分不同用途的令牌
+func signToken(purpose, payload string, ttl time.Duration) string {
+	data := fmt.Sprintf("%d|%s", time.Now().Add(ttl).Unix(), payload)
+
+	return base64.RawURLEncoding.EncodeToString([]byte(data)) + "." +
+		base64.RawURLEncoding.EncodeToString(computeSignature(purpose, data))
+}
+
+// verifySignedToken 校验签名令牌并返回其中的负载
+func verifySignedToken(purpose, token string) (string, error) {
+	parts := strings.SplitN(token, ".", 2)
+	if len(parts) != 2 {
+		return "", ErrInvalidSignedToken
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", ErrInvalidSignedToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(signature, computeSignature(purpose, string(data))) {
+		return "", ErrInvalidSignedToken
+	}
+
+	fields := strings.SplitN(string(data), "|", 2)
+	if len(fields) != 2 {
+		return "", ErrInvalidSignedToken
+	}
+
+	exp, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil || time.Now().Unix() > exp {
+		return "", ErrInvalidSignedToken
+	}
+
+	return fields[1], nil
+}
+
+// computeSignature 计算HMAC-SHA256签名
+func computeSignature(purpose, data string) []byte {
+	mac := hmac.New(sha256.New, []byte(viper.GetString("app.signing_secret")))
+	mac.Write([]byte(purpose + "|" + data))
+
+	return mac.Sum(nil)
+}
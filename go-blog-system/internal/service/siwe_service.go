@@ -0,0 +1,183 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/duanyu/go-blog-system/internal/repository"
+	"github.com/duanyu/go-blog-system/pkg/siwe"
+	"github.com/spf13/viper"
+)
+
+// ErrWalletNotLinked 钱包未绑定任何用户
+var ErrWalletNotLinked = errors.New("wallet is not linked to any account")
+
+// SIWEService 以太坊登录（EIP-4361）服务接口
+type SIWEService interface {
+	GenerateNonce(ip string) (*model.SIWENonce, error)
+	Login(req *model.SIWERequest) (*model.LoginResponse, error)
+	LinkWallet(userID int, req *model.SIWERequest) (*model.Wallet, error)
+	UnlinkWallet(userID int, address string) error
+	PurgeExpiredNonces() error
+}
+
+// siweService 以太坊登录服务实现
+type siweService struct {
+	walletRepo   repository.WalletRepository
+	userRepo     repository.UserRepository
+	tokenService TokenService
+	nonceLimiter RateLimiter
+}
+
+// NewSIWEService 创建以太坊登录服务
+func NewSIWEService(
+	walletRepo repository.WalletRepository,
+	userRepo repository.UserRepository,
+	tokenService TokenService,
+	nonceLimiter RateLimiter,
+) SIWEService {
+	return &siweService{
+		walletRepo:   walletRepo,
+		userRepo:     userRepo,
+		tokenService: tokenService,
+		nonceLimiter: nonceLimiter,
+	}
+}
+
+// GenerateNonce 生成一次性随机数，按IP限制频率
+func (s *siweService) GenerateNonce(ip string) (*model.SIWENonce, error) {
+	if err := s.nonceLimiter.Allow(ip); err != nil {
+		return nil, err
+	}
+
+	value, err := randomToken(16)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	nonce := &model.SIWENonce{
+		Nonce:     value,
+		ExpiresAt: time.Now().Add(time.Minute * time.Duration(viper.GetInt("siwe.nonce_expiration"))),
+	}
+
+	if err := s.walletRepo.CreateNonce(nonce); err != nil {
+		return nil, err
+	}
+
+	return nonce, nil
+}
+
+// PurgeExpiredNonces 清理过期或已使用的随机数
+func (s *siweService) PurgeExpiredNonces() error {
+	return s.walletRepo.DeleteExpiredNonces(time.Now())
+}
+
+// Login 校验签名消息并为已绑定该钱包的用户签发令牌
+func (s *siweService) Login(req *model.SIWERequest) (*model.LoginResponse, error) {
+	address, err := s.verify(req)
+	if err != nil {
+		return nil, err
+	}
+
+	wallet, err := s.walletRepo.GetByAddress(address)
+	if err != nil {
+		return nil, ErrWalletNotLinked
+	}
+
+	user, err := s.userRepo.GetByID(wallet.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// 钱包签名只是第一因素，开启两步验证时同样返回质询令牌
+	return beginLogin(s.tokenService, s.userRepo, user, req.Client)
+}
+
+// LinkWallet 校验签名消息并将钱包绑定到当前用户
+func (s *siweService) LinkWallet(userID int, req *model.SIWERequest) (*model.Wallet, error) {
+	address, err := s.verify(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing, err := s.walletRepo.GetByAddress(address); err == nil {
+		if existing.UserID == userID {
+			return existing, nil
+		}
+		return nil, errors.New("wallet is already linked to another account")
+	}
+
+	wallet := &model.Wallet{
+		UserID:  userID,
+		Address: address,
+	}
+
+	if err := s.walletRepo.Create(wallet); err != nil {
+		return nil, err
+	}
+
+	return wallet, nil
+}
+
+// UnlinkWallet 解绑钱包
+func (s *siweService) UnlinkWallet(userID int, address string) error {
+	if !siwe.IsHexAddress(address) {
+		return errors.New("invalid wallet address")
+	}
+
+	return s.walletRepo.Delete(userID, strings.ToLower(address))
+}
+
+// verify 校验EIP-4361消息和签名，返回小写的签名者地址
+func (s *siweService) verify(req *model.SIWERequest) (string, error) {
+	msg, err := siwe.ParseMessage(req.Message)
+	if err != nil {
+		return "", err
+	}
+
+	if msg.Domain != viper.GetString("siwe.domain") {
+		return "", errors.New("siwe message domain mismatch")
+	}
+
+	if chainIDs := viper.GetIntSlice("siwe.chain_ids"); len(chainIDs) > 0 && !containsInt(chainIDs, msg.ChainID) {
+		return "", fmt.Errorf("unsupported chain id: %d", msg.ChainID)
+	}
+
+	if err := msg.ValidAt(time.Now()); err != nil {
+		return "", err
+	}
+
+	signer, err := siwe.RecoverAddress(req.Message, req.Signature)
+	if err != nil {
+		return "", err
+	}
+
+	if signer != strings.ToLower(msg.Address) {
+		return "", errors.New("signature does not match siwe message address")
+	}
+
+	// 签名校验通过后再消耗随机数，防止重放
+	consumed, err := s.walletRepo.ConsumeNonce(msg.Nonce)
+	if err != nil {
+		return "", err
+	}
+	if !consumed {
+		return "", errors.New("invalid or expired siwe nonce")
+	}
+
+	return signer, nil
+}
+
+// containsInt 检查切片是否包含指定整数
+func containsInt(values []int, target int) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+
+	return false
+}
@@ -12,7 +12,7 @@ import (
 type TagService interface {
 	Create(req *model.CreateTagRequest) (*model.Tag, error)
 	GetByID(id int) (*model.Tag, error)
-	List() ([]model.Tag, error)
+	List(query *model.PageQuery) (*model.Page[model.Tag], error)
 	Delete(id int) error
 }
 
@@ -51,9 +51,20 @@ func (s *tagService) GetByID(id int) (*model.Tag, error) {
 	return s.tagRepo.GetByID(id)
 }
 
-// List 获取所有标签
-func (s *tagService) List() ([]model.Tag, error) {
-	return s.tagRepo.List()
+// List 分页获取标签
+func (s *tagService) List(query *model.PageQuery) (*model.Page[model.Tag], error) {
+	normalizePage(query, 50)
+
+	page, err := s.tagRepo.List(query)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fillTotal(page, query, s.tagRepo.Count); err != nil {
+		return nil, err
+	}
+
+	return page, nil
 }
 
 // Delete 删除标签
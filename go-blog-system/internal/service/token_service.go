@@ -0,0 +1,403 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/duanyu/go-blog-system/internal/model"
+	"github.com/duanyu/go-blog-system/internal/repository"
+	"github.com/duanyu/go-blog-system/pkg/jwtkeys"
+	"github.com/spf13/viper"
+)
+
+var (
+	// ErrInvalidToken 访问令牌无效
+	ErrInvalidToken = errors.New("invalid token")
+	// ErrTokenRevoked 访问令牌已被吊销
+	ErrTokenRevoked = errors.New("token has been revoked")
+	// ErrInvalidRefreshToken 刷新令牌无效或已过期
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	// ErrRefreshTokenReused 刷新令牌被重复使用
+	ErrRefreshTokenReused = errors.New("refresh token reuse detected, session revoked")
+	// ErrSessionNotFound 会话不存在或已吊销
+	ErrSessionNotFound = errors.New("session not found")
+)
+
+const (
+	// sessionTouchInterval 会话最近活跃时间的记录间隔
+	sessionTouchInterval = time.Minute
+	// maxUserAgentLength 保存的User-Agent最大长度
+	maxUserAgentLength = 255
+)
+
+// AccessClaims 访问令牌中携带的身份信息
+type AccessClaims struct {
+	UserID    int
+	Role      model.Role
+	MFA       bool // 本次登录是否通过了两步验证
+	SessionID string
+	TokenID   string
+	ExpiresAt time.Time
+
+	// 以下字段仅在使用个人访问令牌认证时设置
+	PersonalTokenID int
+	Scopes          []model.Scope
+}
+
+// IsPersonalToken 是否通过个人访问令牌认证
+func (c *AccessClaims) IsPersonalToken() bool {
+	return c.PersonalTokenID != 0
+}
+
+// HasScope 检查是否拥有指定授权范围，登录会话不受授权范围限制
+func (c *AccessClaims) HasScope(scope model.Scope) bool {
+	if !c.IsPersonalToken() {
+		return true
+	}
+
+	for _, s := range c.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// TokenService 令牌服务接口
+type TokenService interface {
+	Issue(user *model.User, mfa bool, client model.ClientInfo) (*model.TokenPair, error)
+	Refresh(refreshToken string, client model.ClientInfo) (*model.TokenPair, error)
+	ParseAccessToken(tokenString string) (*AccessClaims, error)
+	TouchSession(sessionID, ip string) error
+	ListSessions(userID int, currentSessionID string) ([]*model.SessionResponse, error)
+	RevokeSession(userID int, sessionID string) error
+	RevokeOtherSessions(userID int, keepSessionID string) error
+	Revoke(claims *AccessClaims) error
+	RevokeAllForUser(userID int) error
+	UseOnce(jti string, expiresAt time.Time) (bool, error)
+	PurgeExpired() error
+}
+
+// tokenService 令牌服务实现
+type tokenService struct {
+	tokenRepo repository.TokenRepository
+	userRepo  repository.UserRepository
+	keys      *jwtkeys.KeySet
+}
+
+// NewTokenService 创建令牌服务
+func NewTokenService(
+	tokenRepo repository.TokenRepository,
+	userRepo repository.UserRepository,
+	keys *jwtkeys.KeySet,
+) TokenService {
+	return &tokenService{
+		tokenRepo: tokenRepo,
+		userRepo:  userRepo,
+		keys:      keys,
+	}
+}
+
+// Issue 为用户开启新会话并签发令牌，mfa表示本次登录是否通过了两步验证
+func (s *tokenService) Issue(user *model.User, mfa bool, client model.ClientInfo) (*model.TokenPair, error) {
+	sessionID, err := randomToken(16)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate session id: %w", err)
+	}
+
+	now := time.Now()
+	session := &model.Session{
+		ID:         sessionID,
+		UserID:     user.ID,
+		MFA:        mfa,
+		UserAgent:  truncateUserAgent(client.UserAgent),
+		IP:         client.IP,
+		LastSeenAt: &now,
+		ExpiresAt:  now.Add(refreshTokenTTL()),
+	}
+
+	if err := s.tokenRepo.CreateSession(session); err != nil {
+		return nil, err
+	}
+
+	return s.issuePair(session, user)
+}
+
+// Refresh 使用刷新令牌换取新的令牌对（刷新令牌一次性使用并轮换）
+func (s *tokenService) Refresh(refreshToken string, client model.ClientInfo) (*model.TokenPair, error) {
+	token, err := s.tokenRepo.GetRefreshTokenByHash(hashToken(refreshToken))
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	session, err := s.tokenRepo.GetSessionByID(token.SessionID)
+	if err != nil || session.RevokedAt != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	// 已轮换过的令牌再次出现，说明令牌可能被盗，吊销整个令牌族
+	if token.UsedAt != nil {
+		if err := s.tokenRepo.RevokeSession(session.ID); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	}
+
+	if time.Now().After(token.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	// 并发刷新时只有一个请求能成功标记
+	marked, err := s.tokenRepo.MarkRefreshTokenUsed(token.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !marked {
+		if err := s.tokenRepo.RevokeSession(session.ID); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	}
+
+	// 重新读取用户，使角色变更在刷新后生效
+	user, err := s.userRepo.GetByID(session.UserID)
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	session.ExpiresAt = time.Now().Add(refreshTokenTTL())
+	if err := s.tokenRepo.ExtendSession(session.ID, session.ExpiresAt); err != nil {
+		return nil, err
+	}
+
+	if err := s.TouchSession(session.ID, client.IP); err != nil {
+		return nil, err
+	}
+
+	return s.issuePair(session, user)
+}
+
+// ParseAccessToken 解析访问令牌并检查吊销状态
+func (s *tokenService) ParseAccessToken(tokenString string) (*AccessClaims, error) {
+	// 根据kid选择验证密钥，并校验签名方法
+	token, err := jwt.Parse(tokenString, s.keys.Keyfunc)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	mapClaims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, ErrInvalidToken
+	}
+
+	claims, err := claimsFromMap(mapClaims)
+	if err != nil {
+		return nil, err
+	}
+
+	revoked, err := s.tokenRepo.IsAccessTokenRevoked(claims.TokenID)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, ErrTokenRevoked
+	}
+
+	session, err := s.tokenRepo.GetSessionByID(claims.SessionID)
+	if err != nil || session.RevokedAt != nil {
+		return nil, ErrTokenRevoked
+	}
+
+	return claims, nil
+}
+
+// TouchSession 记录会话的最近活跃时间和IP
+func (s *tokenService) TouchSession(sessionID, ip string) error {
+	return s.tokenRepo.TouchSession(sessionID, ip, time.Now(), sessionTouchInterval)
+}
+
+// ListSessions 获取用户的活跃会话，currentSessionID对应的会话标记为当前会话
+func (s *tokenService) ListSessions(userID int, currentSessionID string) ([]*model.SessionResponse, error) {
+	sessions, err := s.tokenRepo.ListActiveSessions(userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*model.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		responses = append(responses, &model.SessionResponse{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IP:         session.IP,
+			MFA:        session.MFA,
+			Current:    session.ID == currentSessionID,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			ExpiresAt:  session.ExpiresAt,
+		})
+	}
+
+	return responses, nil
+}
+
+// RevokeSession 吊销用户的指定会话，该会话的访问令牌随即失效
+func (s *tokenService) RevokeSession(userID int, sessionID string) error {
+	revoked, err := s.tokenRepo.RevokeUserSession(userID, sessionID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrSessionNotFound
+	}
+
+	return nil
+}
+
+// RevokeOtherSessions 吊销用户除指定会话外的所有会话
+func (s *tokenService) RevokeOtherSessions(userID int, keepSessionID string) error {
+	return s.tokenRepo.RevokeOtherUserSessions(userID, keepSessionID)
+}
+
+// Revoke 注销：吊销会话并将当前访问令牌加入黑名单
+func (s *tokenService) Revoke(claims *AccessClaims) error {
+	if err := s.tokenRepo.RevokeSession(claims.SessionID); err != nil {
+		return err
+	}
+
+	return s.tokenRepo.RevokeAccessToken(claims.TokenID, claims.ExpiresAt)
+}
+
+// RevokeAllForUser 吊销用户的所有会话
+func (s *tokenService) RevokeAllForUser(userID int) error {
+	return s.tokenRepo.RevokeUserSessions(userID)
+}
+
+// UseOnce 记录一次性令牌（如两步验证质询令牌）已使用，返回false表示重复使用
+func (s *tokenService) UseOnce(jti string, expiresAt time.Time) (bool, error) {
+	return s.tokenRepo.UseTokenID(jti, expiresAt)
+}
+
+// PurgeExpired 清理过期的令牌记录
+func (s *tokenService) PurgeExpired() error {
+	return s.tokenRepo.DeleteExpired(time.Now())
+}
+
+// issuePair 在会话下签发访问令牌和新的刷新令牌
+func (s *tokenService) issuePair(session *model.Session, user *model.User) (*model.TokenPair, error) {
+	accessToken, err := s.generateJWT(user, session)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	rawRefreshToken, err := randomToken(32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	refreshToken := &model.RefreshToken{
+		SessionID: session.ID,
+		TokenHash: hashToken(rawRefreshToken),
+		ExpiresAt: session.ExpiresAt,
+	}
+
+	if err := s.tokenRepo.CreateRefreshToken(refreshToken); err != nil {
+		return nil, err
+	}
+
+	return &model.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: rawRefreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(accessTokenTTL().Seconds()),
+	}, nil
+}
+
+// generateJWT 生成JWT访问令牌
+func (s *tokenService) generateJWT(user *model.User, session *model.Session) (string, error) {
+	jti, err := randomToken(16)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"user_id": user.ID,
+		"role":    string(user.Role),
+		"mfa":     session.MFA,
+		"sid":     session.ID,
+		"jti":     jti,
+		"exp":     now.Add(accessTokenTTL()).Unix(),
+		"iat":     now.Unix(),
+	}
+
+	return s.keys.Sign(claims)
+}
+
+// claimsFromMap 从JWT声明中提取身份信息
+func claimsFromMap(mapClaims jwt.MapClaims) (*AccessClaims, error) {
+	userID, ok := mapClaims["user_id"].(float64)
+	if !ok {
+		return nil, errors.New("invalid user id in token")
+	}
+
+	role, _ := mapClaims["role"].(string)
+	mfa, _ := mapClaims["mfa"].(bool)
+	sessionID, _ := mapClaims["sid"].(string)
+	tokenID, _ := mapClaims["jti"].(string)
+	exp, _ := mapClaims["exp"].(float64)
+	if sessionID == "" || tokenID == "" {
+		return nil, ErrInvalidToken
+	}
+
+	return &AccessClaims{
+		UserID:    int(userID),
+		Role:      model.Role(role),
+		MFA:       mfa,
+		SessionID: sessionID,
+		TokenID:   tokenID,
+		ExpiresAt: time.Unix(int64(exp), 0),
+	}, nil
+}
+
+// accessTokenTTL 访问令牌有效期
+func accessTokenTTL() time.Duration {
+	return time.Minute * time.Duration(viper.GetInt("app.jwt_access_expiration"))
+}
+
+// refreshTokenTTL 刷新令牌有效期
+func refreshTokenTTL() time.Duration {
+	return time.Hour * time.Duration(viper.GetInt("app.jwt_refresh_expiration"))
+}
+
+// truncateUserAgent 截断过长的User-Agent
+func truncateUserAgent(userAgent string) string {
+	runes := []rune(userAgent)
+	if len(runes) > maxUserAgentLength {
+		return string(runes[:maxUserAgentLength])
+	}
+
+	return userAgent
+}
+
+// randomToken 生成n字节的随机十六进制字符串
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// hashToken 计算令牌的SHA-256哈希
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -3,37 +3,100 @@ package service
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
-	"github.com/dgrijalva/jwt-go"
 	"github.com/duanyu/go-blog-system/internal/model"
 	"github.com/duanyu/go-blog-system/internal/repository"
+	"github.com/duanyu/go-blog-system/pkg/mailer"
+	"github.com/duanyu/go-blog-system/pkg/siwe"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 	"golang.org/x/crypto/bcrypt"
 )
 
+var (
+	// ErrEmailNotVerified 邮箱未验证
+	ErrEmailNotVerified = errors.New("email address is not verified")
+	// ErrVerificationThrottled 验证邮件发送过于频繁
+	ErrVerificationThrottled = errors.New("verification email was sent recently, please try again later")
+	// ErrAccountDeleted 账号已注销且不可恢复
+	ErrAccountDeleted = errors.New("account has been deleted")
+	// ErrUserNotFound 用户不存在或已注销
+	ErrUserNotFound = errors.New("user not found")
+)
+
+const (
+	// emailVerificationPurpose 邮箱验证令牌用途
+	emailVerificationPurpose = "email-verification"
+	// anonymizeBatchSize 每轮匿名化处理的用户数
+	anonymizeBatchSize = 100
+	// anonymizedUsernamePrefix 匿名化账号的用户名前缀
+	anonymizedUsernamePrefix = "deleted_user_"
+	// recentPostsLimit 用户主页展示的最近文章数
+	recentPostsLimit = 5
+)
+
 // UserService 用户服务接口
 type UserService interface {
 	Register(req *model.CreateUserRequest) (*model.UserResponse, error)
-	Login(req *model.LoginRequest) (string, *model.UserResponse, error)
+	Login(req *model.LoginRequest) (*model.LoginResponse, error)
 	GetByID(id int) (*model.UserResponse, error)
+	GetProfile(id int, viewer model.Actor) (*model.UserProfileResponse, error)
+	Directory(query *model.UserDirectoryQuery) (*model.Page[model.UserDirectoryEntry], error)
 	Update(id int, req *model.UpdateUserRequest) (*model.UserResponse, error)
-	Delete(id int) error
-	List(page, perPage int) ([]model.UserResponse, int, error)
+	VerifyEmail(token string) (*model.UserResponse, error)
+	ResendVerification(id int) error
+	ChangePassword(id int, req *model.ChangePasswordRequest) (*model.TokenPair, error)
+	RequestPasswordReset(req *model.ForgotPasswordRequest) error
+	ResetPassword(req *model.ResetPasswordRequest) error
+	UpdateRole(id int, role model.Role) (*model.UserResponse, error)
+	Unlock(id int) error
+	Delete(id, deletedBy int) (time.Time, error)
+	AnonymizeDeleted() (int, error)
+	PurgeExpiredResets() error
+	List(query *model.PageQuery) (*model.Page[model.UserResponse], error)
 }
 
 // userService 用户服务实现
 type userService struct {
-	userRepo repository.UserRepository
+	userRepo     repository.UserRepository
+	resetRepo    repository.PasswordResetRepository
+	walletRepo   repository.WalletRepository
+	postRepo     repository.PostRepository
+	tokenService TokenService
+	throttle     LoginThrottle
+	mailer       mailer.Mailer
 }
 
 // NewUserService 创建用户服务
-func NewUserService(userRepo repository.UserRepository) UserService {
-	return &userService{userRepo: userRepo}
+func NewUserService(
+	userRepo repository.UserRepository,
+	resetRepo repository.PasswordResetRepository,
+	walletRepo repository.WalletRepository,
+	postRepo repository.PostRepository,
+	tokenService TokenService,
+	throttle LoginThrottle,
+	mail mailer.Mailer,
+) UserService {
+	return &userService{
+		userRepo:     userRepo,
+		resetRepo:    resetRepo,
+		walletRepo:   walletRepo,
+		postRepo:     postRepo,
+		tokenService: tokenService,
+		throttle:     throttle,
+		mailer:       mail,
+	}
 }
 
 // Register 注册用户
 func (s *userService) Register(req *model.CreateUserRequest) (*model.UserResponse, error) {
+	// 保留匿名化账号使用的用户名
+	if strings.HasPrefix(strings.ToLower(req.Username), anonymizedUsernamePrefix) {
+		return nil, errors.New("username is reserved")
+	}
+
 	// 检查用户名是否已存在
 	_, err := s.userRepo.GetByUsername(req.Username)
 	if err == nil {
@@ -57,6 +120,7 @@ func (s *userService) Register(req *model.CreateUserRequest) (*model.UserRespons
 		Username: req.Username,
 		Email:    req.Email,
 		Password: string(hashedPassword),
+		Role:     model.RoleAuthor,
 		Avatar:   req.Avatar,
 		Bio:      req.Bio,
 	}
@@ -65,44 +129,42 @@ func (s *userService) Register(req *model.CreateUserRequest) (*model.UserRespons
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
-	return &model.UserResponse{
-		ID:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		Avatar:    user.Avatar,
-		Bio:       user.Bio,
-		CreatedAt: user.CreatedAt,
-	}, nil
+	// 发送验证邮件失败不影响注册，用户可稍后重新发送
+	if err := s.sendVerificationEmail(user); err != nil {
+		logrus.Warnf("Failed to send verification email to user %d: %v", user.ID, err)
+	}
+
+	return userResponse(user), nil
 }
 
 // Login 用户登录
-func (s *userService) Login(req *model.LoginRequest) (string, *model.UserResponse, error) {
-	// 获取用户
+func (s *userService) Login(req *model.LoginRequest) (*model.LoginResponse, error) {
+	// 检查账号和IP是否处于退避或锁定期
+	if err := s.throttle.Check(req.Username, req.Client.IP); err != nil {
+		return nil, err
+	}
+
+	// 获取用户（不存在的用户名同样计入失败次数）
 	user, err := s.userRepo.GetByUsername(req.Username)
 	if err != nil {
-		return "", nil, errors.New("invalid username or password")
+		return nil, s.loginFailed(req)
 	}
 
 	// 验证密码
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password))
 	if err != nil {
-		return "", nil, errors.New("invalid username or password")
+		return nil, s.loginFailed(req)
 	}
 
-	// 生成JWT令牌
-	token, err := generateJWT(user.ID)
-	if err != nil {
-		return "", nil, fmt.Errorf("failed to generate token: %w", err)
+	// 开启两步验证的账号在第二步通过后才清除失败记录
+	if !user.IsMFAEnabled() {
+		if err := s.throttle.RecordSuccess(req.Username); err != nil {
+			return nil, err
+		}
 	}
 
-	return token, &model.UserResponse{
-		ID:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		Avatar:    user.Avatar,
-		Bio:       user.Bio,
-		CreatedAt: user.CreatedAt,
-	}, nil
+	// 签发令牌（开启两步验证时返回质询令牌）
+	return beginLogin(s.tokenService, s.userRepo, user, req.Client)
 }
 
 // GetByID 根据ID获取用户
@@ -112,14 +174,81 @@ func (s *userService) GetByID(id int) (*model.UserResponse, error) {
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
-	return &model.UserResponse{
-		ID:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		Avatar:    user.Avatar,
-		Bio:       user.Bio,
-		CreatedAt: user.CreatedAt,
-	}, nil
+	// 获取绑定的钱包
+	wallets, err := s.walletRepo.ListByUserID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallets: %w", err)
+	}
+
+	response := userResponse(user)
+	for _, wallet := range wallets {
+		response.Wallets = append(response.Wallets, siwe.ChecksumAddress(wallet.Address))
+	}
+
+	return response, nil
+}
+
+// GetProfile 获取用户公开主页，邮箱仅对本人和管理员可见
+func (s *userService) GetProfile(id int, viewer model.Actor) (*model.UserProfileResponse, error) {
+	user, err := s.userRepo.GetByID(id)
+	if err != nil || user.IsDeleted() {
+		return nil, ErrUserNotFound
+	}
+
+	stats, err := s.userRepo.GetStats(id)
+	if err != nil {
+		return nil, err
+	}
+
+	recent, err := s.postRepo.List(&model.PostQuery{
+		UserID:    &id,
+		Status:    model.PostStatusPublished,
+		PageQuery: model.PageQuery{PerPage: recentPostsLimit},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recent posts: %w", err)
+	}
+
+	profile := &model.UserProfileResponse{
+		PublicUserResponse: *user.ToPublicResponse(),
+		PostCount:          stats.PostCount,
+		CommentCount:       stats.CommentCount,
+		FollowerCount:      stats.FollowerCount,
+		FollowingCount:     stats.FollowingCount,
+		RecentPosts:        make([]model.PostSummary, 0, len(recent.Items)),
+	}
+
+	for _, post := range recent.Items {
+		profile.RecentPosts = append(profile.RecentPosts, model.PostSummary{
+			ID:          post.ID,
+			Title:       post.Title,
+			Slug:        post.Slug,
+			PublishedAt: post.PublishedAt,
+			CreatedAt:   post.CreatedAt,
+		})
+	}
+
+	if viewer.CanModify(user.ID, model.PermissionManageUsers) {
+		profile.Email = user.Email
+	}
+
+	return profile, nil
+}
+
+// Directory 分页获取公开的用户目录
+func (s *userService) Directory(query *model.UserDirectoryQuery) (*model.Page[model.UserDirectoryEntry], error) {
+	normalizePage(&query.PageQuery, 20)
+
+	page, err := s.userRepo.ListDirectory(query)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fillTotal(page, &query.PageQuery, func() (int, error) { return s.userRepo.CountDirectory(query) }); err != nil {
+		return nil, fmt.Errorf("failed to count user directory: %w", err)
+	}
+
+	return page, nil
 }
 
 // Update 更新用户
@@ -130,13 +259,16 @@ func (s *userService) Update(id int, req *model.UpdateUserRequest) (*model.UserR
 	}
 
 	// 更新字段
-	if req.Email != nil {
+	emailChanged := false
+	if req.Email != nil && *req.Email != user.Email {
 		// 检查邮箱是否已被其他用户使用
 		existingUser, err := s.userRepo.GetByEmail(*req.Email)
 		if err == nil && existingUser.ID != id {
 			return nil, errors.New("email already in use")
 		}
 		user.Email = *req.Email
+		user.EmailVerifiedAt = nil
+		emailChanged = true
 	}
 
 	if req.Avatar != nil {
@@ -151,64 +283,329 @@ func (s *userService) Update(id int, req *model.UpdateUserRequest) (*model.UserR
 		return nil, fmt.Errorf("failed to update user: %w", err)
 	}
 
-	return &model.UserResponse{
-		ID:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		Avatar:    user.Avatar,
-		Bio:       user.Bio,
-		CreatedAt: user.CreatedAt,
-	}, nil
+	// 邮箱变更后需要重新验证
+	if emailChanged {
+		if err := s.sendVerificationEmail(user); err != nil {
+			logrus.Warnf("Failed to send verification email to user %d: %v", user.ID, err)
+		}
+	}
+
+	return userResponse(user), nil
 }
 
-// Delete 删除用户
-func (s *userService) Delete(id int) error {
-	return s.userRepo.Delete(id)
+// VerifyEmail 校验邮件中的签名链接并标记邮箱已验证
+func (s *userService) VerifyEmail(token string) (*model.UserResponse, error) {
+	payload, err := verifySignedToken(emailVerificationPurpose, token)
+	if err != nil {
+		return nil, err
+	}
+
+	// 负载格式：用户ID|邮箱
+	var userID int
+	var email string
+	if _, err := fmt.Sscanf(payload, "%d|%s", &userID, &email); err != nil {
+		return nil, ErrInvalidSignedToken
+	}
+
+	// 邮箱已被修改时旧链接失效
+	verified, err := s.userRepo.MarkEmailVerified(userID, email)
+	if err != nil {
+		return nil, err
+	}
+	if !verified {
+		return nil, ErrInvalidSignedToken
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return userResponse(user), nil
 }
 
-// List 获取用户列表
-func (s *userService) List(page, perPage int) ([]model.UserResponse, int, error) {
-	users, err := s.userRepo.List(page, perPage)
+// ResendVerification 重新发送验证邮件
+func (s *userService) ResendVerification(id int) error {
+	user, err := s.userRepo.GetByID(id)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to list users: %w", err)
+		return fmt.Errorf("failed to get user: %w", err)
 	}
 
-	count, err := s.userRepo.Count()
+	if user.IsEmailVerified() {
+		return errors.New("email address is already verified")
+	}
+
+	interval := time.Second * time.Duration(viper.GetInt("app.email_verification_resend_interval"))
+	if user.VerificationSentAt != nil && time.Since(*user.VerificationSentAt) < interval {
+		return ErrVerificationThrottled
+	}
+
+	return s.sendVerificationEmail(user)
+}
+
+// sendVerificationEmail 发送带签名链接的验证邮件
+func (s *userService) sendVerificationEmail(user *model.User) error {
+	expiration := time.Hour * time.Duration(viper.GetInt("app.email_verification_expiration"))
+	token := signToken(emailVerificationPurpose, fmt.Sprintf("%d|%s", user.ID, user.Email), expiration)
+	link := fmt.Sprintf("%s/api/verify-email?token=%s", viper.GetString("app.base_url"), token)
+
+	err := s.mailer.Send(&mailer.Message{
+		To:      []string{user.Email},
+		Subject: "验证您的邮箱",
+		Body: fmt.Sprintf("%s，您好：\n\n请在%d小时内访问以下链接完成邮箱验证：\n\n%s\n\n如果这不是您本人的操作，请忽略此邮件。\n",
+			user.Username, int(expiration.Hours()), link),
+	})
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count users: %w", err)
+		return err
 	}
 
-	userResponses := make([]model.UserResponse, len(users))
-	for i, user := range users {
-		userResponses[i] = model.UserResponse{
-			ID:        user.ID,
-			Username:  user.Username,
-			Email:     user.Email,
-			Avatar:    user.Avatar,
-			Bio:       user.Bio,
-			CreatedAt: user.CreatedAt,
-		}
+	return s.userRepo.SetVerificationSentAt(user.ID, time.Now())
+}
+
+// checkEmailVerified 开启强制验证时检查用户邮箱是否已验证
+func checkEmailVerified(user *model.User) error {
+	if viper.GetBool("app.require_verified_email") && !user.IsEmailVerified() {
+		return ErrEmailNotVerified
+	}
+
+	return nil
+}
+
+// ChangePassword 修改密码，成功后吊销所有现有会话并为当前客户端签发新令牌
+func (s *userService) ChangePassword(id int, req *model.ChangePasswordRequest) (*model.TokenPair, error) {
+	user, err := s.userRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// 验证旧密码
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.OldPassword))
+	if err != nil {
+		return nil, errors.New("old password is incorrect")
+	}
+
+	if err := s.setPassword(user, req.NewPassword); err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.tokenService.Issue(user, req.MFA, req.Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue tokens: %w", err)
 	}
 
-	return userResponses, count, nil
+	return tokens, nil
+}
+
+// PurgeExpiredResets 清理过期或已使用的密码重置令牌
+func (s *userService) PurgeExpiredResets() error {
+	return s.resetRepo.DeleteExpired(time.Now())
 }
 
-// generateJWT 生成JWT令牌
-func generateJWT(userID int) (string, error) {
-	jwtSecret := viper.GetString("app.jwt_secret")
-	expiration := viper.GetInt("app.jwt_expiration")
+// RequestPasswordReset 发送密码重置邮件（邮箱不存在时静默返回，避免泄露账号信息）
+func (s *userService) RequestPasswordReset(req *model.ForgotPasswordRequest) error {
+	user, err := s.userRepo.GetByEmail(req.Email)
+	if err != nil {
+		logrus.Debugf("Password reset requested for unknown email %s", req.Email)
+		return nil
+	}
+
+	// 新令牌签发后旧令牌作废
+	if err := s.resetRepo.InvalidateByUserID(user.ID); err != nil {
+		return err
+	}
+
+	rawToken, err := randomToken(32)
+	if err != nil {
+		return fmt.Errorf("failed to generate reset token: %w", err)
+	}
+
+	expiration := time.Minute * time.Duration(viper.GetInt("app.password_reset_expiration"))
+	reset := &model.PasswordReset{
+		UserID:    user.ID,
+		TokenHash: hashToken(rawToken),
+		ExpiresAt: time.Now().Add(expiration),
+	}
+
+	if err := s.resetRepo.Create(reset); err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/reset-password?token=%s", viper.GetString("app.base_url"), rawToken)
+	return s.mailer.Send(&mailer.Message{
+		To:      []string{user.Email},
+		Subject: "重置您的密码",
+		Body: fmt.Sprintf("%s，您好：\n\n我们收到了重置您账号密码的请求。请在%d分钟内访问以下链接设置新密码：\n\n%s\n\n如果这不是您本人的操作，请忽略此邮件。\n",
+			user.Username, int(expiration.Minutes()), link),
+	})
+}
+
+// ResetPassword 使用一次性令牌重置密码
+func (s *userService) ResetPassword(req *model.ResetPasswordRequest) error {
+	reset, err := s.resetRepo.GetByTokenHash(hashToken(req.Token))
+	if err != nil || reset.UsedAt != nil || time.Now().After(reset.ExpiresAt) {
+		return errors.New("invalid or expired reset token")
+	}
+
+	// 并发请求时只有一个能成功使用令牌
+	marked, err := s.resetRepo.MarkUsed(reset.ID)
+	if err != nil {
+		return err
+	}
+	if !marked {
+		return errors.New("invalid or expired reset token")
+	}
 
-	claims := jwt.MapClaims{
-		"user_id": userID,
-		"exp":     time.Now().Add(time.Hour * time.Duration(expiration)).Unix(),
-		"iat":     time.Now().Unix(),
+	user, err := s.userRepo.GetByID(reset.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(jwtSecret))
+	return s.setPassword(user, req.NewPassword)
+}
+
+// setPassword 更新密码并吊销用户的所有会话
+func (s *userService) setPassword(user *model.User, password string) error {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
-		return "", err
+		return fmt.Errorf("failed to hash password: %w", err)
 	}
 
-	return tokenString, nil
-}
\ No newline at end of file
+	user.Password = string(hashedPassword)
+	if err := s.userRepo.Update(user); err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	if err := s.tokenService.RevokeAllForUser(user.ID); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateRole 修改用户角色
+func (s *userService) UpdateRole(id int, role model.Role) (*model.UserResponse, error) {
+	if !role.IsValid() {
+		return nil, fmt.Errorf("invalid role: %s", role)
+	}
+
+	user, err := s.userRepo.GetByID(id)
+	if err != nil || user.IsDeleted() {
+		return nil, ErrUserNotFound
+	}
+
+	if err := s.userRepo.UpdateRole(id, role); err != nil {
+		return nil, fmt.Errorf("failed to update user role: %w", err)
+	}
+	user.Role = role
+
+	return userResponse(user), nil
+}
+
+// Unlock 解除账号的登录锁定
+func (s *userService) Unlock(id int) error {
+	user, err := s.userRepo.GetByID(id)
+	if err != nil || user.IsDeleted() {
+		return ErrUserNotFound
+	}
+
+	return s.throttle.Unlock(user.Username)
+}
+
+// Delete 注销用户，deletedBy为操作人（本人注销时等于id）。只有本人注销的账号可以在宽限期内通过登录恢复
+func (s *userService) Delete(id, deletedBy int) (time.Time, error) {
+	now := time.Now()
+	deleted, err := s.userRepo.SoftDelete(id, deletedBy, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !deleted {
+		return time.Time{}, ErrUserNotFound
+	}
+
+	// 注销后立即退出所有设备，宽限期内重新登录即可恢复账号
+	if err := s.tokenService.RevokeAllForUser(id); err != nil {
+		return time.Time{}, err
+	}
+
+	return now.Add(deletionGracePeriod()), nil
+}
+
+// AnonymizeDeleted 匿名化超过宽限期的注销账号，返回成功处理的数量。
+// 单个账号失败时继续处理其余账号，所有失败合并为一个错误返回
+func (s *userService) AnonymizeDeleted() (int, error) {
+	policy := model.DeletedPostsPolicy(viper.GetString("account_deletion.posts_policy"))
+	reassignTo := viper.GetInt("account_deletion.reassign_to")
+
+	switch policy {
+	case "":
+		policy = model.DeletedPostsKeep
+	case model.DeletedPostsKeep, model.DeletedPostsDelete:
+	case model.DeletedPostsReassign:
+		if reassignTo == 0 {
+			return 0, errors.New("account_deletion.reassign_to is required when posts_policy is reassign")
+		}
+	default:
+		return 0, fmt.Errorf("unknown account_deletion.posts_policy: %s", policy)
+	}
+
+	users, err := s.userRepo.ListDeletedBefore(time.Now().Add(-deletionGracePeriod()), anonymizeBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	var errs []error
+	for _, user := range users {
+		placeholder := fmt.Sprintf("%s%d", anonymizedUsernamePrefix, user.ID)
+		email := placeholder + "@deleted.invalid"
+
+		if err := s.userRepo.Anonymize(user.ID, placeholder, email, policy, reassignTo); err != nil {
+			errs = append(errs, fmt.Errorf("user %d: %w", user.ID, err))
+			continue
+		}
+		count++
+	}
+
+	return count, errors.Join(errs...)
+}
+
+// List 分页获取用户列表
+func (s *userService) List(query *model.PageQuery) (*model.Page[model.UserResponse], error) {
+	normalizePage(query, 10)
+
+	page, err := s.userRepo.List(query)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fillTotal(page, query, s.userRepo.Count); err != nil {
+		return nil, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	userResponses := make([]model.UserResponse, len(page.Items))
+	for i, user := range page.Items {
+		userResponses[i] = user.ToResponse()
+	}
+
+	return model.MapPage(page, userResponses), nil
+}
+
+// deletionGracePeriod 注销账号的宽限期
+func deletionGracePeriod() time.Duration {
+	return time.Hour * 24 * time.Duration(viper.GetInt("account_deletion.grace_period"))
+}
+
+// loginFailed 记录登录失败并返回统一的错误信息
+func (s *userService) loginFailed(req *model.LoginRequest) error {
+	if err := s.throttle.RecordFailure(req.Username, req.Client.IP); err != nil {
+		return err
+	}
+
+	return errors.New("invalid username or password")
+}
+
+// userResponse 构建用户响应
+func userResponse(user *model.User) *model.UserResponse {
+	response := user.ToResponse()
+	return &response
+}
@@ -0,0 +1,5 @@
+-- 为用户添加角色
+ALTER TABLE users ADD COLUMN role ENUM('admin', 'editor', 'author', 'reader') NOT NULL DEFAULT 'author' AFTER password;
+
+-- 初始数据中的admin用户设为管理员
+UPDATE users SET role = 'admin' WHERE username = 'admin';
@@ -0,0 +1,7 @@
+-- 为用户添加邮箱验证字段
+ALTER TABLE users
+    ADD COLUMN email_verified_at TIMESTAMP NULL DEFAULT NULL AFTER email,
+    ADD COLUMN verification_sent_at TIMESTAMP NULL DEFAULT NULL AFTER email_verified_at;
+
+-- 已有用户视为已验证
+UPDATE users SET email_verified_at = CURRENT_TIMESTAMP;
@@ -0,0 +1,15 @@
+-- 创建用户钱包地址表（地址统一以小写存储）
+CREATE TABLE IF NOT EXISTS user_wallets (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    address CHAR(42) NOT NULL UNIQUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- 创建以太坊登录一次性随机数表
+CREATE TABLE IF NOT EXISTS siwe_nonces (
+    nonce VARCHAR(64) PRIMARY KEY,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP NULL DEFAULT NULL
+);
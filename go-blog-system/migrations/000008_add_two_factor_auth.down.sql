@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS recovery_codes;
+
+ALTER TABLE sessions DROP COLUMN mfa;
+
+ALTER TABLE users
+    DROP COLUMN totp_last_step,
+    DROP COLUMN totp_enabled_at,
+    DROP COLUMN totp_secret;
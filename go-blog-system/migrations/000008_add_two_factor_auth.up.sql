@@ -0,0 +1,19 @@
+-- 为用户添加TOTP两步验证字段
+ALTER TABLE users
+    ADD COLUMN totp_secret VARCHAR(64) NULL DEFAULT NULL AFTER role,
+    ADD COLUMN totp_enabled_at TIMESTAMP NULL DEFAULT NULL AFTER totp_secret,
+    ADD COLUMN totp_last_step BIGINT NOT NULL DEFAULT 0 AFTER totp_enabled_at;
+
+-- 记录会话是否通过了两步验证（刷新令牌时保持）
+ALTER TABLE sessions ADD COLUMN mfa TINYINT(1) NOT NULL DEFAULT 0 AFTER user_id;
+
+-- 创建恢复码表（只保存恢复码的SHA-256哈希）
+CREATE TABLE IF NOT EXISTS recovery_codes (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    code_hash CHAR(64) NOT NULL,
+    used_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_recovery_codes_user_code (user_id, code_hash),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
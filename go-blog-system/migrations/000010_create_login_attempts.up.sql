@@ -0,0 +1,8 @@
+-- 创建登录失败记录表（多实例部署时共享登录限流状态）
+CREATE TABLE IF NOT EXISTS login_attempts (
+    attempt_key VARCHAR(191) PRIMARY KEY,
+    failures INT NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMP NOT NULL,
+    blocked_until TIMESTAMP NULL DEFAULT NULL,
+    INDEX idx_login_attempts_last_failure_at (last_failure_at)
+);
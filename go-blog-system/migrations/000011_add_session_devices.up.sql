@@ -0,0 +1,5 @@
+-- 为会话记录设备信息和最近活跃时间
+ALTER TABLE sessions
+    ADD COLUMN user_agent VARCHAR(255) NOT NULL DEFAULT '' AFTER mfa,
+    ADD COLUMN ip VARCHAR(45) NOT NULL DEFAULT '' AFTER user_agent,
+    ADD COLUMN last_seen_at TIMESTAMP NULL DEFAULT NULL AFTER ip;
@@ -0,0 +1,12 @@
+ALTER TABLE comments
+    DROP FOREIGN KEY fk_comments_user_id,
+    ADD CONSTRAINT comments_ibfk_1 FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE posts
+    DROP FOREIGN KEY fk_posts_user_id,
+    ADD CONSTRAINT posts_ibfk_1 FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE users
+    DROP INDEX idx_users_deleted_at,
+    DROP COLUMN anonymized_at,
+    DROP COLUMN deleted_at;
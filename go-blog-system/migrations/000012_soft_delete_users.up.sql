@@ -0,0 +1,14 @@
+-- 为用户添加软删除和匿名化字段
+ALTER TABLE users
+    ADD COLUMN deleted_at TIMESTAMP NULL DEFAULT NULL AFTER bio,
+    ADD COLUMN anonymized_at TIMESTAMP NULL DEFAULT NULL AFTER deleted_at,
+    ADD INDEX idx_users_deleted_at (deleted_at);
+
+-- 用户不再被物理删除，文章和评论的外键改为RESTRICT，防止误删级联清空他人的评论串
+ALTER TABLE posts
+    DROP FOREIGN KEY posts_ibfk_1,
+    ADD CONSTRAINT fk_posts_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT;
+
+ALTER TABLE comments
+    DROP FOREIGN KEY comments_ibfk_1,
+    ADD CONSTRAINT fk_comments_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT;
@@ -0,0 +1,13 @@
+-- 创建个人数据导出表
+CREATE TABLE IF NOT EXISTS data_exports (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    status ENUM('pending', 'processing', 'completed', 'failed') NOT NULL DEFAULT 'pending',
+    file_path VARCHAR(255) NULL DEFAULT NULL,
+    error VARCHAR(255) NULL DEFAULT NULL,
+    expires_at TIMESTAMP NULL DEFAULT NULL,
+    completed_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_data_exports_user_id (user_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
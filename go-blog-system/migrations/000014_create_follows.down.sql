@@ -0,0 +1,5 @@
+ALTER TABLE post_tags DROP INDEX idx_post_tags_tag_id;
+ALTER TABLE posts DROP INDEX idx_posts_user_status_created;
+
+DROP TABLE IF EXISTS tag_follows;
+DROP TABLE IF EXISTS user_follows;
@@ -0,0 +1,25 @@
+-- 创建用户关注表
+CREATE TABLE IF NOT EXISTS user_follows (
+    follower_id INT NOT NULL,
+    followee_id INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (follower_id, followee_id),
+    INDEX idx_user_follows_followee_id (followee_id),
+    FOREIGN KEY (follower_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (followee_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- 创建标签关注表
+CREATE TABLE IF NOT EXISTS tag_follows (
+    user_id INT NOT NULL,
+    tag_id INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, tag_id),
+    INDEX idx_tag_follows_tag_id (tag_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE
+);
+
+-- 动态流按作者和发布时间倒序读取已发布文章
+ALTER TABLE posts ADD INDEX idx_posts_user_status_created (user_id, status, created_at, id);
+ALTER TABLE post_tags ADD INDEX idx_post_tags_tag_id (tag_id, post_id);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS post_slug_redirects;
+
+ALTER TABLE posts DROP INDEX idx_posts_slug;
+ALTER TABLE posts DROP COLUMN slug;
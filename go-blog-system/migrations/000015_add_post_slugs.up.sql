@@ -0,0 +1,12 @@
+-- 为文章添加slug（已有文章由服务启动时根据标题回填）
+ALTER TABLE posts ADD COLUMN slug VARCHAR(191) NULL AFTER title;
+ALTER TABLE posts ADD UNIQUE INDEX idx_posts_slug (slug);
+
+-- 创建文章旧slug重定向表
+CREATE TABLE IF NOT EXISTS post_slug_redirects (
+    slug VARCHAR(191) PRIMARY KEY,
+    post_id INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_post_slug_redirects_post_id (post_id),
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
+);
@@ -0,0 +1,5 @@
+ALTER TABLE posts
+    DROP COLUMN render_version,
+    DROP COLUMN toc,
+    DROP COLUMN content_html,
+    DROP COLUMN content_format;
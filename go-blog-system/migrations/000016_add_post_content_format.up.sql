@@ -0,0 +1,8 @@
+-- 文章内容格式和渲染缓存；已有文章按纯文本处理，新文章默认使用Markdown
+ALTER TABLE posts
+    ADD COLUMN content_format ENUM('markdown', 'html', 'plain') NOT NULL DEFAULT 'plain' AFTER content,
+    ADD COLUMN content_html MEDIUMTEXT NULL AFTER content_format,
+    ADD COLUMN toc TEXT NULL AFTER content_html,
+    ADD COLUMN render_version INT NOT NULL DEFAULT 0 AFTER toc;
+
+ALTER TABLE posts ALTER COLUMN content_format SET DEFAULT 'markdown';
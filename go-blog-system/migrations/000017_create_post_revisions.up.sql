@@ -0,0 +1,15 @@
+-- 创建文章修订历史表
+CREATE TABLE IF NOT EXISTS post_revisions (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    post_id INT NOT NULL,
+    revision INT NOT NULL,
+    user_id INT NULL,
+    title VARCHAR(255) NOT NULL,
+    content MEDIUMTEXT NOT NULL,
+    content_format ENUM('markdown', 'html', 'plain') NOT NULL,
+    status ENUM('draft', 'published', 'archived') NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_post_revisions_post_revision (post_id, revision),
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL
+);
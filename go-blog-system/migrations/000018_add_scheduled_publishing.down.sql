@@ -0,0 +1,14 @@
+ALTER TABLE posts
+    DROP INDEX idx_posts_status_published,
+    DROP INDEX idx_posts_user_status_published,
+    ADD INDEX idx_posts_user_status_created (user_id, status, created_at, id);
+
+UPDATE posts SET status = 'draft' WHERE status = 'scheduled';
+UPDATE post_revisions SET status = 'draft' WHERE status = 'scheduled';
+
+ALTER TABLE post_revisions
+    MODIFY COLUMN status ENUM('draft', 'published', 'archived') NOT NULL;
+
+ALTER TABLE posts
+    DROP COLUMN published_at,
+    MODIFY COLUMN status ENUM('draft', 'published', 'archived') DEFAULT 'draft';
@@ -0,0 +1,16 @@
+-- 文章增加定时发布状态和发布时间
+ALTER TABLE posts
+    MODIFY COLUMN status ENUM('draft', 'scheduled', 'published', 'archived') DEFAULT 'draft',
+    ADD COLUMN published_at TIMESTAMP NULL DEFAULT NULL AFTER status;
+
+ALTER TABLE post_revisions
+    MODIFY COLUMN status ENUM('draft', 'scheduled', 'published', 'archived') NOT NULL;
+
+-- 已发布的文章以创建时间作为发布时间
+UPDATE posts SET published_at = created_at, updated_at = updated_at WHERE status IN ('published', 'archived');
+
+-- 文章列表和动态流按发布时间排序，定时任务按状态和发布时间查找到期文章
+ALTER TABLE posts
+    DROP INDEX idx_posts_user_status_created,
+    ADD INDEX idx_posts_user_status_published (user_id, status, published_at, id),
+    ADD INDEX idx_posts_status_published (status, published_at);
@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS post_status_transitions;
+
+UPDATE posts SET status = 'draft' WHERE status IN ('in_review', 'approved');
+UPDATE post_revisions SET status = 'draft' WHERE status IN ('in_review', 'approved');
+
+ALTER TABLE post_revisions
+    MODIFY COLUMN status ENUM('draft', 'scheduled', 'published', 'archived') NOT NULL;
+
+ALTER TABLE posts
+    MODIFY COLUMN status ENUM('draft', 'scheduled', 'published', 'archived') DEFAULT 'draft';
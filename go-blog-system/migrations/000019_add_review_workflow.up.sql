@@ -0,0 +1,20 @@
+-- 文章增加审核流程状态
+ALTER TABLE posts
+    MODIFY COLUMN status ENUM('draft', 'in_review', 'approved', 'scheduled', 'published', 'archived') DEFAULT 'draft';
+
+ALTER TABLE post_revisions
+    MODIFY COLUMN status ENUM('draft', 'in_review', 'approved', 'scheduled', 'published', 'archived') NOT NULL;
+
+-- 创建文章状态变更历史表
+CREATE TABLE IF NOT EXISTS post_status_transitions (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    post_id INT NOT NULL,
+    from_status ENUM('draft', 'in_review', 'approved', 'scheduled', 'published', 'archived') NULL,
+    to_status ENUM('draft', 'in_review', 'approved', 'scheduled', 'published', 'archived') NOT NULL,
+    user_id INT NULL,
+    comment TEXT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_post_status_transitions_post (post_id, to_status, created_at),
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL
+);
@@ -0,0 +1,6 @@
+-- 文章全文索引，使用ngram分词支持中文（需要MySQL 5.7.6及以上）
+-- InnoDB每次只能创建一个全文索引
+ALTER TABLE posts ADD FULLTEXT INDEX ft_posts_title_content (title, content) WITH PARSER ngram;
+
+-- 单独的标题索引用于提高标题命中的权重
+ALTER TABLE posts ADD FULLTEXT INDEX ft_posts_title (title) WITH PARSER ngram;
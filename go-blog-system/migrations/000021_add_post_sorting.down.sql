@@ -0,0 +1,6 @@
+ALTER TABLE posts
+    DROP INDEX idx_posts_title,
+    DROP INDEX idx_posts_updated,
+    DROP INDEX idx_posts_comment_count,
+    DROP INDEX idx_posts_view_count,
+    DROP COLUMN comment_count;
@@ -0,0 +1,13 @@
+-- 文章评论数，随评论的创建和删除原子更新，用于排序和过滤
+ALTER TABLE posts ADD COLUMN comment_count INT NOT NULL DEFAULT 0 AFTER view_count;
+
+UPDATE posts p
+SET p.comment_count = (SELECT COUNT(*) FROM comments c WHERE c.post_id = p.id),
+    p.updated_at = p.updated_at;
+
+-- 文章列表的排序方式
+ALTER TABLE posts
+    ADD INDEX idx_posts_view_count (view_count, id),
+    ADD INDEX idx_posts_comment_count (comment_count, id),
+    ADD INDEX idx_posts_updated (updated_at, id),
+    ADD INDEX idx_posts_title (title, id);
@@ -0,0 +1,5 @@
+ALTER TABLE posts
+    DROP COLUMN reading_time,
+    DROP COLUMN word_count,
+    DROP COLUMN excerpt,
+    DROP COLUMN summary;
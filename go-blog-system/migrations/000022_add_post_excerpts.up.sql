@@ -0,0 +1,9 @@
+-- 作者填写的摘要，以及根据正文生成的摘要、字数和阅读时间
+ALTER TABLE posts
+    ADD COLUMN summary VARCHAR(500) NULL DEFAULT NULL AFTER content,
+    ADD COLUMN excerpt VARCHAR(1000) NOT NULL DEFAULT '' AFTER summary,
+    ADD COLUMN word_count INT NOT NULL DEFAULT 0 AFTER excerpt,
+    ADD COLUMN reading_time INT NOT NULL DEFAULT 0 AFTER word_count;
+
+-- 摘要随渲染结果生成，服务启动时重新渲染已有文章
+UPDATE posts SET render_version = 0, updated_at = updated_at;
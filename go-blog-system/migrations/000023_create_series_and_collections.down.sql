@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS collection_posts;
+DROP TABLE IF EXISTS collections;
+DROP TABLE IF EXISTS series_posts;
+DROP TABLE IF EXISTS series;
@@ -0,0 +1,49 @@
+-- 创建系列表
+CREATE TABLE IF NOT EXISTS series (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_series_user_id (user_id, created_at),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- 系列中的文章，一篇文章最多属于一个系列
+CREATE TABLE IF NOT EXISTS series_posts (
+    series_id INT NOT NULL,
+    post_id INT NOT NULL,
+    position INT NOT NULL,
+    PRIMARY KEY (series_id, post_id),
+    UNIQUE KEY uk_series_posts_post_id (post_id),
+    INDEX idx_series_posts_position (series_id, position),
+    FOREIGN KEY (series_id) REFERENCES series(id) ON DELETE CASCADE,
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
+);
+
+-- 创建合集表
+CREATE TABLE IF NOT EXISTS collections (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_collections_user_id (user_id, created_at),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- 合集中的文章
+CREATE TABLE IF NOT EXISTS collection_posts (
+    collection_id INT NOT NULL,
+    post_id INT NOT NULL,
+    position INT NOT NULL,
+    note VARCHAR(500) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (collection_id, post_id),
+    INDEX idx_collection_posts_position (collection_id, position),
+    INDEX idx_collection_posts_post_id (post_id),
+    FOREIGN KEY (collection_id) REFERENCES collections(id) ON DELETE CASCADE,
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
+);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS bookmarks;
+DROP TABLE IF EXISTS comment_reactions;
+DROP TABLE IF EXISTS post_reactions;
+
+ALTER TABLE comments DROP COLUMN reactions;
+ALTER TABLE posts DROP COLUMN reactions;
@@ -0,0 +1,39 @@
+-- 文章和评论的表情回应数量（JSON，如 {"like": 3}），随回应的添加和取消原子更新
+ALTER TABLE posts ADD COLUMN reactions JSON NULL AFTER comment_count;
+ALTER TABLE comments ADD COLUMN reactions JSON NULL AFTER parent_id;
+
+-- 创建文章表情回应表，同一用户对同一篇文章的每种回应只记录一次
+CREATE TABLE IF NOT EXISTS post_reactions (
+    post_id INT NOT NULL,
+    user_id INT NOT NULL,
+    reaction VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (post_id, user_id, reaction),
+    INDEX idx_post_reactions_user_id (user_id),
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- 创建评论表情回应表
+CREATE TABLE IF NOT EXISTS comment_reactions (
+    comment_id INT NOT NULL,
+    user_id INT NOT NULL,
+    reaction VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (comment_id, user_id, reaction),
+    INDEX idx_comment_reactions_user_id (user_id),
+    FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- 创建收藏表
+CREATE TABLE IF NOT EXISTS bookmarks (
+    user_id INT NOT NULL,
+    post_id INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, post_id),
+    INDEX idx_bookmarks_user_created (user_id, created_at, post_id),
+    INDEX idx_bookmarks_post_id (post_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
+);
@@ -0,0 +1 @@
+ALTER TABLE data_exports DROP COLUMN instance_id;
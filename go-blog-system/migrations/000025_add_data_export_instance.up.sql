@@ -0,0 +1,2 @@
+-- 记录生成导出文件的实例，服务重启时只清理本实例中断的任务
+ALTER TABLE data_exports ADD COLUMN instance_id VARCHAR(64) NOT NULL DEFAULT '' AFTER status;
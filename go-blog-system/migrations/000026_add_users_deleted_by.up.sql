@@ -0,0 +1,3 @@
+-- 记录注销操作人，只有本人注销的账号可以在宽限期内通过登录恢复。
+-- 迁移前注销的账号无法确定操作人，保持为NULL，不能自助恢复
+ALTER TABLE users ADD COLUMN deleted_by INT NULL DEFAULT NULL AFTER deleted_at;
@@ -4,7 +4,7 @@ import (
 	"fmt"
 	"time"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
@@ -32,7 +32,19 @@ func NewMySQLConnection(config *Config) (*sqlx.DB, error) {
 		config.Params,
 	)
 
-	db, err := sqlx.Connect("mysql", dsn)
+	// 显式使用UTC：驱动按UTC读写时间，会话时区固定为+00:00，不依赖服务器和DSN中的loc配置
+	cfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse database dsn: %w", err)
+	}
+	cfg.ParseTime = true
+	cfg.Loc = time.UTC
+	if cfg.Params == nil {
+		cfg.Params = make(map[string]string)
+	}
+	cfg.Params["time_zone"] = "'+00:00'"
+
+	db, err := sqlx.Connect("mysql", cfg.FormatDSN())
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
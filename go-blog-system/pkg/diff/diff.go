@@ -0,0 +1,291 @@
+package diff
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Op 编辑操作类型
+type Op string
+
+const (
+	OpEqual  Op = "equal"
+	OpInsert Op = "insert"
+	OpDelete Op = "delete"
+)
+
+// ContextLines 统一格式差异中每个变更块前后保留的上下文行数
+const ContextLines = 3
+
+// MaxTokens 逐个比较的行数或词数上限，限制大文本比较的耗时
+const MaxTokens = 10000
+
+// Segment 差异片段
+type Segment struct {
+	Op   Op     `json:"op"`
+	Text string `json:"text"`
+}
+
+// wordPattern 词级切分：每个汉字单独成词，字母数字连续成词，空白和标点各自成词
+var wordPattern = regexp.MustCompile(`\p{Han}|[\p{L}\p{N}_]+|\s+|.`)
+
+// Unified 生成按行比较的统一格式差异（与 diff -u 输出一致），没有变化时只包含文件头
+func Unified(from, to, fromLabel, toLabel string) string {
+	edits := compare(splitLines(from), splitLines(to))
+
+	// 每个编辑位置之前旧文本和新文本已经过的行数
+	aPos := make([]int, len(edits)+1)
+	bPos := make([]int, len(edits)+1)
+	for i, e := range edits {
+		aPos[i+1], bPos[i+1] = aPos[i], bPos[i]
+		if e.Op != OpInsert {
+			aPos[i+1]++
+		}
+		if e.Op != OpDelete {
+			bPos[i+1]++
+		}
+	}
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "--- %s\n+++ %s\n", fromLabel, toLabel)
+
+	prevEnd := 0
+	for start := 0; start < len(edits); {
+		for start < len(edits) && edits[start].Op == OpEqual {
+			start++
+		}
+		if start == len(edits) {
+			break
+		}
+
+		// 间隔不超过两倍上下文的变更合并到同一个块
+		end := start
+		for i := start; i < len(edits); i++ {
+			if edits[i].Op != OpEqual {
+				end = i + 1
+			} else if i-end >= 2*ContextLines {
+				break
+			}
+		}
+
+		hunkStart := max(prevEnd, start-ContextLines)
+		hunkEnd := min(len(edits), end+ContextLines)
+
+		fmt.Fprintf(&buf, "@@ -%s +%s @@\n",
+			hunkRange(aPos[hunkStart], aPos[hunkEnd]-aPos[hunkStart]),
+			hunkRange(bPos[hunkStart], bPos[hunkEnd]-bPos[hunkStart]),
+		)
+		for _, e := range edits[hunkStart:hunkEnd] {
+			switch e.Op {
+			case OpEqual:
+				buf.WriteByte(' ')
+			case OpInsert:
+				buf.WriteByte('+')
+			case OpDelete:
+				buf.WriteByte('-')
+			}
+			buf.WriteString(e.Text)
+			buf.WriteByte('\n')
+		}
+
+		prevEnd, start = hunkEnd, hunkEnd
+	}
+
+	return buf.String()
+}
+
+// Words 按词比较两段文本，相邻的同类操作合并为一个片段
+func Words(from, to string) []Segment {
+	edits := compare(wordPattern.FindAllString(from, -1), wordPattern.FindAllString(to, -1))
+
+	var segments []Segment
+	for _, e := range edits {
+		if n := len(segments); n > 0 && segments[n-1].Op == e.Op {
+			segments[n-1].Text += e.Text
+			continue
+		}
+		segments = append(segments, e)
+	}
+
+	return segments
+}
+
+// hunkRange 格式化变更块的行范围，空范围按 diff -u 的约定指向前一行
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// splitLines 按行切分文本，忽略结尾的换行
+func splitLines(s string) []string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	if s == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// compare 计算从a到b的最短编辑序列。去掉相同的开头和结尾后，剩余部分超过MaxTokens时
+// 不再逐个比较，整段作为删除加插入
+func compare(a, b []string) []Segment {
+	prefix, suffix := commonAffix(a, b)
+
+	edits := appendEdits(nil, OpEqual, a[:prefix])
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if len(midA)+len(midB) > MaxTokens {
+		edits = appendEdits(edits, OpDelete, midA)
+		edits = appendEdits(edits, OpInsert, midB)
+	} else {
+		edits = diffMiddle(midA, midB, edits)
+	}
+
+	return appendEdits(edits, OpEqual, a[len(a)-suffix:])
+}
+
+// diffRange 比较a和b并把编辑序列追加到edits
+func diffRange(a, b []string, edits []Segment) []Segment {
+	prefix, suffix := commonAffix(a, b)
+
+	edits = appendEdits(edits, OpEqual, a[:prefix])
+	edits = diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix], edits)
+
+	return appendEdits(edits, OpEqual, a[len(a)-suffix:])
+}
+
+// diffMiddle 比较开头和结尾都不相同的a和b：在中间蛇处一分为二后分别递归比较
+func diffMiddle(a, b []string, edits []Segment) []Segment {
+	if len(a) == 0 {
+		return appendEdits(edits, OpInsert, b)
+	}
+	if len(b) == 0 {
+		return appendEdits(edits, OpDelete, a)
+	}
+
+	x, y := bisect(a, b)
+	if (x == 0 && y == 0) || (x == len(a) && y == len(b)) {
+		// 没有任何相同的部分
+		edits = appendEdits(edits, OpDelete, a)
+		return appendEdits(edits, OpInsert, b)
+	}
+
+	edits = diffRange(a[:x], b[:y], edits)
+
+	return diffRange(a[x:], b[y:], edits)
+}
+
+// bisect 使用线性空间的Myers算法同时从两端搜索，返回正反两条路径相遇处的分割点。
+// 每轮只保存各对角线能到达的最远位置，内存为O(N+M)
+func bisect(a, b []string) (int, int) {
+	n, m := len(a), len(b)
+	maxD := (n + m + 1) / 2
+	offset := maxD
+	size := 2*maxD + 2
+
+	// forward[offset+k] 和 backward[offset+k] 分别为从起点和终点出发在对角线k上到达的最远位置，-1表示尚未到达
+	forward := make([]int, size)
+	backward := make([]int, size)
+	for i := range forward {
+		forward[i], backward[i] = -1, -1
+	}
+	forward[offset+1], backward[offset+1] = 0, 0
+
+	delta := n - m
+	// 长度差为奇数时正向搜索检查相遇，否则反向搜索检查相遇
+	front := delta%2 != 0
+
+	// 超出编辑图范围的对角线不再搜索
+	var fStart, fEnd, bStart, bEnd int
+	for d := 0; d < maxD; d++ {
+		for k := -d + fStart; k <= d-fEnd; k += 2 {
+			i := offset + k
+			var x int
+			if k == -d || (k != d && forward[i-1] < forward[i+1]) {
+				x = forward[i+1]
+			} else {
+				x = forward[i-1] + 1
+			}
+
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			forward[i] = x
+
+			switch {
+			case x > n:
+				fEnd += 2
+			case y > m:
+				fStart += 2
+			case front:
+				j := offset + delta - k
+				if j >= 0 && j < size && backward[j] != -1 && x >= n-backward[j] {
+					return x, y
+				}
+			}
+		}
+
+		for k := -d + bStart; k <= d-bEnd; k += 2 {
+			i := offset + k
+			var x int
+			if k == -d || (k != d && backward[i-1] < backward[i+1]) {
+				x = backward[i+1]
+			} else {
+				x = backward[i-1] + 1
+			}
+
+			y := x - k
+			for x < n && y < m && a[n-x-1] == b[m-y-1] {
+				x++
+				y++
+			}
+			backward[i] = x
+
+			switch {
+			case x > n:
+				bEnd += 2
+			case y > m:
+				bStart += 2
+			case !front:
+				j := offset + delta - k
+				if j >= 0 && j < size && forward[j] != -1 && forward[j] >= n-x {
+					return forward[j], forward[j] - (j - offset)
+				}
+			}
+		}
+	}
+
+	return 0, 0
+}
+
+// commonAffix 返回a和b相同的开头和结尾的长度，两者不重叠
+func commonAffix(a, b []string) (int, int) {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	return prefix, suffix
+}
+
+// appendEdits 将tokens逐个作为op操作追加到edits
+func appendEdits(edits []Segment, op Op, tokens []string) []Segment {
+	for _, t := range tokens {
+		edits = append(edits, Segment{Op: op, Text: t})
+	}
+
+	return edits
+}
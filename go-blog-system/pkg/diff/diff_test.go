@@ -0,0 +1,210 @@
+package diff
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWords(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		want     []Segment
+	}{
+		{
+			name: "identical",
+			from: "hello world",
+			to:   "hello world",
+			want: []Segment{{Op: OpEqual, Text: "hello world"}},
+		},
+		{
+			name: "replace word",
+			from: "the quick fox",
+			to:   "the slow fox",
+			want: []Segment{
+				{Op: OpEqual, Text: "the "},
+				{Op: OpDelete, Text: "quick"},
+				{Op: OpInsert, Text: "slow"},
+				{Op: OpEqual, Text: " fox"},
+			},
+		},
+		{
+			name: "han characters",
+			from: "今天天气很好",
+			to:   "今天天气不好",
+			want: []Segment{
+				{Op: OpEqual, Text: "今天天气"},
+				{Op: OpDelete, Text: "很"},
+				{Op: OpInsert, Text: "不"},
+				{Op: OpEqual, Text: "好"},
+			},
+		},
+		{
+			name: "from empty",
+			from: "",
+			to:   "new text",
+			want: []Segment{{Op: OpInsert, Text: "new text"}},
+		},
+		{
+			name: "to empty",
+			from: "old text",
+			to:   "",
+			want: []Segment{{Op: OpDelete, Text: "old text"}},
+		},
+		{
+			name: "both empty",
+			from: "",
+			to:   "",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Words(tt.from, tt.to)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Words(%q, %q) = %#v, want %#v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		want     string
+	}{
+		{
+			name: "no changes",
+			from: "a\nb\n",
+			to:   "a\nb\n",
+			want: "--- old\n+++ new\n",
+		},
+		{
+			name: "change in the middle",
+			from: "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			to:   "1\n2\n3\n4\nfive\n6\n7\n8\n9\n",
+			want: "--- old\n+++ new\n" +
+				"@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			name: "separate hunks",
+			from: "a\n1\n2\n3\n4\n5\n6\n7\n8\nb\n",
+			to:   "A\n1\n2\n3\n4\n5\n6\n7\n8\nB\n",
+			want: "--- old\n+++ new\n" +
+				"@@ -1,4 +1,4 @@\n-a\n+A\n 1\n 2\n 3\n" +
+				"@@ -7,4 +7,4 @@\n 6\n 7\n 8\n-b\n+B\n",
+		},
+		{
+			name: "insert into empty",
+			from: "",
+			to:   "x\ny\n",
+			want: "--- old\n+++ new\n@@ -0,0 +1,2 @@\n+x\n+y\n",
+		},
+		{
+			name: "delete everything",
+			from: "x\n",
+			to:   "",
+			want: "--- old\n+++ new\n@@ -1 +0,0 @@\n-x\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Unified(tt.from, tt.to, "old", "new")
+			if got != tt.want {
+				t.Errorf("Unified() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCompareMinimal 随机输入下编辑序列应能还原两端文本，且编辑数与最长公共子序列一致
+func TestCompareMinimal(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	alphabet := []string{"a", "b", "c", "d"}
+	random := func() []string {
+		tokens := make([]string, rng.Intn(30))
+		for i := range tokens {
+			tokens[i] = alphabet[rng.Intn(len(alphabet))]
+		}
+		return tokens
+	}
+
+	for i := 0; i < 2000; i++ {
+		a, b := random(), random()
+		edits := compare(a, b)
+
+		var gotA, gotB []string
+		changes := 0
+		for _, e := range edits {
+			if e.Op != OpInsert {
+				gotA = append(gotA, e.Text)
+			}
+			if e.Op != OpDelete {
+				gotB = append(gotB, e.Text)
+			}
+			if e.Op != OpEqual {
+				changes++
+			}
+		}
+
+		if strings.Join(gotA, "") != strings.Join(a, "") || strings.Join(gotB, "") != strings.Join(b, "") {
+			t.Fatalf("compare(%v, %v) does not reproduce its inputs: %v", a, b, edits)
+		}
+		if want := len(a) + len(b) - 2*lcsLength(a, b); changes != want {
+			t.Fatalf("compare(%v, %v) made %d changes, want %d", a, b, changes, want)
+		}
+	}
+}
+
+func TestCompareMaxTokens(t *testing.T) {
+	a := make([]string, MaxTokens)
+	b := make([]string, MaxTokens)
+	for i := range a {
+		a[i] = "a"
+		b[i] = "b"
+	}
+	a = append([]string{"same"}, append(a, "end")...)
+	b = append([]string{"same"}, append(b, "end")...)
+
+	edits := compare(a, b)
+	if len(edits) != 2*MaxTokens+2 {
+		t.Fatalf("got %d edits, want %d", len(edits), 2*MaxTokens+2)
+	}
+	if edits[0] != (Segment{Op: OpEqual, Text: "same"}) || edits[len(edits)-1] != (Segment{Op: OpEqual, Text: "end"}) {
+		t.Errorf("common prefix and suffix should be kept, got %v ... %v", edits[0], edits[len(edits)-1])
+	}
+	for _, e := range edits[1 : MaxTokens+1] {
+		if e.Op != OpDelete {
+			t.Fatalf("expected the whole block to be deleted, got %v", e)
+		}
+	}
+	for _, e := range edits[MaxTokens+1 : 2*MaxTokens+1] {
+		if e.Op != OpInsert {
+			t.Fatalf("expected the whole block to be inserted, got %v", e)
+		}
+	}
+}
+
+// lcsLength 动态规划求最长公共子序列长度
+func lcsLength(a, b []string) int {
+	dp := make([][]int, len(a)+1)
+	for i := range dp {
+		dp[i] = make([]int, len(b)+1)
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			if a[i-1] == b[j-1] {
+				dp[i][j] = dp[i-1][j-1] + 1
+			} else {
+				dp[i][j] = max(dp[i-1][j], dp[i][j-1])
+			}
+		}
+	}
+
+	return dp[len(a)][len(b)]
+}
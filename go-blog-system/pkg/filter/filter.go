@@ -0,0 +1,327 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+const (
+	// MaxLength 表达式最大长度
+	MaxLength = 500
+	// MaxConditions 表达式中最多包含的条件数
+	MaxConditions = 20
+	// maxDepth 括号和NOT的最大嵌套层数
+	maxDepth = 10
+)
+
+// ErrSyntax 过滤表达式语法错误
+var ErrSyntax = errors.New("syntax error")
+
+// Operator 比较运算符
+type Operator string
+
+const (
+	OpEq       Operator = "="
+	OpNe       Operator = "!="
+	OpGt       Operator = ">"
+	OpGe       Operator = ">="
+	OpLt       Operator = "<"
+	OpLe       Operator = "<="
+	OpContains Operator = ":" // field:value，由字段决定是等于还是包含
+)
+
+// Expr 过滤表达式节点：*And、*Or、*Not或*Condition
+type Expr interface {
+	expr()
+}
+
+// And 两个条件同时成立
+type And struct {
+	Left, Right Expr
+}
+
+// Or 任一条件成立
+type Or struct {
+	Left, Right Expr
+}
+
+// Not 条件不成立
+type Not struct {
+	X Expr
+}
+
+// Condition 单个比较条件
+type Condition struct {
+	Field string
+	Op    Operator
+	Value string
+	Pos   int // 条件在表达式中的位置（字符偏移），用于错误提示
+}
+
+func (*And) expr()       {}
+func (*Or) expr()        {}
+func (*Not) expr()       {}
+func (*Condition) expr() {}
+
+// Parse 解析过滤表达式，语法：
+//
+//	expr       = or
+//	or         = and { "OR" and }
+//	and        = unary { ["AND"] unary }      相邻的条件之间省略AND
+//	unary      = "NOT" unary | "(" expr ")" | condition
+//	condition  = field op value
+//	op         = "=" | "!=" | ">" | ">=" | "<" | "<=" | ":"
+//	value      = 带双引号的字符串 | 不含空白和括号的单词
+//
+// 关键字不区分大小写，字段名由小写字母、数字和下划线组成。解析器只构造语法树，
+// 字段是否存在、运算符和值是否合法由调用方检查
+func Parse(s string) (Expr, error) {
+	if len([]rune(s)) > MaxLength {
+		return nil, fmt.Errorf("%w: longer than %d characters", ErrSyntax, MaxLength)
+	}
+
+	p := &parser{src: []rune(s)}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpace()
+	if !p.eof() {
+		return nil, p.errorf("unexpected %q", string(p.src[p.pos]))
+	}
+
+	return expr, nil
+}
+
+// Fields 返回表达式中出现的字段名（按出现顺序，可能重复）
+func Fields(e Expr) []string {
+	switch e := e.(type) {
+	case *And:
+		return append(Fields(e.Left), Fields(e.Right)...)
+	case *Or:
+		return append(Fields(e.Left), Fields(e.Right)...)
+	case *Not:
+		return Fields(e.X)
+	case *Condition:
+		return []string{e.Field}
+	}
+
+	return nil
+}
+
+// parser 递归下降解析器
+type parser struct {
+	src        []rune
+	pos        int
+	depth      int
+	conditions int
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.keyword("OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Or{Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if !p.keyword("AND") {
+			// 相邻的条件隐式以AND连接
+			p.skipSpace()
+			if p.eof() || p.peek() == ')' || p.peekKeyword("OR") {
+				return left, nil
+			}
+		}
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &And{Left: left, Right: right}
+	}
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > maxDepth {
+		return nil, p.errorf("nested more than %d levels", maxDepth)
+	}
+
+	if p.keyword("NOT") {
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{X: x}, nil
+	}
+
+	p.skipSpace()
+	if p.peek() == '(' {
+		p.pos++
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		p.skipSpace()
+		if p.peek() != ')' {
+			return nil, p.errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return expr, nil
+	}
+
+	return p.parseCondition()
+}
+
+func (p *parser) parseCondition() (Expr, error) {
+	p.skipSpace()
+	start := p.pos
+
+	for !p.eof() && isFieldRune(p.peek()) {
+		p.pos++
+	}
+	if p.pos == start {
+		if p.eof() {
+			return nil, p.errorf("unexpected end of expression")
+		}
+		return nil, p.errorf("expected field name")
+	}
+	field := string(p.src[start:p.pos])
+
+	p.skipSpace()
+	op, ok := p.operator()
+	if !ok {
+		return nil, p.errorf("expected operator after %q", field)
+	}
+
+	p.skipSpace()
+	value, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+
+	p.conditions++
+	if p.conditions > MaxConditions {
+		return nil, fmt.Errorf("%w: more than %d conditions", ErrSyntax, MaxConditions)
+	}
+
+	return &Condition{Field: field, Op: op, Value: value, Pos: start}, nil
+}
+
+// operator 读取比较运算符，两个字符的运算符优先
+func (p *parser) operator() (Operator, bool) {
+	for _, op := range []Operator{OpNe, OpGe, OpLe, OpEq, OpGt, OpLt, OpContains} {
+		if p.hasPrefix(string(op)) {
+			p.pos += len([]rune(string(op)))
+			return op, true
+		}
+	}
+
+	return "", false
+}
+
+// value 读取带引号的字符串或单词
+func (p *parser) value() (string, error) {
+	if p.peek() == '"' {
+		p.pos++
+
+		var b strings.Builder
+		for !p.eof() {
+			r := p.src[p.pos]
+			p.pos++
+			switch {
+			case r == '"':
+				return b.String(), nil
+			case r == '\\' && !p.eof():
+				b.WriteRune(p.src[p.pos])
+				p.pos++
+			default:
+				b.WriteRune(r)
+			}
+		}
+		return "", p.errorf("unterminated string")
+	}
+
+	start := p.pos
+	for !p.eof() && !unicode.IsSpace(p.peek()) && p.peek() != '(' && p.peek() != ')' {
+		p.pos++
+	}
+	if p.pos == start {
+		return "", p.errorf("expected value")
+	}
+
+	return string(p.src[start:p.pos]), nil
+}
+
+// keyword 跳过空白后读取关键字，关键字后必须是空白、括号或表达式结尾
+func (p *parser) keyword(kw string) bool {
+	p.skipSpace()
+	if !p.peekKeyword(kw) {
+		return false
+	}
+
+	p.pos += len(kw)
+	return true
+}
+
+// peekKeyword 当前位置是否为关键字
+func (p *parser) peekKeyword(kw string) bool {
+	end := p.pos + len(kw)
+	if end > len(p.src) || !strings.EqualFold(string(p.src[p.pos:end]), kw) {
+		return false
+	}
+
+	return end == len(p.src) || unicode.IsSpace(p.src[end]) || p.src[end] == '('
+}
+
+func (p *parser) hasPrefix(s string) bool {
+	return strings.HasPrefix(string(p.src[p.pos:]), s)
+}
+
+func (p *parser) skipSpace() {
+	for !p.eof() && unicode.IsSpace(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *parser) peek() rune {
+	if p.eof() {
+		return 0
+	}
+
+	return p.src[p.pos]
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s at position %d", ErrSyntax, fmt.Sprintf(format, args...), p.pos)
+}
+
+// isFieldRune 字段名允许的字符
+func isFieldRune(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_'
+}
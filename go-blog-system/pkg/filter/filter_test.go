@@ -0,0 +1,141 @@
+package filter
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// format 将语法树还原为带完整括号的字符串，便于比较结构
+func format(e Expr) string {
+	switch e := e.(type) {
+	case *And:
+		return "(" + format(e.Left) + " AND " + format(e.Right) + ")"
+	case *Or:
+		return "(" + format(e.Left) + " OR " + format(e.Right) + ")"
+	case *Not:
+		return "NOT " + format(e.X)
+	case *Condition:
+		return e.Field + string(e.Op) + e.Value
+	}
+
+	return "?"
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "single condition", input: "view_count>10", want: "view_count>10"},
+		{name: "spaces around operator", input: "view_count >= 10", want: "view_count>=10"},
+		{name: "all operators", input: "a=1 b!=2 c>3 d>=4 e<5 f<=6 g:7", want: "((((((a=1 AND b!=2) AND c>3) AND d>=4) AND e<5) AND f<=6) AND g:7)"},
+		{name: "implicit and", input: "a=1 b=2", want: "(a=1 AND b=2)"},
+		{name: "and binds tighter than or", input: "a=1 OR b=2 AND c=3", want: "(a=1 OR (b=2 AND c=3))"},
+		{name: "implicit and binds tighter than or", input: "a=1 b=2 OR c=3", want: "((a=1 AND b=2) OR c=3)"},
+		{name: "or is left associative", input: "a=1 OR b=2 OR c=3", want: "((a=1 OR b=2) OR c=3)"},
+		{name: "parentheses override precedence", input: "(a=1 OR b=2) c=3", want: "((a=1 OR b=2) AND c=3)"},
+		{name: "not binds to the next unary", input: "NOT a=1 b=2", want: "(NOT a=1 AND b=2)"},
+		{name: "not before parentheses", input: "NOT(a=1 OR b=2)", want: "NOT (a=1 OR b=2)"},
+		{name: "double not", input: "NOT NOT a=1", want: "NOT NOT a=1"},
+		{name: "keywords are case insensitive", input: "a=1 and b=2 Or not c=3", want: "((a=1 AND b=2) OR NOT c=3)"},
+		{name: "field starting with keyword", input: "notes:x order=1 android=2", want: "((notes:x AND order=1) AND android=2)"},
+		{name: "quoted value", input: `title:"hello world"`, want: "title:hello world"},
+		{name: "quoted value with escapes", input: `title:"say \"hi\" \\ bye"`, want: `title:say "hi" \ bye`},
+		{name: "quoted keyword and parentheses", input: `title:"(OR)"`, want: "title:(OR)"},
+		{name: "value ends at parenthesis", input: "(a=1)", want: "a=1"},
+		{name: "han characters", input: "title:你好", want: "title:你好"},
+		{name: "maximum depth", input: strings.Repeat("(", maxDepth-1) + "a=1" + strings.Repeat(")", maxDepth-1), want: "a=1"},
+		{name: "maximum not chain", input: strings.Repeat("NOT ", maxDepth-1) + "a=1", want: strings.Repeat("NOT ", maxDepth-1) + "a=1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) error: %v", tt.input, err)
+			}
+			if got := format(expr); got != tt.want {
+				t.Errorf("Parse(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantMsg string
+	}{
+		{name: "empty", input: "", wantMsg: "unexpected end of expression"},
+		{name: "missing operator", input: "view_count 10", wantMsg: `expected operator after "view_count"`},
+		{name: "missing value", input: "view_count>", wantMsg: "expected value"},
+		{name: "missing field", input: "=1", wantMsg: "expected field name"},
+		{name: "uppercase field", input: "Title:x", wantMsg: "expected field name"},
+		{name: "unterminated string", input: `title:"abc`, wantMsg: "unterminated string"},
+		{name: "missing closing parenthesis", input: "(a=1", wantMsg: "missing closing parenthesis"},
+		{name: "extra closing parenthesis", input: "a=1)", wantMsg: `unexpected ")"`},
+		{name: "dangling or", input: "a=1 OR", wantMsg: "unexpected end of expression"},
+		{name: "dangling and", input: "a=1 AND", wantMsg: "unexpected end of expression"},
+		{name: "dangling not", input: "NOT", wantMsg: "unexpected end of expression"},
+		{name: "too deep", input: strings.Repeat("(", maxDepth) + "a=1" + strings.Repeat(")", maxDepth), wantMsg: "nested more than 10 levels"},
+		{name: "too many nots", input: strings.Repeat("NOT ", maxDepth) + "a=1", wantMsg: "nested more than 10 levels"},
+		{name: "too many conditions", input: strings.Repeat("a=1 ", MaxConditions+1), wantMsg: "more than 20 conditions"},
+		{name: "too long", input: "title:" + strings.Repeat("x", MaxLength), wantMsg: "longer than 500 characters"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.input)
+			if err == nil {
+				t.Fatalf("Parse(%q) succeeded, want error", tt.input)
+			}
+			if !errors.Is(err, ErrSyntax) {
+				t.Errorf("Parse(%q) error %v is not ErrSyntax", tt.input, err)
+			}
+			if !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("Parse(%q) error = %q, want it to contain %q", tt.input, err, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestParseLimits(t *testing.T) {
+	if _, err := Parse(strings.TrimSpace(strings.Repeat("a=1 ", MaxConditions))); err != nil {
+		t.Errorf("%d conditions should be allowed: %v", MaxConditions, err)
+	}
+
+	if _, err := Parse("title:" + strings.Repeat("字", MaxLength-len("title:"))); err != nil {
+		t.Errorf("length is counted in characters, %d should be allowed: %v", MaxLength, err)
+	}
+}
+
+func TestConditionPos(t *testing.T) {
+	expr, err := Parse("a=1  OR  (b=2)")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	or := expr.(*Or)
+	if pos := or.Left.(*Condition).Pos; pos != 0 {
+		t.Errorf("left position = %d, want 0", pos)
+	}
+	if pos := or.Right.(*Condition).Pos; pos != 10 {
+		t.Errorf("right position = %d, want 10", pos)
+	}
+}
+
+func TestFields(t *testing.T) {
+	expr, err := Parse("status=draft (tag:go OR NOT author:bob) status=published")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"status", "tag", "author", "status"}
+	if got := Fields(expr); !reflect.DeepEqual(got, want) {
+		t.Errorf("Fields() = %v, want %v", got, want)
+	}
+}
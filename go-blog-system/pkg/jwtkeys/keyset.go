@@ -0,0 +1,278 @@
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/spf13/viper"
+)
+
+// KeyConfig 单个签名密钥配置
+type KeyConfig struct {
+	KID            string `mapstructure:"kid"`
+	Algorithm      string `mapstructure:"algorithm"`        // RS256, EdDSA
+	PrivateKeyFile string `mapstructure:"private_key_file"` // 仅当前签名密钥需要
+	PublicKeyFile  string `mapstructure:"public_key_file"`  // 已退役密钥只需公钥用于验证
+}
+
+// Key 签名密钥
+type Key struct {
+	ID         string
+	Method     jwt.SigningMethod
+	PrivateKey interface{} // 已退役的密钥为nil
+	PublicKey  interface{}
+}
+
+// KeySet 签名密钥集合：一个当前签名密钥加若干仅用于验证的密钥
+type KeySet struct {
+	active *Key
+	keys   map[string]*Key
+}
+
+// JWK JSON Web Key
+type JWK struct {
+	KTY string `json:"kty"`
+	KID string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	CRV string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewHMACKeySet 创建使用共享密钥的HS256密钥集合（开发环境使用）
+func NewHMACKeySet(secret string) *KeySet {
+	key := &Key{
+		Method:     jwt.SigningMethodHS256,
+		PrivateKey: []byte(secret),
+		PublicKey:  []byte(secret),
+	}
+
+	return &KeySet{
+		active: key,
+		keys:   map[string]*Key{"": key},
+	}
+}
+
+// NewKeySet 根据配置加载密钥集合
+func NewKeySet(activeKID string, configs []KeyConfig) (*KeySet, error) {
+	ks := &KeySet{keys: make(map[string]*Key)}
+
+	for _, config := range configs {
+		key, err := loadKey(config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load jwt key %q: %w", config.KID, err)
+		}
+		ks.keys[key.ID] = key
+	}
+
+	active, ok := ks.keys[activeKID]
+	if !ok {
+		return nil, fmt.Errorf("active jwt key %q is not configured", activeKID)
+	}
+	if active.PrivateKey == nil {
+		return nil, fmt.Errorf("active jwt key %q has no private key", activeKID)
+	}
+
+	ks.active = active
+	return ks, nil
+}
+
+// InitFromViper 从Viper配置初始化密钥集合，未配置非对称密钥时回退到HS256
+func InitFromViper() (*KeySet, error) {
+	var configs []KeyConfig
+	if err := viper.UnmarshalKey("jwt.keys", &configs); err != nil {
+		return nil, fmt.Errorf("failed to read jwt keys config: %w", err)
+	}
+
+	if len(configs) == 0 {
+		return NewHMACKeySet(viper.GetString("app.jwt_secret")), nil
+	}
+
+	return NewKeySet(viper.GetString("jwt.active_kid"), configs)
+}
+
+// IsSymmetric 是否使用共享密钥签名
+func (ks *KeySet) IsSymmetric() bool {
+	_, ok := ks.active.Method.(*jwt.SigningMethodHMAC)
+	return ok
+}
+
+// Sign 使用当前签名密钥签发令牌
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(ks.active.Method, claims)
+	if ks.active.ID != "" {
+		token.Header["kid"] = ks.active.ID
+	}
+
+	return token.SignedString(ks.active.PrivateKey)
+}
+
+// Keyfunc 根据令牌头中的kid选择验证密钥，供jwt.Parse使用
+func (ks *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	key, ok := ks.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key: %q", kid)
+	}
+
+	// 验证签名方法，防止算法混淆攻击
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+
+	return key.PublicKey, nil
+}
+
+// JWKS 返回所有非对称公钥（包括已退役但仍可验证的密钥）
+func (ks *KeySet) JWKS() JWKS {
+	jwks := JWKS{Keys: []JWK{}}
+
+	for _, key := range ks.keys {
+		switch pub := key.PublicKey.(type) {
+		case *rsa.PublicKey:
+			jwks.Keys = append(jwks.Keys, JWK{
+				KTY: "RSA",
+				KID: key.ID,
+				Use: "sig",
+				Alg: key.Method.Alg(),
+				N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			jwks.Keys = append(jwks.Keys, JWK{
+				KTY: "OKP",
+				KID: key.ID,
+				Use: "sig",
+				Alg: key.Method.Alg(),
+				CRV: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(pub),
+			})
+		}
+	}
+
+	return jwks
+}
+
+// loadKey 从PEM文件加载密钥
+func loadKey(config KeyConfig) (*Key, error) {
+	if config.KID == "" {
+		return nil, errors.New("kid is required")
+	}
+
+	key := &Key{ID: config.KID}
+
+	switch config.Algorithm {
+	case "RS256":
+		key.Method = jwt.SigningMethodRS256
+	case "EdDSA":
+		key.Method = SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("unsupported algorithm: %s", config.Algorithm)
+	}
+
+	if config.PrivateKeyFile != "" {
+		data, err := os.ReadFile(config.PrivateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := key.setPrivateKey(data); err != nil {
+			return nil, err
+		}
+		return key, nil
+	}
+
+	if config.PublicKeyFile == "" {
+		return nil, errors.New("private_key_file or public_key_file is required")
+	}
+
+	data, err := os.ReadFile(config.PublicKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := key.setPublicKey(data); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// setPrivateKey 解析私钥并推导公钥
+func (k *Key) setPrivateKey(data []byte) error {
+	if k.Method == SigningMethodEdDSA {
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return errors.New("invalid pem data")
+		}
+
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return err
+		}
+
+		privateKey, ok := parsed.(ed25519.PrivateKey)
+		if !ok {
+			return errors.New("key is not an ed25519 private key")
+		}
+
+		k.PrivateKey = privateKey
+		k.PublicKey = privateKey.Public()
+		return nil
+	}
+
+	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+	if err != nil {
+		return err
+	}
+
+	k.PrivateKey = privateKey
+	k.PublicKey = &privateKey.PublicKey
+	return nil
+}
+
+// setPublicKey 解析公钥
+func (k *Key) setPublicKey(data []byte) error {
+	if k.Method == SigningMethodEdDSA {
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return errors.New("invalid pem data")
+		}
+
+		parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return err
+		}
+
+		publicKey, ok := parsed.(ed25519.PublicKey)
+		if !ok {
+			return errors.New("key is not an ed25519 public key")
+		}
+
+		k.PublicKey = publicKey
+		return nil
+	}
+
+	publicKey, err := jwt.ParseRSAPublicKeyFromPEM(data)
+	if err != nil {
+		return err
+	}
+
+	k.PublicKey = publicKey
+	return nil
+}
@@ -0,0 +1,129 @@
+package mailer
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// Message 邮件内容
+type Message struct {
+	To      []string
+	Subject string
+	Body    string
+}
+
+// Mailer 邮件发送接口
+type Mailer interface {
+	Send(msg *Message) error
+}
+
+// Config 邮件配置
+type Config struct {
+	Driver   string
+	From     string
+	Host     string
+	Port     int
+	Username string
+	Password string
+	Outbox   string
+}
+
+// smtpMailer 通过SMTP服务器发送邮件
+type smtpMailer struct {
+	config *Config
+}
+
+// NewSMTPMailer 创建SMTP邮件发送器
+func NewSMTPMailer(config *Config) Mailer {
+	return &smtpMailer{config: config}
+}
+
+// Send 发送邮件
+func (m *smtpMailer) Send(msg *Message) error {
+	addr := fmt.Sprintf("%s:%d", m.config.Host, m.config.Port)
+
+	// 本地SMTP替身（如MailHog）通常无需认证
+	var auth smtp.Auth
+	if m.config.Username != "" {
+		auth = smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+	}
+
+	if err := smtp.SendMail(addr, auth, m.config.From, msg.To, buildMessage(m.config.From, msg)); err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+
+	return nil
+}
+
+// fileMailer 将邮件写入本地发件箱目录
+type fileMailer struct {
+	config *Config
+}
+
+// NewFileMailer 创建文件邮件发送器
+func NewFileMailer(config *Config) Mailer {
+	return &fileMailer{config: config}
+}
+
+// Send 将邮件保存为.eml文件
+func (m *fileMailer) Send(msg *Message) error {
+	if err := os.MkdirAll(m.config.Outbox, 0755); err != nil {
+		return fmt.Errorf("failed to create outbox: %w", err)
+	}
+
+	name := fmt.Sprintf("%d.eml", time.Now().UnixNano())
+	path := filepath.Join(m.config.Outbox, name)
+	if err := os.WriteFile(path, buildMessage(m.config.From, msg), 0644); err != nil {
+		return fmt.Errorf("failed to write mail: %w", err)
+	}
+
+	logrus.Debugf("Mail to %s written to %s", strings.Join(msg.To, ", "), path)
+	return nil
+}
+
+// InitFromViper 从Viper配置初始化邮件发送器
+func InitFromViper() (Mailer, error) {
+	config := &Config{
+		Driver:   viper.GetString("mailer.driver"),
+		From:     viper.GetString("mailer.from"),
+		Host:     viper.GetString("mailer.host"),
+		Port:     viper.GetInt("mailer.port"),
+		Username: viper.GetString("mailer.username"),
+		Password: viper.GetString("mailer.password"),
+		Outbox:   viper.GetString("mailer.outbox"),
+	}
+
+	switch config.Driver {
+	case "smtp":
+		return NewSMTPMailer(config), nil
+	case "file", "":
+		return NewFileMailer(config), nil
+	default:
+		return nil, fmt.Errorf("unsupported mailer driver: %s", config.Driver)
+	}
+}
+
+// buildMessage 构建RFC 5322格式的邮件
+func buildMessage(from string, msg *Message) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("From: " + from + "\r\n")
+	buf.WriteString("To: " + strings.Join(msg.To, ", ") + "\r\n")
+	buf.WriteString("Subject: " + mime.BEncoding.Encode("UTF-8", msg.Subject) + "\r\n")
+	buf.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+
+	return buf.Bytes()
+}
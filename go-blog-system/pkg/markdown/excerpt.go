@@ -0,0 +1,108 @@
+package markdown
+
+import (
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+const (
+	// ExcerptWidth 摘要的最大显示宽度，中日韩字符计2，其余字符计1
+	ExcerptWidth = 240
+	// cjkPerMinute 中日韩文字的阅读速度（字/分钟）
+	cjkPerMinute = 300
+	// wordsPerMinute 其他语言的阅读速度（词/分钟）
+	wordsPerMinute = 200
+)
+
+// nonProse 不适合出现在摘要中的块：代码块、标题和表格
+var nonProse = regexp.MustCompile(`(?is)<pre\b.*?</pre>|<h[1-6]\b.*?</h[1-6]>|<table\b.*?</table>`)
+
+// Stats 正文摘要和字数统计
+type Stats struct {
+	Excerpt     string
+	WordCount   int
+	ReadingTime int // 分钟
+}
+
+// Summarize 根据渲染后的HTML生成摘要并统计字数和阅读时间。
+// 摘要取自正文段落（跳过代码块、标题和表格），字数中每个中日韩字符计为一个词
+func Summarize(html string) Stats {
+	text := PlainText(html)
+	cjk, words := countWords(text)
+
+	stats := Stats{
+		Excerpt:   truncate(PlainText(nonProse.ReplaceAllString(html, " ")), ExcerptWidth),
+		WordCount: cjk + words,
+	}
+	if stats.WordCount > 0 {
+		minutes := float64(cjk)/cjkPerMinute + float64(words)/wordsPerMinute
+		stats.ReadingTime = int(math.Max(1, math.Ceil(minutes)))
+	}
+
+	return stats
+}
+
+// countWords 分别统计中日韩字符数和其他语言的单词数
+func countWords(text string) (cjk, words int) {
+	inWord := false
+	for _, r := range text {
+		switch {
+		case isCJK(r):
+			cjk++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if !inWord {
+				words++
+			}
+			inWord = true
+		case r == '\'' || r == '-':
+			// 单词内的撇号和连字符（don't、well-known）不拆分单词
+		default:
+			inWord = false
+		}
+	}
+
+	return cjk, words
+}
+
+// truncate 按显示宽度截断文本：不在单词中间截断，中日韩字符之间可以截断，截断时追加省略号
+func truncate(text string, width int) string {
+	runes := []rune(text)
+
+	used, cut := 0, len(runes)
+	for i, r := range runes {
+		w := 1
+		if isCJK(r) {
+			w = 2
+		}
+		if used+w > width {
+			cut = i
+			break
+		}
+		used += w
+	}
+	if cut == len(runes) {
+		return text
+	}
+
+	// 截断点落在单词中间时回退到单词开头
+	end := cut
+	for end > 0 && !isCJK(runes[end-1]) && !isCJK(runes[end]) && !unicode.IsSpace(runes[end-1]) && !unicode.IsSpace(runes[end]) {
+		end--
+	}
+	if end == 0 {
+		// 单个超长的单词只能直接截断
+		end = cut
+	}
+
+	return strings.TrimRightFunc(string(runes[:end]), func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	}) + "…"
+}
+
+// isCJK 是否为中日韩文字
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
@@ -0,0 +1,195 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/duanyu/go-blog-system/pkg/slug"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	gmhtml "github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+)
+
+// Version 渲染规则版本，修改渲染、过滤或摘要规则时递增，已缓存的HTML和摘要会重新生成
+const Version = 2
+
+// Heading 目录中的标题
+type Heading struct {
+	Level int    `json:"level"`
+	Text  string `json:"text"`
+	ID    string `json:"id"`
+}
+
+// Result 渲染结果
+type Result struct {
+	HTML string
+	TOC  []Heading
+}
+
+var (
+	// md 支持GFM（表格、删除线、任务列表、自动链接）并为标题生成锚点；
+	// 保留内嵌的原始HTML，输出统一经过policy过滤
+	md = goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+		goldmark.WithRendererOptions(gmhtml.WithUnsafe()),
+	)
+
+	// policy 在UGC策略的基础上保留代码块语言类名、标题锚点和任务列表复选框
+	policy = newPolicy()
+
+	// stripPolicy 去除全部标签，用于提取纯文本
+	stripPolicy = bluemonday.StrictPolicy()
+
+	// blockTag 块级元素和换行标签，提取纯文本时在其前面补充空白
+	blockTag = regexp.MustCompile(`(?i)</?(?:p|div|h[1-6]|li|ul|ol|blockquote|pre|table|tr|td|th|br|hr)\b`)
+)
+
+// newPolicy 创建HTML过滤策略
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+	p.AllowAttrs("id").Matching(regexp.MustCompile(`^[\w-]+$`)).OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+	return p
+}
+
+// Render 将Markdown渲染为经过过滤的HTML，并提取标题生成目录
+func Render(source string) (*Result, error) {
+	src := []byte(source)
+	ctx := parser.NewContext(parser.WithIDs(newHeadingIDs()))
+	doc := md.Parser().Parse(text.NewReader(src), parser.WithContext(ctx))
+
+	var buf bytes.Buffer
+	if err := md.Renderer().Render(&buf, src, doc); err != nil {
+		return nil, fmt.Errorf("failed to render markdown: %w", err)
+	}
+
+	return &Result{
+		HTML: policy.Sanitize(buf.String()),
+		TOC:  collectHeadings(doc, src),
+	}, nil
+}
+
+// Sanitize 过滤用户提交的HTML
+func Sanitize(source string) string {
+	return policy.Sanitize(source)
+}
+
+// RenderPlain 将纯文本转义为HTML，空行分段，单个换行转为<br>
+func RenderPlain(source string) string {
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+
+	var buf strings.Builder
+	for _, para := range strings.Split(source, "\n\n") {
+		para = strings.TrimSpace(para)
+		if para == "" {
+			continue
+		}
+
+		buf.WriteString("<p>")
+		buf.WriteString(strings.ReplaceAll(html.EscapeString(para), "\n", "<br>\n"))
+		buf.WriteString("</p>\n")
+	}
+
+	return buf.String()
+}
+
+// PlainText 提取HTML中的纯文本，块级元素之间以空格分隔，连续空白合并为一个空格
+func PlainText(source string) string {
+	text := stripPolicy.Sanitize(blockTag.ReplaceAllString(source, " $0"))
+	return strings.Join(strings.Fields(html.UnescapeString(text)), " ")
+}
+
+// collectHeadings 按文档顺序收集标题
+func collectHeadings(doc ast.Node, source []byte) []Heading {
+	var headings []Heading
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		heading, ok := n.(*ast.Heading)
+		if !ok || !entering {
+			return ast.WalkContinue, nil
+		}
+
+		id, _ := heading.AttributeString("id")
+		idBytes, _ := id.([]byte)
+		headings = append(headings, Heading{
+			Level: heading.Level,
+			Text:  headingText(heading, source),
+			ID:    string(idBytes),
+		})
+
+		return ast.WalkSkipChildren, nil
+	})
+
+	return headings
+}
+
+// headingText 提取标题的纯文本
+func headingText(n ast.Node, source []byte) string {
+	var buf bytes.Buffer
+	_ = ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch t := c.(type) {
+		case *ast.Text:
+			buf.Write(t.Segment.Value(source))
+			if t.SoftLineBreak() {
+				buf.WriteByte(' ')
+			}
+		case *ast.String:
+			buf.Write(t.Value)
+		case *ast.CodeSpan:
+			for child := t.FirstChild(); child != nil; child = child.NextSibling() {
+				if s, ok := child.(*ast.Text); ok {
+					buf.Write(s.Segment.Value(source))
+				}
+			}
+			return ast.WalkSkipChildren, nil
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	return strings.TrimSpace(buf.String())
+}
+
+// headingIDs 使用slug规则（中文转拼音）生成标题锚点，重复时追加数字后缀
+type headingIDs struct {
+	used map[string]bool
+}
+
+// newHeadingIDs 创建标题锚点生成器
+func newHeadingIDs() parser.IDs {
+	return &headingIDs{used: make(map[string]bool)}
+}
+
+// Generate 生成唯一的锚点
+func (h *headingIDs) Generate(value []byte, kind ast.NodeKind) []byte {
+	base := slug.Make(string(value))
+	if base == "" {
+		base = "heading"
+	}
+
+	id := base
+	for n := 1; h.used[id]; n++ {
+		id = fmt.Sprintf("%s-%d", base, n)
+	}
+	h.used[id] = true
+
+	return []byte(id)
+}
+
+// Put 记录已使用的锚点
+func (h *headingIDs) Put(value []byte) {
+	h.used[string(value)] = true
+}
@@ -0,0 +1,182 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxTerms 单次搜索最多使用的关键词数
+const MaxTerms = 10
+
+// NgramTokenSize MySQL ngram分词的词元长度（ngram_token_size，默认为2）
+const NgramTokenSize = 2
+
+// booleanOperators MySQL布尔全文检索中有特殊含义的字符
+const booleanOperators = `+-<>()~*"@`
+
+// Terms 将搜索语句按空白拆分为关键词，去除布尔检索运算符，忽略重复的关键词
+func Terms(q string) []string {
+	var terms []string
+	seen := make(map[string]bool)
+
+	for _, field := range strings.Fields(q) {
+		term := strings.Map(func(r rune) rune {
+			if strings.ContainsRune(booleanOperators, r) {
+				return -1
+			}
+			return r
+		}, field)
+
+		key := strings.ToLower(term)
+		if term == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		terms = append(terms, term)
+		if len(terms) == MaxTerms {
+			break
+		}
+	}
+
+	return terms
+}
+
+// BooleanQuery 生成布尔模式的全文检索语句：每个关键词作为短语且必须全部出现。
+// 使用ngram分词时短语按相邻字符序列匹配，适用于不以空格分词的中文；
+// 短于词元长度的关键词（如单个汉字）无法组成词元，改用前缀匹配
+func BooleanQuery(terms []string) string {
+	parts := make([]string, len(terms))
+	for i, term := range terms {
+		if utf8.RuneCountInString(term) < NgramTokenSize {
+			parts[i] = "+" + term + "*"
+		} else {
+			parts[i] = `+"` + term + `"`
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// Highlight 转义文本并用<mark>标记命中的关键词（不区分大小写）
+func Highlight(text string, terms []string) string {
+	runes := []rune(text)
+	return mark(runes, matches(runes, terms))
+}
+
+// Snippet 截取第一个命中关键词附近约length个字符的片段并高亮关键词，
+// 没有命中时取文本开头，截断处以省略号表示
+func Snippet(text string, terms []string, length int) string {
+	runes := []rune(text)
+	if len(runes) <= length {
+		return Highlight(text, terms)
+	}
+
+	spans := matches(runes, terms)
+
+	// 命中位置前保留约四分之一的上下文
+	start := 0
+	if len(spans) > 0 {
+		start = spans[0].start - length/4
+	}
+	if start > len(runes)-length {
+		start = len(runes) - length
+	}
+	if start < 0 {
+		start = 0
+	}
+	end := start + length
+
+	// 只保留完整落在片段内的命中
+	var visible []span
+	for _, s := range spans {
+		if s.start >= start && s.end <= end {
+			visible = append(visible, span{s.start - start, s.end - start})
+		}
+	}
+
+	snippet := mark(runes[start:end], visible)
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(runes) {
+		snippet += "…"
+	}
+
+	return snippet
+}
+
+// span 命中区间[start, end)，以字符为单位
+type span struct {
+	start, end int
+}
+
+// matches 查找所有关键词的命中区间，按位置排序并合并重叠部分
+func matches(runes []rune, terms []string) []span {
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	// covered[i]表示第i个字符属于某个命中
+	covered := make([]bool, len(runes))
+	for _, term := range terms {
+		t := []rune(strings.ToLower(term))
+		if len(t) == 0 {
+			continue
+		}
+
+		for i := 0; i+len(t) <= len(lower); i++ {
+			if equalRunes(lower[i:i+len(t)], t) {
+				for j := i; j < i+len(t); j++ {
+					covered[j] = true
+				}
+			}
+		}
+	}
+
+	var spans []span
+	for i := 0; i < len(covered); i++ {
+		if !covered[i] {
+			continue
+		}
+
+		j := i
+		for j < len(covered) && covered[j] {
+			j++
+		}
+		spans = append(spans, span{i, j})
+		i = j
+	}
+
+	return spans
+}
+
+// mark 转义文本并为命中区间加上<mark>标签
+func mark(runes []rune, spans []span) string {
+	var buf strings.Builder
+	pos := 0
+	for _, s := range spans {
+		buf.WriteString(html.EscapeString(string(runes[pos:s.start])))
+		buf.WriteString("<mark>")
+		buf.WriteString(html.EscapeString(string(runes[s.start:s.end])))
+		buf.WriteString("</mark>")
+		pos = s.end
+	}
+	buf.WriteString(html.EscapeString(string(runes[pos:])))
+
+	return buf.String()
+}
+
+// equalRunes 比较两个字符切片是否相同
+func equalRunes(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,224 @@
+package siwe
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"golang.org/x/crypto/sha3"
+)
+
+// headerSuffix EIP-4361消息首行后缀
+const headerSuffix = " wants you to sign in with your Ethereum account:"
+
+// Message EIP-4361消息
+type Message struct {
+	Domain         string
+	Address        string
+	Statement      string
+	URI            string
+	Version        string
+	ChainID        int
+	Nonce          string
+	IssuedAt       time.Time
+	ExpirationTime *time.Time
+	NotBefore      *time.Time
+	RequestID      string
+	Resources      []string
+}
+
+// ParseMessage 解析EIP-4361消息
+func ParseMessage(raw string) (*Message, error) {
+	lines := strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n")
+	if len(lines) < 2 || !strings.HasSuffix(lines[0], headerSuffix) {
+		return nil, errors.New("invalid siwe message header")
+	}
+
+	msg := &Message{
+		Domain:  strings.TrimSuffix(lines[0], headerSuffix),
+		Address: strings.TrimSpace(lines[1]),
+	}
+	// 域名前可带协议
+	if i := strings.Index(msg.Domain, "://"); i >= 0 {
+		msg.Domain = msg.Domain[i+3:]
+	}
+	if !IsHexAddress(msg.Address) {
+		return nil, errors.New("invalid address in siwe message")
+	}
+
+	i := 2
+	for i < len(lines) && lines[i] == "" {
+		i++
+	}
+
+	// 可选的声明行
+	if i < len(lines) && !strings.HasPrefix(lines[i], "URI: ") {
+		msg.Statement = lines[i]
+		i++
+		for i < len(lines) && lines[i] == "" {
+			i++
+		}
+	}
+
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if line == "" {
+			continue
+		}
+
+		if line == "Resources:" {
+			for i++; i < len(lines); i++ {
+				if !strings.HasPrefix(lines[i], "- ") {
+					break
+				}
+				msg.Resources = append(msg.Resources, strings.TrimPrefix(lines[i], "- "))
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			return nil, fmt.Errorf("invalid siwe message line: %q", line)
+		}
+
+		if err := msg.setField(key, value); err != nil {
+			return nil, err
+		}
+	}
+
+	if msg.URI == "" || msg.Version != "1" || msg.Nonce == "" || msg.IssuedAt.IsZero() {
+		return nil, errors.New("siwe message is missing required fields")
+	}
+
+	return msg, nil
+}
+
+// setField 设置消息字段
+func (m *Message) setField(key, value string) error {
+	var err error
+
+	switch key {
+	case "URI":
+		m.URI = value
+	case "Version":
+		m.Version = value
+	case "Chain ID":
+		m.ChainID, err = strconv.Atoi(value)
+	case "Nonce":
+		if len(value) < 8 {
+			return errors.New("siwe nonce is too short")
+		}
+		m.Nonce = value
+	case "Issued At":
+		m.IssuedAt, err = time.Parse(time.RFC3339, value)
+	case "Expiration Time":
+		m.ExpirationTime, err = parseTime(value)
+	case "Not Before":
+		m.NotBefore, err = parseTime(value)
+	case "Request ID":
+		m.RequestID = value
+	default:
+		return fmt.Errorf("unknown siwe message field: %s", key)
+	}
+
+	if err != nil {
+		return fmt.Errorf("invalid siwe %s: %w", strings.ToLower(key), err)
+	}
+
+	return nil
+}
+
+// ValidAt 检查消息在指定时间是否处于有效期内
+func (m *Message) ValidAt(now time.Time) error {
+	if m.ExpirationTime != nil && now.After(*m.ExpirationTime) {
+		return errors.New("siwe message has expired")
+	}
+
+	if m.NotBefore != nil && now.Before(*m.NotBefore) {
+		return errors.New("siwe message is not yet valid")
+	}
+
+	return nil
+}
+
+// RecoverAddress 从EIP-191个人签名中恢复签名者地址（小写十六进制）
+func RecoverAddress(message, signature string) (string, error) {
+	sig, err := hex.DecodeString(strings.TrimPrefix(signature, "0x"))
+	if err != nil || len(sig) != 65 {
+		return "", errors.New("invalid signature")
+	}
+
+	// 以太坊签名格式为 R || S || V，转换为 V || R || S 的紧凑格式
+	v := sig[64]
+	if v >= 27 {
+		v -= 27
+	}
+	if v > 1 {
+		return "", errors.New("invalid signature recovery id")
+	}
+
+	compact := make([]byte, 65)
+	compact[0] = 27 + v
+	copy(compact[1:], sig[:64])
+
+	pubKey, _, err := ecdsa.RecoverCompact(compact, hashPersonalMessage(message))
+	if err != nil {
+		return "", fmt.Errorf("failed to recover signer: %w", err)
+	}
+
+	// 地址为公钥（去掉0x04前缀）Keccak-256哈希的后20字节
+	hash := keccak256(pubKey.SerializeUncompressed()[1:])
+	return "0x" + hex.EncodeToString(hash[12:]), nil
+}
+
+// IsHexAddress 检查是否为合法的以太坊地址
+func IsHexAddress(address string) bool {
+	if len(address) != 42 || !strings.HasPrefix(address, "0x") {
+		return false
+	}
+
+	_, err := hex.DecodeString(address[2:])
+	return err == nil
+}
+
+// ChecksumAddress 返回EIP-55校验和格式的地址
+func ChecksumAddress(address string) string {
+	lower := strings.ToLower(strings.TrimPrefix(address, "0x"))
+	hash := hex.EncodeToString(keccak256([]byte(lower)))
+
+	result := []byte(lower)
+	for i, c := range result {
+		if c >= 'a' && c <= 'f' && hash[i] >= '8' {
+			result[i] = c - 'a' + 'A'
+		}
+	}
+
+	return "0x" + string(result)
+}
+
+// hashPersonalMessage 计算EIP-191个人消息哈希
+func hashPersonalMessage(message string) []byte {
+	prefix := fmt.Sprintf("\x19Ethereum Signed Message:\n%d", len(message))
+	return keccak256([]byte(prefix + message))
+}
+
+// keccak256 计算Keccak-256哈希
+func keccak256(data []byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// parseTime 解析RFC 3339时间
+func parseTime(value string) (*time.Time, error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
@@ -0,0 +1,63 @@
+package slug
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/mozillazg/go-pinyin"
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxLength slug最大长度
+const MaxLength = 80
+
+// pinyinArgs 不带声调、不启用多音字的拼音参数
+var pinyinArgs = pinyin.NewArgs()
+
+// Make 根据文本生成URL友好的slug：汉字转写为拼音，拉丁字母去除变音符号并转为小写，
+// 其余字符作为分隔符，单词之间用连字符连接。无法转写出任何字符时返回空字符串
+func Make(s string) string {
+	var words []string
+	var current strings.Builder
+
+	flush := func() {
+		if current.Len() > 0 {
+			words = append(words, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, r := range norm.NFD.String(s) {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			// 每个汉字的拼音作为一个独立单词
+			flush()
+			if py := pinyin.SinglePinyin(r, pinyinArgs); len(py) > 0 {
+				words = append(words, py[0])
+			}
+		case unicode.Is(unicode.Mn, r):
+			// 去除分解后的变音符号（é -> e）
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			current.WriteRune(unicode.ToLower(r))
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	return truncate(strings.Join(words, "-"), MaxLength)
+}
+
+// truncate 按单词边界截断slug
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+
+	s = s[:max]
+	if i := strings.LastIndexByte(s, '-'); i > 0 {
+		s = s[:i]
+	}
+
+	return strings.Trim(s, "-")
+}
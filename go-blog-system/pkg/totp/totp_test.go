@@ -0,0 +1,113 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret RFC 6238 附录B中SHA1测试向量使用的密钥 "12345678901234567890"
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// TestGenerateCodeRFC6238 RFC 6238 附录B的8位验证码取后6位
+func TestGenerateCodeRFC6238(t *testing.T) {
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+
+	for _, tt := range tests {
+		got, err := GenerateCode(rfcSecret, Step(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("GenerateCode at %d: %v", tt.unix, err)
+		}
+		if want := tt.want[len(tt.want)-Digits:]; got != want {
+			t.Errorf("GenerateCode at %d = %s, want %s", tt.unix, got, want)
+		}
+	}
+}
+
+func TestGenerateCodeLowercaseSecret(t *testing.T) {
+	upper, err := GenerateCode(rfcSecret, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lower, err := GenerateCode(strings.ToLower(rfcSecret), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if upper != lower {
+		t.Errorf("secret should be case-insensitive: %s != %s", upper, lower)
+	}
+}
+
+func TestGenerateCodeInvalidSecret(t *testing.T) {
+	if _, err := GenerateCode("not base32!", 1); err == nil {
+		t.Error("expected an error for an invalid secret")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	step := Step(now)
+
+	code := func(s int64) string {
+		c, err := GenerateCode(rfcSecret, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	tests := []struct {
+		name     string
+		code     string
+		wantStep int64
+		wantOK   bool
+	}{
+		{"current step", code(step), step, true},
+		{"previous step", code(step - 1), step - 1, true},
+		{"next step", code(step + 1), step + 1, true},
+		{"surrounding spaces", " " + code(step) + " ", step, true},
+		{"outside skew", code(step - 2), 0, false},
+		{"wrong length", code(step)[:Digits-1], 0, false},
+		{"wrong code", "000000", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 极小概率下其他时间步的验证码恰好相同，跳过该情况
+			if tt.name == "wrong code" && (tt.code == code(step-1) || tt.code == code(step) || tt.code == code(step+1)) {
+				t.Skip("wrong code collides with a valid code")
+			}
+
+			gotStep, ok := Validate(rfcSecret, tt.code, now)
+			if ok != tt.wantOK || gotStep != tt.wantStep {
+				t.Errorf("Validate(%q) = (%d, %v), want (%d, %v)", tt.code, gotStep, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(secret) != 32 {
+		t.Errorf("secret length = %d, want 32", len(secret))
+	}
+	if _, err := GenerateCode(secret, 1); err != nil {
+		t.Errorf("generated secret is not usable: %v", err)
+	}
+}